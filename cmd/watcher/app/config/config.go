@@ -3,36 +3,129 @@ package config
 import (
 	"errors"
 	"fmt"
+	"reflect"
 
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
 	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
 )
 
+// Engines computing the leader schedule of the pools.
+const (
+	SlotLeaderEngineCncli  = "cncli"
+	SlotLeaderEngineNative = "native"
+)
+
+// Chain data providers the watcher can query.
+const (
+	ProviderBlockfrost = "blockfrost"
+	ProviderKoios      = "koios"
+)
+
 type Config struct {
+	Provider             string               `mapstructure:"provider"`
 	Pools                pools.Pools          `mapstructure:"pools"`
 	HTTP                 HTTPConfig           `mapstructure:"http"`
-	Network              string               `mapstructure:"network"`
+	Network              NetworkConfig        `mapstructure:"network"`
 	Cardano              CardanoConfig        `mapstructure:"cardano"`
 	Database             DatabaseConfig       `mapstructure:"database"`
 	Blockfrost           BlockFrostConfig     `mapstructure:"blockfrost"`
+	Koios                KoiosConfig          `mapstructure:"koios"`
+	Providers            []ProviderConfig     `mapstructure:"providers"`
 	BlockWatcherConfig   BlockWatcherConfig   `mapstructure:"block-watcher"`
 	PoolWatcherConfig    PoolWatcherConfig    `mapstructure:"pool-watcher"`
 	NetworkWatcherConfig NetworkWatcherConfig `mapstructure:"network-watcher"`
 	StatusWatcherConfig  StatusWatcherConfig  `mapstructure:"status-watcher"`
+	KESWatcherConfig     KESWatcherConfig     `mapstructure:"kes-watcher"`
 	SlotLeaderConfig     SlotLeaderConfig     `mapstructure:"slot-leader"`
 }
 
+// NetworkConfig selects the Cardano network. Name is either one of the public
+// networks or the name of a custom network defined by its testnet magic and
+// the directory holding its genesis files.
+type NetworkConfig struct {
+	Name         string `mapstructure:"name"`
+	TestnetMagic int    `mapstructure:"testnet-magic"`
+	ConfigDir    string `mapstructure:"config-dir"`
+}
+
+// CardanoNetwork returns the network selected by the configuration.
+func (c NetworkConfig) CardanoNetwork() cardano.Network {
+	if network, ok := cardano.KnownNetwork(c.Name); ok {
+		return network
+	}
+	return cardano.Network{Name: c.Name, Magic: c.TestnetMagic}
+}
+
+// NetworkDecodeHook allows the network to be configured with its name only, e.g. `network: mainnet`.
+// The name is merged into base, which holds the testnet magic and the config
+// directory set by the flags or the environment since they are not part of the scalar.
+func NetworkDecodeHook(base NetworkConfig) mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(NetworkConfig{}) {
+			return data, nil
+		}
+		network := base
+		network.Name = data.(string) //nolint:forcetypeassert
+		return network, nil
+	}
+}
+
+// Validate ensures that the network is either a public network or a fully defined custom network.
+func (c NetworkConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("network name is required")
+	}
+
+	if network, ok := cardano.KnownNetwork(c.Name); ok {
+		if c.TestnetMagic != 0 && c.TestnetMagic != network.Magic {
+			return fmt.Errorf("invalid testnet-magic %d for network %s, expected %d", c.TestnetMagic, c.Name, network.Magic)
+		}
+		return nil
+	}
+
+	if c.TestnetMagic <= 0 {
+		return fmt.Errorf("testnet-magic is required for custom network %s", c.Name)
+	}
+	if c.ConfigDir == "" {
+		return fmt.Errorf("config-dir with the genesis files is required for custom network %s", c.Name)
+	}
+	return nil
+}
+
+// SlotLeaderConfig selects how the leader schedule is computed: with the
+// cncli leaderlog command or in process with the native Praos leader check.
+//
+// The leader slots are encrypted in the database when an encryption key is
+// provided, either directly (base64) or through a file holding it.
 type SlotLeaderConfig struct {
-	Concurrency int `mapstructure:"concurrency"`
+	Engine            string `mapstructure:"engine"`
+	Concurrency       int    `mapstructure:"concurrency"`
+	EncryptionKey     string `mapstructure:"encryption-key"`
+	EncryptionKeyFile string `mapstructure:"encryption-key-file"`
+	// VRFKeyCheckInterval is the interval, in seconds, between two verifications
+	// of the VRF keys of the pools against their on-chain registration.
+	VRFKeyCheckInterval int `mapstructure:"vrf-key-check-interval"`
 }
 
 type BlockWatcherConfig struct {
-	Enabled         bool `mapstructure:"enabled"`
-	RefreshInterval int  `mapstructure:"refresh-interval"`
+	Enabled           bool   `mapstructure:"enabled"`
+	RefreshInterval   int    `mapstructure:"refresh-interval"`
+	ConfirmationDepth int    `mapstructure:"confirmation-depth"`
+	ConfirmationUnit  string `mapstructure:"confirmation-unit"`
+	RecheckWindow     int    `mapstructure:"recheck-window"`
+	// ExposeNextSlotLeader publishes the next leader slot of the pools instead
+	// of the number of leader slots remaining in the epoch.
+	ExposeNextSlotLeader bool `mapstructure:"expose-next-slot-leader"`
 }
 
 type PoolWatcherConfig struct {
 	Enabled         bool `mapstructure:"enabled"`
 	RefreshInterval int  `mapstructure:"refresh-interval"`
+	// RelayProbeInterval is the interval, in seconds, at which the relays of the pools are dialed (0 = disabled)
+	RelayProbeInterval int `mapstructure:"relay-probe-interval"`
+	// PledgeWarningThreshold is the margin over the declared pledge, in percent, under which the live pledge is reported
+	PledgeWarningThreshold float64 `mapstructure:"pledge-warning-threshold"`
 }
 
 type NetworkWatcherConfig struct {
@@ -40,6 +133,11 @@ type NetworkWatcherConfig struct {
 	RefreshInterval int  `mapstructure:"refresh-interval"`
 }
 
+type KESWatcherConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	RefreshInterval int  `mapstructure:"refresh-interval"`
+}
+
 type StatusWatcherConfig struct {
 	RefreshInterval int `mapstructure:"refresh-interval"`
 }
@@ -50,10 +148,60 @@ type HTTPConfig struct {
 }
 
 type BlockFrostConfig struct {
-	ProjectID   string `mapstructure:"project-id"`
-	Endpoint    string `mapstructure:"endpoint"`
-	MaxRoutines int    `mapstructure:"max-routines"`
-	Timeout     int    `mapstructure:"timeout"`
+	ProjectID   string          `mapstructure:"project-id"`
+	Endpoint    string          `mapstructure:"endpoint"`
+	MaxRoutines int             `mapstructure:"max-routines"`
+	Timeout     int             `mapstructure:"timeout"`
+	RateLimit   RateLimitConfig `mapstructure:"rate-limit"`
+}
+
+type KoiosConfig struct {
+	Endpoint  string          `mapstructure:"endpoint"`
+	APIKey    string          `mapstructure:"api-key"`
+	Timeout   int             `mapstructure:"timeout"`
+	RateLimit RateLimitConfig `mapstructure:"rate-limit"`
+}
+
+// RateLimitConfig bounds the request rate sent to a chain data provider and
+// the number of times a rate limited or failed request is retried.
+// Zero values use the defaults and a negative max-retries disables retries.
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `mapstructure:"requests-per-second"`
+	Burst             int     `mapstructure:"burst"`
+	MaxRetries        int     `mapstructure:"max-retries"`
+}
+
+// ProviderConfig defines one of the chain data providers the watcher fails
+// over between, in priority order. Type is either blockfrost or koios.
+type ProviderConfig struct {
+	Name        string          `mapstructure:"name"`
+	Type        string          `mapstructure:"type"`
+	Endpoint    string          `mapstructure:"endpoint"`
+	ProjectID   string          `mapstructure:"project-id"`
+	APIKey      string          `mapstructure:"api-key"`
+	MaxRoutines int             `mapstructure:"max-routines"`
+	Timeout     int             `mapstructure:"timeout"`
+	RateLimit   RateLimitConfig `mapstructure:"rate-limit"`
+}
+
+// Validate ensures that the provider defines what its type requires.
+func (c ProviderConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("name is required for all providers")
+	}
+	switch c.Type {
+	case ProviderBlockfrost:
+		if c.ProjectID == "" || c.Endpoint == "" {
+			return fmt.Errorf("project-id and endpoint are required for blockfrost provider %s", c.Name)
+		}
+	case ProviderKoios:
+		if c.Endpoint == "" {
+			return fmt.Errorf("endpoint is required for koios provider %s", c.Name)
+		}
+	default:
+		return fmt.Errorf("invalid type %s for provider %s. Type must be either %s or %s", c.Type, c.Name, ProviderBlockfrost, ProviderKoios)
+	}
+	return nil
 }
 
 type CardanoConfig struct {
@@ -77,11 +225,17 @@ type DatabaseConfig struct {
 	Path string `mapstructure:"path"`
 }
 
+// GenesisDir returns the directory holding the byron and shelley genesis files of the network.
+func (c *Config) GenesisDir() string {
+	if c.Network.ConfigDir != "" {
+		return c.Network.ConfigDir
+	}
+	return c.Cardano.ConfigDir
+}
+
 func (c *Config) Validate() error {
-	switch c.Network {
-	case "mainnet", "preprod":
-	default:
-		return fmt.Errorf("invalid network: %s. Network must be either %s or %s", c.Network, "mainnet", "preprod")
+	if err := c.Network.Validate(); err != nil {
+		return err
 	}
 
 	if len(c.Pools) == 0 {
@@ -107,8 +261,56 @@ func (c *Config) Validate() error {
 		return errors.New("at least one active pool must be defined")
 	}
 
-	if c.Blockfrost.ProjectID == "" || c.Blockfrost.Endpoint == "" {
-		return errors.New("blockfrost project-id and endpoint are required")
+	names := make(map[string]struct{}, len(c.Providers))
+	for _, provider := range c.Providers {
+		if err := provider.Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[provider.Name]; ok {
+			return fmt.Errorf("provider %s is defined more than once", provider.Name)
+		}
+		names[provider.Name] = struct{}{}
+	}
+
+	// The blockfrost and koios sections are only used when no providers are listed
+	switch {
+	case len(c.Providers) > 0:
+	case c.Provider == ProviderBlockfrost:
+		if c.Blockfrost.ProjectID == "" || c.Blockfrost.Endpoint == "" {
+			return errors.New("blockfrost project-id and endpoint are required")
+		}
+	case c.Provider == ProviderKoios:
+		if c.Koios.Endpoint == "" {
+			return errors.New("koios endpoint is required")
+		}
+	default:
+		return fmt.Errorf("invalid provider: %s. Provider must be either %s or %s", c.Provider, ProviderBlockfrost, ProviderKoios)
+	}
+
+	switch c.SlotLeaderConfig.Engine {
+	case SlotLeaderEngineCncli, SlotLeaderEngineNative:
+	default:
+		return fmt.Errorf("invalid slot-leader engine: %s. Engine must be either %s or %s", c.SlotLeaderConfig.Engine, SlotLeaderEngineCncli, SlotLeaderEngineNative)
+	}
+
+	if c.SlotLeaderConfig.VRFKeyCheckInterval <= 0 {
+		return errors.New("slot-leader vrf-key-check-interval must be positive")
+	}
+
+	if c.SlotLeaderConfig.EncryptionKey != "" && c.SlotLeaderConfig.EncryptionKeyFile != "" {
+		return errors.New("slot-leader encryption-key and encryption-key-file are mutually exclusive")
+	}
+
+	switch c.BlockWatcherConfig.ConfirmationUnit {
+	case "slots", "blocks":
+	default:
+		return fmt.Errorf("invalid block-watcher confirmation-unit: %s. Unit must be either %s or %s", c.BlockWatcherConfig.ConfirmationUnit, "slots", "blocks")
+	}
+	if c.BlockWatcherConfig.ConfirmationDepth < 0 || c.BlockWatcherConfig.RecheckWindow < 0 {
+		return errors.New("block-watcher confirmation-depth and recheck-window must not be negative")
+	}
+	if c.PoolWatcherConfig.PledgeWarningThreshold < 0 {
+		return errors.New("pool-watcher pledge-warning-threshold must not be negative")
 	}
 
 	if len(c.Cardano.Nodes) == 0 {
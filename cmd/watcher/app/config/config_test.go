@@ -0,0 +1,95 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// loadNetwork reads the network of the given configuration with the network
+// flags bound the same way as the watcher command.
+func loadNetwork(t *testing.T, content string, args []string) NetworkConfig {
+	t.Helper()
+
+	flags := pflag.NewFlagSet("watcher", pflag.ContinueOnError)
+	flags.String("network", "preprod", "")
+	flags.Int("network-testnet-magic", 0, "")
+	flags.String("network-config-dir", "", "")
+	require.NoError(t, flags.Parse(args))
+
+	v := viper.New()
+	require.NoError(t, v.BindPFlag("network.name", flags.Lookup("network")))
+	require.NoError(t, v.BindPFlag("network.testnet-magic", flags.Lookup("network-testnet-magic")))
+	require.NoError(t, v.BindPFlag("network.config-dir", flags.Lookup("network-config-dir")))
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	v.AutomaticEnv()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(content)))
+
+	cfg := &Config{}
+	decodeHook := mapstructure.ComposeDecodeHookFunc(
+		NetworkDecodeHook(NetworkConfig{
+			TestnetMagic: v.GetInt("network.testnet-magic"),
+			ConfigDir:    v.GetString("network.config-dir"),
+		}),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+	require.NoError(t, v.Unmarshal(cfg, viper.DecodeHook(decodeHook)))
+	return cfg.Network
+}
+
+func TestNetworkDecodeHook(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		args     []string
+		env      map[string]string
+		expected NetworkConfig
+	}{
+		{
+			name:     "GoodPath_ScalarPublicNetwork",
+			content:  "network: mainnet\n",
+			expected: NetworkConfig{Name: "mainnet"},
+		},
+		{
+			name:     "GoodPath_ScalarCustomNetworkWithFlags",
+			content:  "network: devnet\n",
+			args:     []string{"--network-testnet-magic", "42", "--network-config-dir", "/genesis"},
+			expected: NetworkConfig{Name: "devnet", TestnetMagic: 42, ConfigDir: "/genesis"},
+		},
+		{
+			name:     "GoodPath_ScalarCustomNetworkWithEnv",
+			content:  "network: devnet\n",
+			env:      map[string]string{"NETWORK_TESTNET_MAGIC": "7", "NETWORK_CONFIG_DIR": "/env-genesis"},
+			expected: NetworkConfig{Name: "devnet", TestnetMagic: 7, ConfigDir: "/env-genesis"},
+		},
+		{
+			name:     "GoodPath_StructCustomNetwork",
+			content:  "network:\n  name: devnet\n  testnet-magic: 42\n  config-dir: /genesis\n",
+			expected: NetworkConfig{Name: "devnet", TestnetMagic: 42, ConfigDir: "/genesis"},
+		},
+		{
+			name:     "GoodPath_StructCustomNetworkWithFlags",
+			content:  "network:\n  name: devnet\n",
+			args:     []string{"--network-testnet-magic", "42", "--network-config-dir", "/genesis"},
+			expected: NetworkConfig{Name: "devnet", TestnetMagic: 42, ConfigDir: "/genesis"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			network := loadNetwork(t, tt.content, tt.args)
+			require.Equal(t, tt.expected, network)
+			require.NoError(t, network.Validate())
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/kilnfi/cardano-validator-watcher/internal/schedule"
+	"github.com/spf13/cobra"
+)
+
+type maintenanceWindowsOptions struct {
+	count    int
+	margin   time.Duration
+	instance string
+	format   string
+}
+
+// NewMaintenanceWindowsCommand returns the command listing the largest windows
+// without leader slot of each instance, to plan the restarts of the block producers.
+func NewMaintenanceWindowsCommand() *cobra.Command {
+	opts := &maintenanceWindowsOptions{}
+	cmd := &cobra.Command{
+		Use:   "maintenance-windows",
+		Short: "find the largest windows without leader slot of each instance",
+		Long: `find the largest windows without leader slot of the pools hosted on each instance
+		in the current and the next epoch, keeping a safety margin around the leader slots.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runMaintenanceWindows(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().IntVarP(&opts.count, "count", "", schedule.DefaultWindowCount, "number of windows per instance")
+	cmd.Flags().DurationVarP(&opts.margin, "margin", "", schedule.DefaultWindowMargin, "safety margin kept before and after each leader slot")
+	cmd.Flags().StringVarP(&opts.instance, "instance", "", "", "instance to inspect (default is all the instances)")
+	cmd.Flags().StringVarP(&opts.format, "format", "", "text", "output format (text or json)")
+
+	return cmd
+}
+
+func runMaintenanceWindows(ctx context.Context, opts *maintenanceWindowsOptions) error {
+	if opts.format != "text" && opts.format != "json" {
+		return fmt.Errorf("invalid format %s. Format must be either text or json", opts.format)
+	}
+
+	selectedPools := cfg.Pools
+	if opts.instance != "" {
+		selectedPools = pools.Pools{}
+		for _, pool := range cfg.Pools {
+			if pool.Instance == opts.instance {
+				selectedPools = append(selectedPools, pool)
+			}
+		}
+		if len(selectedPools) == 0 {
+			return fmt.Errorf("instance %s is not configured", opts.instance)
+		}
+	}
+
+	slotLeaderService, timeline, location, err := openSchedules(ctx)
+	if err != nil {
+		return err
+	}
+
+	finder := schedule.NewWindowFinder(slotLeaderService, timeline, location, selectedPools)
+	windows, err := finder.Find(ctx, opts.count, opts.margin)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	for _, instance := range windows {
+		if instance.Unknown() {
+			logger.WarnContext(ctx, "maintenance windows of the instance are unknown",
+				slog.String("instance", instance.Instance),
+				slog.String("error", instance.Error),
+			)
+		}
+	}
+
+	if opts.format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(windows); err != nil {
+			return fmt.Errorf("unable to write json: %w", err)
+		}
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "INSTANCE\tSTART\tEND\tDURATION")
+	for _, instance := range windows {
+		if instance.Unknown() {
+			_, _ = fmt.Fprintf(writer, "%s\tunknown\tunknown\tunknown\n", instance.Instance)
+			continue
+		}
+		for _, window := range instance.Windows {
+			_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+				instance.Instance,
+				window.Start.Format(time.RFC3339),
+				window.End.Format(time.RFC3339),
+				window.Duration().Round(time.Second),
+			)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("unable to write maintenance windows: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
+	"github.com/kilnfi/cardano-validator-watcher/internal/database"
+	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/kilnfi/cardano-validator-watcher/internal/schedule"
+	"github.com/kilnfi/cardano-validator-watcher/internal/slotleader"
+	"github.com/kilnfi/cardano-validator-watcher/migrations"
+	"github.com/spf13/cobra"
+)
+
+type scheduleOptions struct {
+	epoch  string
+	poolID string
+	format string
+	output string
+}
+
+// NewScheduleCommand returns the command exporting the leader schedule stored
+// in the database, so operators can plan the maintenance of their nodes.
+func NewScheduleCommand() *cobra.Command {
+	opts := &scheduleOptions{}
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "export the leader schedule of the pools",
+		Long: `export the leader schedule of the pools computed by the watcher
+		as csv, json or iCalendar, with the time of each slot in the configured cardano timezone.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runSchedule(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.epoch, "epoch", "", "current", "epoch to export (current, next or an epoch number)")
+	cmd.Flags().StringVarP(&opts.poolID, "pool", "", "", "ID of the pool to export (default is all the active pools)")
+	cmd.Flags().StringVarP(&opts.format, "format", "", string(schedule.FormatCSV), "output format (csv, json or ics)")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "output file, - for stdout (default is schedule-<epoch>.<format>)")
+
+	return cmd
+}
+
+func runSchedule(ctx context.Context, opts *scheduleOptions) error {
+	format, err := schedule.ParseFormat(opts.format)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	selectedPools, err := selectPools(opts.poolID)
+	if err != nil {
+		return err
+	}
+
+	slotLeaderService, timeline, location, err := openSchedules(ctx)
+	if err != nil {
+		return err
+	}
+	exporter := schedule.NewExporter(slotLeaderService, timeline, location)
+
+	epoch, err := exporter.ResolveEpoch(opts.epoch)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	slots, missing, err := exporter.Slots(ctx, epoch, selectedPools)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	// The schedule of a pool exported alone is required, the others are skipped
+	for _, pool := range missing {
+		if opts.poolID != "" {
+			return fmt.Errorf("unable to get the schedule of pool %s in epoch %d: %w", pool.ID, epoch, slotleader.ErrScheduleNotFound)
+		}
+		logger.WarnContext(ctx, "pool skipped, its schedule of the epoch is not stored",
+			slog.String("pool_id", pool.ID),
+			slog.Int("epoch", epoch),
+		)
+	}
+
+	if opts.output == "-" {
+		return exporter.Write(os.Stdout, format, slots) //nolint:wrapcheck
+	}
+
+	output := opts.output
+	if output == "" {
+		output = fmt.Sprintf("schedule-%d.%s", epoch, format)
+	}
+	if err := writeScheduleFile(output, func(w io.Writer) error {
+		return exporter.Write(w, format, slots)
+	}); err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "leader schedule exported",
+		slog.Int("epoch", epoch),
+		slog.Int("slots", len(slots)),
+		slog.String("format", string(format)),
+		slog.String("output", output),
+	)
+	return nil
+}
+
+// openSchedules opens the schedules stored in the database maintained by the
+// watcher, with the timeline and the timezone used to convert slots to times.
+func openSchedules(ctx context.Context) (*slotleader.Service, *cardanotime.Timeline, *time.Location, error) {
+	location, err := time.LoadLocation(cfg.Cardano.Timezone)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid timezone %s: %w", cfg.Cardano.Timezone, err)
+	}
+
+	// The database is opened read-only, the schedules are only written by the watcher
+	database := database.NewDatabase(database.Options{
+		URL:          "?mode=ro&_timeout=15000&_fk=true&cache=shared",
+		Path:         cfg.Database.Path,
+		MaxOpenConns: 1,
+	})
+	if err := database.Connect(ctx); err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to connect to database: %w", err)
+	}
+	// The database is migrated by the watcher only, a schema that is behind is
+	// reported instead of being migrated under the running watcher
+	if err := database.CheckMigrations(ctx, migrations.FS); err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to read schedules, start the watcher to migrate the database first: %w", err)
+	}
+
+	timeline, err := createTimeline()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to create network timeline: %w", err)
+	}
+
+	// The schedules are only read from the database, no client is needed
+	slotLeaderService, err := createSlotLeaderService(database.DB, nil, nil, timeline, metrics.NewCollection())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return slotLeaderService, timeline, location, nil
+}
+
+// selectPools returns the pool with the given ID, or all the active pools when poolID is empty.
+func selectPools(poolID string) ([]pools.Pool, error) {
+	if poolID == "" {
+		return cfg.Pools.GetActivePools(), nil
+	}
+
+	for _, pool := range cfg.Pools {
+		if pool.ID == poolID {
+			return []pools.Pool{pool}, nil
+		}
+	}
+	return nil, fmt.Errorf("pool %s is not configured", poolID)
+}
+
+func writeScheduleFile(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("unable to create %s: %w", path, err)
+	}
+
+	if err := write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("unable to close %s: %w", path, err)
+	}
+	return nil
+}
@@ -7,19 +7,27 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/jmoiron/sqlx"
 	"github.com/kilnfi/cardano-validator-watcher/cmd/watcher/app/config"
 	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
 	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost/blockfrostapi"
+	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost/failover"
+	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost/koiosapi"
+	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost/transport"
 	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
 	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanocli"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanonative"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
 	"github.com/kilnfi/cardano-validator-watcher/internal/database"
 	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
 	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/kilnfi/cardano-validator-watcher/internal/schedule"
 	"github.com/kilnfi/cardano-validator-watcher/internal/server/http"
 	"github.com/kilnfi/cardano-validator-watcher/internal/slotleader"
 	"github.com/kilnfi/cardano-validator-watcher/internal/watcher"
@@ -77,47 +85,98 @@ func NewWatcherCommand() *cobra.Command {
 		RunE:          run,
 	}
 
-	cmd.Flags().StringVarP(&configFile, "config", "", "", "config file (default is config.yml)")
+	cmd.PersistentFlags().StringVarP(&configFile, "config", "", "", "config file (default is config.yml)")
 	cmd.Flags().StringP("log-level", "", "info", "config file (default is config.yml)")
 	cmd.Flags().StringP("http-server-host", "", http.ServerDefaultHost, "host on which HTTP server should listen")
 	cmd.Flags().IntP("http-server-port", "", http.ServerDefaultPort, "port on which HTTP server should listen")
-	cmd.Flags().StringP("network", "", "preprod", "cardano network ID")
+	cmd.Flags().StringP("network", "", "preprod", "cardano network name (mainnet, preprod, preview, sanchonet or the name of a custom network)")
+	cmd.Flags().IntP("network-testnet-magic", "", 0, "testnet magic of a custom network")
+	cmd.Flags().StringP("network-config-dir", "", "", "path to the directory where the genesis files of a custom network are stored")
 	cmd.Flags().StringP("database-path", "", "watcher.db", "path to the local database mainly used by cardano client")
 	cmd.Flags().StringP("cardano-config-dir", "", "/config", "path to the directory where the cardano config files are stored")
 	cmd.Flags().StringP("cardano-timezone", "", "UTC", "timezone to use with cardano-cli - https://en.wikipedia.org/wiki/List_of_tz_database_time_zones")
+	cmd.Flags().StringP("provider", "", config.ProviderBlockfrost, "chain data provider (blockfrost or koios)")
 	cmd.Flags().StringP("blockfrost-project-id", "", "", "blockfrost project id")
 	cmd.Flags().StringP("blockfrost-endpoint", "", "", "blockfrost API endpoint")
 	cmd.Flags().IntP("blockfrost-max-routines", "", 10, "number of routines used by blockfrost to perform concurrent actions")
 	cmd.Flags().IntP("blockfrost-timeout", "", 60, "Timeout for requests to the Blockfrost API (in seconds)")
+	cmd.Flags().Float64P("blockfrost-requests-per-second", "", transport.DefaultRequestsPerSecond, "Maximum sustained rate of requests sent to the Blockfrost API")
+	cmd.Flags().IntP("blockfrost-burst", "", transport.DefaultBurst, "Maximum burst of requests sent to the Blockfrost API")
+	cmd.Flags().IntP("blockfrost-max-retries", "", transport.DefaultMaxRetries, "Number of retries of rate limited or failed Blockfrost requests (negative = disabled)")
+	cmd.Flags().StringP("koios-endpoint", "", "", "koios API endpoint")
+	cmd.Flags().StringP("koios-api-key", "", "", "koios API key (optional)")
+	cmd.Flags().IntP("koios-timeout", "", 60, "Timeout for requests to the Koios API (in seconds)")
+	cmd.Flags().Float64P("koios-requests-per-second", "", transport.DefaultRequestsPerSecond, "Maximum sustained rate of requests sent to the Koios API")
+	cmd.Flags().IntP("koios-burst", "", transport.DefaultBurst, "Maximum burst of requests sent to the Koios API")
+	cmd.Flags().IntP("koios-max-retries", "", transport.DefaultMaxRetries, "Number of retries of rate limited or failed Koios requests (negative = disabled)")
 	cmd.Flags().IntP("status-watcher-refresh-interval", "", 15, "Interval at which the status watcher collects data about the network (in seconds)")
 	cmd.Flags().BoolP("network-watcher-enabled", "", true, "Enable network watcher")
 	cmd.Flags().IntP("network-watcher-refresh-interval", "", 60, "Interval at which the network watcher collects data about the network (in seconds)")
 	cmd.Flags().BoolP("pool-watcher-enabled", "", true, "Enable pool watcher")
 	cmd.Flags().IntP("pool-watcher-refresh-interval", "", 60, "Interval at which the pool watcher collects data about the monitored pools (in seconds)")
+	cmd.Flags().IntP("pool-watcher-relay-probe-interval", "", 60, "Interval at which the pool watcher dials the relays registered by the monitored pools (in seconds, 0 = disabled)")
+	cmd.Flags().Float64P("pool-watcher-pledge-warning-threshold", "", 5, "Margin over the declared pledge, in percent, under which the live pledge of a pool is reported")
+	cmd.Flags().BoolP("kes-watcher-enabled", "", true, "Enable KES watcher")
+	cmd.Flags().IntP("kes-watcher-refresh-interval", "", 300, "Interval at which the KES watcher checks the operational certificates of the monitored pools (in seconds)")
 	cmd.Flags().BoolP("block-watcher-enabled", "", true, "Enable block watcher")
 	cmd.Flags().IntP("block-watcher-refresh-interval", "", 60, "Interval at which the block watcher collects and process slots (in seconds)")
+	cmd.Flags().IntP("block-watcher-confirmation-depth", "", 3, "Distance to the tip a leader slot must reach before the block watcher finalizes it")
+	cmd.Flags().StringP("block-watcher-confirmation-unit", "", "blocks", "Unit of the confirmation depth (slots or blocks)")
+	cmd.Flags().IntP("block-watcher-recheck-window", "", 2160, "Number of slots behind the tip in which validated blocks are re-checked for rollbacks (0 = disabled)")
+	cmd.Flags().BoolP("block-watcher-expose-next-slot-leader", "", false, "Expose the next leader slot of the pools instead of the number of leader slots remaining in the epoch")
+	cmd.Flags().StringP("slot-leader-engine", "", config.SlotLeaderEngineCncli, "Engine computing the leader schedule (cncli or native)")
 	cmd.Flags().IntP("slot-leader-concurrency", "", 0, "max concurrent cncli leaderlog processes (0 = unlimited)")
+	cmd.Flags().IntP("slot-leader-vrf-key-check-interval", "", int(slotleader.DefaultVRFKeyCheckInterval.Seconds()), "Interval at which the VRF keys of the pools are verified against their on-chain registration (in seconds)")
+	cmd.PersistentFlags().StringP("slot-leader-encryption-key-file", "", "", "path to the file holding the base64 key encrypting the leader slots in the database")
 
 	// bind flag to viper
 	checkError(viper.BindPFlag("log-level", cmd.Flag("log-level")), "unable to bind log-level flag")
 	checkError(viper.BindPFlag("http.host", cmd.Flag("http-server-host")), "unable to bind http-server-host flag")
 	checkError(viper.BindPFlag("http.port", cmd.Flag("http-server-port")), "unable to bind http-server-port flag")
-	checkError(viper.BindPFlag("network", cmd.Flag("network")), "unable to bind network flag")
+	checkError(viper.BindPFlag("network.name", cmd.Flag("network")), "unable to bind network flag")
+	checkError(viper.BindPFlag("network.testnet-magic", cmd.Flag("network-testnet-magic")), "unable to bind network-testnet-magic flag")
+	checkError(viper.BindPFlag("network.config-dir", cmd.Flag("network-config-dir")), "unable to bind network-config-dir flag")
 	checkError(viper.BindPFlag("database.path", cmd.Flag("database-path")), "unable to bind database-path flag")
 	checkError(viper.BindPFlag("cardano.config-dir", cmd.Flag("cardano-config-dir")), "unable to bind cardano-config-dir flag")
 	checkError(viper.BindPFlag("cardano.timezone", cmd.Flag("cardano-timezone")), "unable to bind cardano-timezone flag")
+	checkError(viper.BindPFlag("provider", cmd.Flag("provider")), "unable to bind provider flag")
 	checkError(viper.BindPFlag("blockfrost.project-id", cmd.Flag("blockfrost-project-id")), "unable to bind blockfrost-project-id flag")
 	checkError(viper.BindPFlag("blockfrost.endpoint", cmd.Flag("blockfrost-endpoint")), "unable to bind blockfrost-endpoint flag")
 	checkError(viper.BindPFlag("blockfrost.max-routines", cmd.Flag("blockfrost-max-routines")), "unable to bind blockfrost-max-routines flag")
 	checkError(viper.BindPFlag("blockfrost.timeout", cmd.Flag("blockfrost-timeout")), "unable to bind blockfrost-timeout flag")
+	checkError(viper.BindPFlag("blockfrost.rate-limit.requests-per-second", cmd.Flag("blockfrost-requests-per-second")), "unable to bind blockfrost-requests-per-second flag")
+	checkError(viper.BindPFlag("blockfrost.rate-limit.burst", cmd.Flag("blockfrost-burst")), "unable to bind blockfrost-burst flag")
+	checkError(viper.BindPFlag("blockfrost.rate-limit.max-retries", cmd.Flag("blockfrost-max-retries")), "unable to bind blockfrost-max-retries flag")
+	checkError(viper.BindPFlag("koios.endpoint", cmd.Flag("koios-endpoint")), "unable to bind koios-endpoint flag")
+	checkError(viper.BindPFlag("koios.api-key", cmd.Flag("koios-api-key")), "unable to bind koios-api-key flag")
+	checkError(viper.BindPFlag("koios.timeout", cmd.Flag("koios-timeout")), "unable to bind koios-timeout flag")
+	checkError(viper.BindPFlag("koios.rate-limit.requests-per-second", cmd.Flag("koios-requests-per-second")), "unable to bind koios-requests-per-second flag")
+	checkError(viper.BindPFlag("koios.rate-limit.burst", cmd.Flag("koios-burst")), "unable to bind koios-burst flag")
+	checkError(viper.BindPFlag("koios.rate-limit.max-retries", cmd.Flag("koios-max-retries")), "unable to bind koios-max-retries flag")
 	checkError(viper.BindPFlag("network-watcher.enabled", cmd.Flag("network-watcher-enabled")), "unable to bind network-watcher-enabled flag")
 	checkError(viper.BindPFlag("network-watcher.refresh-interval", cmd.Flag("network-watcher-refresh-interval")), "unable to bind network-watcher-refresh-interval flag")
 	checkError(viper.BindPFlag("status-watcher.refresh-interval", cmd.Flag("status-watcher-refresh-interval")), "unable to bind status-watcher-refresh-interval flag")
 	checkError(viper.BindPFlag("pool-watcher.enabled", cmd.Flag("pool-watcher-enabled")), "unable to bind pool-watcher-enabled flag")
 	checkError(viper.BindPFlag("pool-watcher.refresh-interval", cmd.Flag("pool-watcher-refresh-interval")), "unable to bind pool-watcher-refresh-interval flag")
+	checkError(viper.BindPFlag("pool-watcher.relay-probe-interval", cmd.Flag("pool-watcher-relay-probe-interval")), "unable to bind pool-watcher-relay-probe-interval flag")
+	checkError(viper.BindPFlag("pool-watcher.pledge-warning-threshold", cmd.Flag("pool-watcher-pledge-warning-threshold")), "unable to bind pool-watcher-pledge-warning-threshold flag")
+	checkError(viper.BindPFlag("kes-watcher.enabled", cmd.Flag("kes-watcher-enabled")), "unable to bind kes-watcher-enabled flag")
+	checkError(viper.BindPFlag("kes-watcher.refresh-interval", cmd.Flag("kes-watcher-refresh-interval")), "unable to bind kes-watcher-refresh-interval flag")
 	checkError(viper.BindPFlag("block-watcher.enabled", cmd.Flag("block-watcher-enabled")), "unable to bind block-watcher-enabled flag")
 	checkError(viper.BindPFlag("block-watcher.refresh-interval", cmd.Flag("block-watcher-refresh-interval")), "unable to bind block-watcher-refresh-interval flag")
+	checkError(viper.BindPFlag("block-watcher.confirmation-depth", cmd.Flag("block-watcher-confirmation-depth")), "unable to bind block-watcher-confirmation-depth flag")
+	checkError(viper.BindPFlag("block-watcher.confirmation-unit", cmd.Flag("block-watcher-confirmation-unit")), "unable to bind block-watcher-confirmation-unit flag")
+	checkError(viper.BindPFlag("block-watcher.recheck-window", cmd.Flag("block-watcher-recheck-window")), "unable to bind block-watcher-recheck-window flag")
+	checkError(viper.BindPFlag("block-watcher.expose-next-slot-leader", cmd.Flag("block-watcher-expose-next-slot-leader")), "unable to bind block-watcher-expose-next-slot-leader flag")
+	checkError(viper.BindPFlag("slot-leader.engine", cmd.Flag("slot-leader-engine")), "unable to bind slot-leader-engine flag")
 	checkError(viper.BindPFlag("slot-leader.concurrency", cmd.Flag("slot-leader-concurrency")), "unable to bind slot-leader-concurrency flag")
+	checkError(viper.BindPFlag("slot-leader.vrf-key-check-interval", cmd.Flag("slot-leader-vrf-key-check-interval")), "unable to bind slot-leader-vrf-key-check-interval flag")
+	checkError(viper.BindPFlag("slot-leader.encryption-key-file", cmd.PersistentFlags().Lookup("slot-leader-encryption-key-file")), "unable to bind slot-leader-encryption-key-file flag")
+	// The encryption key has no flag so it does not show up in the process list
+	checkError(viper.BindEnv("slot-leader.encryption-key"), "unable to bind slot-leader.encryption-key env")
+
+	cmd.AddCommand(NewScheduleCommand())
+	cmd.AddCommand(NewMaintenanceWindowsCommand())
 
 	return cmd
 }
@@ -143,7 +202,15 @@ func loadConfig() {
 
 	// unmarshal the config
 	cfg = &config.Config{}
-	if err := viper.Unmarshal(cfg); err != nil {
+	decodeHook := mapstructure.ComposeDecodeHookFunc(
+		config.NetworkDecodeHook(config.NetworkConfig{
+			TestnetMagic: viper.GetInt("network.testnet-magic"),
+			ConfigDir:    viper.GetString("network.config-dir"),
+		}),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+	if err := viper.Unmarshal(cfg, viper.DecodeHook(decodeHook)); err != nil {
 		logger.ErrorContext(context.Background(), "unable to unmarshal config", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
@@ -180,14 +247,17 @@ func run(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("unable to migrate database: %w", err)
 	}
 
-	// Initialize blockfrost and cardano clients with options
-	blockfrost := createBlockfrostClient()
-
 	// Initialize prometheus metrics
 	registry := prometheus.NewRegistry()
 	metrics := metrics.NewCollection()
 	metrics.MustRegister(registry)
 
+	// Initialize the chain data and cardano clients with options
+	blockfrost, err := createChainDataClient(metrics)
+	if err != nil {
+		return fmt.Errorf("unable to create chain data client: %w", err)
+	}
+
 	// The watcher proxies cardano-cli through a local Unix socket that forwards
 	// to the configured cardano-node TCP endpoints, failing over between them.
 	const proxySocketPath = "/tmp/cardano-proxy.socket"
@@ -201,7 +271,18 @@ func run(_ *cobra.Command, _ []string) error {
 	}
 	proxy.Start(ctx)
 
-	cardano := createCardanoClient(blockfrost, proxy.SocketPath())
+	// Derive the slot and epoch timing of the network from its shelley genesis
+	timeline, err := createTimeline()
+	if err != nil {
+		return fmt.Errorf("unable to create network timeline: %w", err)
+	}
+
+	cardano := createCardanoClient(blockfrost, proxy.SocketPath(), timeline)
+
+	// Ensure that the genesis files and the chain data provider belong to the configured network
+	if err := checkNetwork(ctx, blockfrost, timeline); err != nil {
+		return fmt.Errorf("network check failed: %w", err)
+	}
 
 	epoch, err := blockfrost.GetLatestEpoch(ctx)
 	if err != nil {
@@ -209,11 +290,42 @@ func run(_ *cobra.Command, _ []string) error {
 	}
 
 	// Launch slot leader calculation for the current slot
-	slotLeaderService := slotleader.NewSlotLeaderService(database.DB, cardano, blockfrost, cfg.Pools, metrics, cfg.SlotLeaderConfig.Concurrency)
+	slotLeaderService, err := createSlotLeaderService(database.DB, cardano, blockfrost, timeline, metrics)
+	if err != nil {
+		return err
+	}
+	// The schedules already stored are aligned with the encryption settings.
+	// Only the watcher does it since it rewrites the leader slots and vacuums the database.
+	if err := slotLeaderService.PrepareStorage(ctx); err != nil {
+		return fmt.Errorf("unable to prepare the schedule storage: %w", err)
+	}
+	// The pools signing with a VRF key that is not registered on-chain are not refreshed
+	if err := slotLeaderService.VerifyVRFKeys(ctx); err != nil {
+		logger.ErrorContext(ctx, "unable to verify the vrf keys of some pools",
+			slog.String("error", err.Error()),
+		)
+	}
 	if err := slotLeaderService.RefreshCurrent(ctx, epoch); err != nil {
-		return fmt.Errorf("unable to refresh slot leaders: %w", err)
+		// The pools that failed are degraded and refreshed again in the background
+		logger.ErrorContext(ctx, "unable to refresh slot leaders of some pools",
+			slog.String("error", err.Error()),
+		)
 	}
 
+	eg.Go(func() error {
+		logger.InfoContext(ctx, "starting slot leader refresh retrier",
+			slog.String("component", "refresh-retrier"),
+		)
+		return slotLeaderService.RunRefreshRetrier(ctx)
+	})
+
+	eg.Go(func() error {
+		logger.InfoContext(ctx, "starting vrf key verifier",
+			slog.String("component", "vrf-key-verifier"),
+		)
+		return slotLeaderService.RunVRFKeyVerifier(ctx, time.Second*time.Duration(cfg.SlotLeaderConfig.VRFKeyCheckInterval))
+	})
+
 	eg.Go(func() error {
 		logger.InfoContext(ctx, "starting next epoch scheduler",
 			slog.String("component", "next-epoch-scheduler"),
@@ -223,8 +335,19 @@ func run(_ *cobra.Command, _ []string) error {
 
 	healthStore := watcher.NewHealthStore()
 
+	location, err := time.LoadLocation(cfg.Cardano.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %s: %w", cfg.Cardano.Timezone, err)
+	}
+	// The maintenance windows reveal the leader schedule, they are only served
+	// when the next slot leader is exposed as well
+	var windowFinder http.MaintenanceWindowFinder
+	if cfg.BlockWatcherConfig.ExposeNextSlotLeader {
+		windowFinder = schedule.NewWindowFinder(slotLeaderService, timeline, location, cfg.Pools)
+	}
+
 	// Start HTTP server
-	if err := startHTTPServer(eg, registry, healthStore); err != nil {
+	if err := startHTTPServer(eg, registry, healthStore, windowFinder); err != nil {
 		return fmt.Errorf("unable to start http server: %w", err)
 	}
 
@@ -233,17 +356,22 @@ func run(_ *cobra.Command, _ []string) error {
 
 	// Start Pool Watcher
 	if cfg.PoolWatcherConfig.Enabled {
-		startPoolWatcher(ctx, eg, blockfrost, metrics, cfg.Pools, healthStore)
+		startPoolWatcher(ctx, eg, blockfrost, metrics, cfg.Pools, database.DB, healthStore)
 	}
 
 	// Start Block Watcher
 	if cfg.BlockWatcherConfig.Enabled {
-		startBlockWatcher(ctx, eg, cardano, blockfrost, slotLeaderService, metrics, cfg.Pools, database.DB, healthStore)
+		startBlockWatcher(ctx, eg, cardano, blockfrost, slotLeaderService, timeline, metrics, cfg.Pools, database.DB, healthStore)
 	}
 
 	// Start Network Watcher
 	if cfg.NetworkWatcherConfig.Enabled {
-		startNetworkWatcher(ctx, eg, blockfrost, metrics, healthStore)
+		startNetworkWatcher(ctx, eg, blockfrost, timeline, metrics, healthStore)
+	}
+
+	// Start KES Watcher
+	if cfg.KESWatcherConfig.Enabled {
+		startKESWatcher(ctx, eg, blockfrost, cardano, timeline, metrics, cfg.Pools, healthStore)
 	}
 
 	<-ctx.Done()
@@ -267,27 +395,162 @@ func run(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
-func createBlockfrostClient() blockfrost.Client {
-	opts := blockfrostapi.ClientOptions{
-		ProjectID:   cfg.Blockfrost.ProjectID,
-		Server:      cfg.Blockfrost.Endpoint,
-		MaxRoutines: cfg.Blockfrost.MaxRoutines,
-		Timeout:     time.Second * time.Duration(cfg.Blockfrost.Timeout),
+// createChainDataClient returns a client failing over between the configured
+// providers, in priority order. Without a providers list, the single provider
+// selected by the provider setting is used.
+func createChainDataClient(metrics *metrics.Collection) (blockfrost.Client, error) {
+	providers := make([]failover.Provider, 0, len(cfg.Providers))
+	for _, provider := range cfg.Providers {
+		providers = append(providers, failover.Provider{
+			Name:   provider.Name,
+			Client: createProviderClient(provider, metrics),
+		})
+	}
+
+	if len(providers) == 0 {
+		provider := config.ProviderConfig{
+			Name:        cfg.Provider,
+			Type:        cfg.Provider,
+			Endpoint:    cfg.Blockfrost.Endpoint,
+			ProjectID:   cfg.Blockfrost.ProjectID,
+			MaxRoutines: cfg.Blockfrost.MaxRoutines,
+			Timeout:     cfg.Blockfrost.Timeout,
+			RateLimit:   cfg.Blockfrost.RateLimit,
+		}
+		if cfg.Provider == config.ProviderKoios {
+			provider.Endpoint = cfg.Koios.Endpoint
+			provider.APIKey = cfg.Koios.APIKey
+			provider.Timeout = cfg.Koios.Timeout
+			provider.RateLimit = cfg.Koios.RateLimit
+		}
+		providers = append(providers, failover.Provider{
+			Name:   provider.Name,
+			Client: createProviderClient(provider, metrics),
+		})
+	}
+
+	client, err := failover.NewClient(providers, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create failover client: %w", err)
+	}
+	return client, nil
+}
+
+// createProviderClient returns the client of a single provider. Its requests
+// are rate limited and retried by a transport publishing per-endpoint metrics.
+func createProviderClient(provider config.ProviderConfig, metrics *metrics.Collection) blockfrost.Client {
+	rt := transport.NewTransport(nil, transport.Options{
+		Provider:          provider.Name,
+		RequestsPerSecond: provider.RateLimit.RequestsPerSecond,
+		Burst:             provider.RateLimit.Burst,
+		MaxRetries:        provider.RateLimit.MaxRetries,
+	}, metrics)
+
+	if provider.Type == config.ProviderKoios {
+		return koiosapi.NewClient(koiosapi.ClientOptions{
+			Server:    provider.Endpoint,
+			APIKey:    provider.APIKey,
+			Timeout:   time.Second * time.Duration(provider.Timeout),
+			Transport: rt,
+		})
 	}
-	return blockfrostapi.NewClient(opts)
+	return blockfrostapi.NewClient(blockfrostapi.ClientOptions{
+		ProjectID:   provider.ProjectID,
+		Server:      provider.Endpoint,
+		MaxRoutines: provider.MaxRoutines,
+		Timeout:     time.Second * time.Duration(provider.Timeout),
+		Transport:   rt,
+	})
 }
 
-func createCardanoClient(blockfrost blockfrost.Client, socketPath string) cardano.CardanoClient {
+func createCardanoClient(blockfrost blockfrost.Client, socketPath string, timeline *cardanotime.Timeline) cardano.CardanoClient {
 	opts := cardanocli.ClientOptions{
-		ConfigDir:  cfg.Cardano.ConfigDir,
-		Network:    cfg.Network,
+		ConfigDir:  cfg.GenesisDir(),
+		Network:    cfg.Network.CardanoNetwork(),
 		SocketPath: socketPath,
 		Timezone:   cfg.Cardano.Timezone,
 	}
-	return cardanocli.NewClient(opts, blockfrost, &cardanocli.RealCommandExecutor{})
+	client := cardanocli.NewClient(opts, blockfrost, &cardanocli.RealCommandExecutor{})
+	if cfg.SlotLeaderConfig.Engine == config.SlotLeaderEngineNative {
+		return cardanonative.NewClient(cardanonative.ClientOptions{Timezone: cfg.Cardano.Timezone}, client, blockfrost, timeline)
+	}
+	return client
+}
+
+// createSlotLeaderService returns the slot leader service, encrypting the
+// leader slots when an encryption key is configured.
+func createSlotLeaderService(
+	db *sqlx.DB,
+	cardano cardano.CardanoClient,
+	blockfrost blockfrost.Client,
+	timeline *cardanotime.Timeline,
+	metrics *metrics.Collection,
+) (*slotleader.Service, error) {
+	opts := []slotleader.ServiceOptionsFunc{}
+	cipher, err := createScheduleCipher()
+	if err != nil {
+		return nil, err
+	}
+	if cipher != nil {
+		opts = append(opts, slotleader.WithScheduleCipher(cipher))
+	}
+
+	return slotleader.NewSlotLeaderService(db, cardano, blockfrost, timeline, cfg.Pools, metrics, cfg.SlotLeaderConfig.Concurrency, opts...), nil
+}
+
+// createScheduleCipher returns the cipher of the leader slots, or nil when no
+// encryption key is configured.
+func createScheduleCipher() (*slotleader.ScheduleCipher, error) {
+	encoded := cfg.SlotLeaderConfig.EncryptionKey
+	if path := cfg.SlotLeaderConfig.EncryptionKeyFile; path != "" {
+		content, err := os.ReadFile(path) //nolint:gosec
+		if err != nil {
+			return nil, fmt.Errorf("unable to read encryption key file: %w", err)
+		}
+		encoded = string(content)
+	}
+	if encoded == "" {
+		return nil, nil
+	}
+
+	key, err := slotleader.ParseEncryptionKey(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slot-leader encryption key: %w", err)
+	}
+	cipher, err := slotleader.NewScheduleCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create schedule cipher: %w", err)
+	}
+	return cipher, nil
+}
+
+func createTimeline() (*cardanotime.Timeline, error) {
+	genesis, err := cardanotime.LoadShelleyGenesis(filepath.Join(cfg.GenesisDir(), "shelley.json"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load shelley genesis: %w", err)
+	}
+	return cardanotime.NewTimeline(genesis, cardanotime.ShelleyTransitionEpoch(cfg.Network.Name)), nil
+}
+
+// checkNetwork ensures that the shelley genesis and the chain data provider
+// share the network magic of the configured network.
+func checkNetwork(ctx context.Context, blockfrost blockfrost.Client, timeline *cardanotime.Timeline) error {
+	network := cfg.Network.CardanoNetwork()
+	if timeline.NetworkMagic() != network.Magic {
+		return fmt.Errorf("shelley genesis in %s has network magic %d but network %s expects %d", cfg.GenesisDir(), timeline.NetworkMagic(), network.Name, network.Magic)
+	}
+
+	genesis, err := blockfrost.GetGenesisInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve chain data provider genesis: %w", err)
+	}
+	if genesis.NetworkMagic != network.Magic {
+		return fmt.Errorf("chain data provider serves network magic %d but network %s expects %d", genesis.NetworkMagic, network.Name, network.Magic)
+	}
+	return nil
 }
 
-func startHTTPServer(eg *errgroup.Group, registry *prometheus.Registry, healthStore *watcher.HealthStore) error {
+func startHTTPServer(eg *errgroup.Group, registry *prometheus.Registry, healthStore *watcher.HealthStore, windowFinder http.MaintenanceWindowFinder) error {
 	var err error
 
 	server, err = http.New(
@@ -295,6 +558,7 @@ func startHTTPServer(eg *errgroup.Group, registry *prometheus.Registry, healthSt
 		healthStore,
 		http.WithHost(cfg.HTTP.Host),
 		http.WithPort(cfg.HTTP.Port),
+		http.WithMaintenanceWindowFinder(windowFinder),
 	)
 	if err != nil {
 		return fmt.Errorf("unable to create http server: %w", err)
@@ -350,18 +614,21 @@ func startPoolWatcher(
 	blockfrost blockfrost.Client,
 	metrics *metrics.Collection,
 	pools pools.Pools,
+	db *sqlx.DB,
 	healthStore *watcher.HealthStore,
 ) {
 	eg.Go(func() error {
 		options := watcher.PoolWatcherOptions{
-			RefreshInterval: time.Second * time.Duration(cfg.PoolWatcherConfig.RefreshInterval),
-			Network:         cfg.Network,
+			RefreshInterval:        time.Second * time.Duration(cfg.PoolWatcherConfig.RefreshInterval),
+			Network:                cfg.Network.Name,
+			RelayProbeInterval:     time.Second * time.Duration(cfg.PoolWatcherConfig.RelayProbeInterval),
+			PledgeWarningThreshold: cfg.PoolWatcherConfig.PledgeWarningThreshold,
 		}
 		logger.InfoContext(ctx,
 			"starting watcher",
 			slog.String("component", "pool-watcher"),
 		)
-		poolWatcher, err := watcher.NewPoolWatcher(blockfrost, metrics, pools, healthStore, options)
+		poolWatcher, err := watcher.NewPoolWatcher(blockfrost, metrics, pools, db, healthStore, options)
 		if err != nil {
 			return fmt.Errorf("unable to create pool watcher: %w", err)
 		}
@@ -376,6 +643,7 @@ func startNetworkWatcher(
 	ctx context.Context,
 	eg *errgroup.Group,
 	blockfrost blockfrost.Client,
+	timeline *cardanotime.Timeline,
 	metrics *metrics.Collection,
 	healthStore *watcher.HealthStore,
 ) {
@@ -383,13 +651,13 @@ func startNetworkWatcher(
 		options := watcher.NetworkWatcherOptions{
 			// to change
 			RefreshInterval: time.Second * time.Duration(cfg.PoolWatcherConfig.RefreshInterval),
-			Network:         cfg.Network,
+			Network:         cfg.Network.Name,
 		}
 		logger.InfoContext(ctx,
 			"starting watcher",
 			slog.String("component", "network-watcher"),
 		)
-		networkWatcher := watcher.NewNetworkWatcher(blockfrost, metrics, healthStore, options)
+		networkWatcher := watcher.NewNetworkWatcher(blockfrost, timeline, metrics, healthStore, options)
 		if err := networkWatcher.Start(ctx); err != nil {
 			return fmt.Errorf("unable to start network watcher: %w", err)
 		}
@@ -397,6 +665,33 @@ func startNetworkWatcher(
 	})
 }
 
+// startKESWatcher starts the KES watcher service
+func startKESWatcher(
+	ctx context.Context,
+	eg *errgroup.Group,
+	blockfrost blockfrost.Client,
+	cardano cardano.CardanoClient,
+	timeline *cardanotime.Timeline,
+	metrics *metrics.Collection,
+	pools pools.Pools,
+	healthStore *watcher.HealthStore,
+) {
+	eg.Go(func() error {
+		options := watcher.KESWatcherOptions{
+			RefreshInterval: time.Second * time.Duration(cfg.KESWatcherConfig.RefreshInterval),
+		}
+		logger.InfoContext(ctx,
+			"starting watcher",
+			slog.String("component", "kes-watcher"),
+		)
+		kesWatcher := watcher.NewKESWatcher(blockfrost, cardano, timeline, metrics, pools, healthStore, options)
+		if err := kesWatcher.Start(ctx); err != nil {
+			return fmt.Errorf("unable to start kes watcher: %w", err)
+		}
+		return nil
+	})
+}
+
 // startBlockWatcher starts the block watcher service
 func startBlockWatcher(
 	ctx context.Context,
@@ -404,6 +699,7 @@ func startBlockWatcher(
 	cardano cardano.CardanoClient,
 	blockfrost blockfrost.Client,
 	sl slotleader.SlotLeader,
+	timeline *cardanotime.Timeline,
 	metrics *metrics.Collection,
 	pools pools.Pools,
 	db *sqlx.DB,
@@ -411,9 +707,13 @@ func startBlockWatcher(
 ) {
 	eg.Go(func() error {
 		options := watcher.BlockWatcherOptions{
-			RefreshInterval: time.Second * time.Duration(cfg.BlockWatcherConfig.RefreshInterval),
+			RefreshInterval:   time.Second * time.Duration(cfg.BlockWatcherConfig.RefreshInterval),
+			ConfirmationDepth: cfg.BlockWatcherConfig.ConfirmationDepth,
+			ConfirmationUnit:  cfg.BlockWatcherConfig.ConfirmationUnit,
+			RecheckWindow:     cfg.BlockWatcherConfig.RecheckWindow,
+			SchedulePrivacy:   !cfg.BlockWatcherConfig.ExposeNextSlotLeader,
 		}
-		blockWatcher := watcher.NewBlockWatcher(cardano, blockfrost, sl, pools, metrics, db, healthStore, options)
+		blockWatcher := watcher.NewBlockWatcher(cardano, blockfrost, sl, timeline, pools, metrics, db, healthStore, options)
 		logger.InfoContext(ctx,
 			"starting watcher",
 			slog.String("component", "block-watcher"),
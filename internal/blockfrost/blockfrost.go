@@ -12,16 +12,20 @@ type Client interface {
 	GetPoolInfo(ctx context.Context, PoolID string) (blockfrost.Pool, error)
 	GetPoolMetadata(ctx context.Context, PoolID string) (blockfrost.PoolMetadata, error)
 	GetPoolRelays(ctx context.Context, PoolID string) ([]blockfrost.PoolRelay, error)
+	GetPoolLatestBlocks(ctx context.Context, PoolID string, count int) ([]string, error)
 	GetBlockDistributionByPool(ctx context.Context, epoch int, PoolID string) ([]string, error)
 	GetLastBlockFromPreviousEpoch(ctx context.Context, prevEpoch int) (blockfrost.Block, error)
 	GetEpochParameters(ctx context.Context, epoch int) (blockfrost.EpochParameters, error)
 	GetBlockBySlotAndEpoch(ctx context.Context, epoch int, slot int) (blockfrost.Block, error)
 	GetBlockBySlot(ctx context.Context, slot int) (blockfrost.Block, error)
+	GetBlockByHeight(ctx context.Context, height int) (blockfrost.Block, error)
+	GetBlockByHash(ctx context.Context, hash string) (blockfrost.Block, error)
 	Health(ctx context.Context) (blockfrost.Health, error)
 	GetFirstSlotInEpoch(ctx context.Context, epoch int) (int, error)
 	GetFirstBlockInEpoch(ctx context.Context, epoch int) (blockfrost.Block, error)
 	GetGenesisInfo(ctx context.Context) (blockfrost.GenesisBlock, error)
 	GetAllPools(ctx context.Context) ([]string, error)
+	GetRetiringPools(ctx context.Context) ([]blockfrost.PoolRetiring, error)
 	GetNetworkInfo(ctx context.Context) (blockfrost.NetworkInfo, error)
 	GetAccountInfo(ctx context.Context, stakeAddress string) (Account, error)
 }
@@ -3,10 +3,12 @@ package blockfrostapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/blockfrost/blockfrost-go"
@@ -15,6 +17,7 @@ import (
 
 type Client struct {
 	blockfrost blockfrost.APIClient
+	httpClient *http.Client
 	apiURL     string
 	projectID  string
 }
@@ -26,48 +29,63 @@ type ClientOptions struct {
 	Server      string
 	MaxRoutines int
 	Timeout     time.Duration
+	// Transport is the optional round tripper used to send the requests,
+	// e.g. to rate limit and retry them. It defaults to http.DefaultTransport.
+	Transport http.RoundTripper
 }
 
 func NewClient(opts ClientOptions) *Client {
+	transport := opts.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	httpClient := &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: statusTransport{next: transport},
+	}
 	return &Client{
 		blockfrost: blockfrost.NewAPIClient(
 			blockfrost.APIClientOptions{
 				ProjectID:   opts.ProjectID,
 				Server:      opts.Server,
 				MaxRoutines: opts.MaxRoutines,
-				Client: &http.Client{
-					Timeout: opts.Timeout,
-				},
+				Client:      httpClient,
 			},
 		),
-		apiURL:    opts.Server,
-		projectID: opts.ProjectID,
+		httpClient: httpClient,
+		apiURL:     opts.Server,
+		projectID:  opts.ProjectID,
 	}
 }
 
-//nolint:wrapcheck
 func (c *Client) GetLatestEpoch(ctx context.Context) (blockfrost.Epoch, error) {
-	return c.blockfrost.EpochLatest(ctx)
+	return mapResult(c.blockfrost.EpochLatest(ctx))
 }
 
-//nolint:wrapcheck
 func (c *Client) GetLatestBlock(ctx context.Context) (blockfrost.Block, error) {
-	return c.blockfrost.BlockLatest(ctx)
+	return mapResult(c.blockfrost.BlockLatest(ctx))
 }
 
-//nolint:wrapcheck
 func (c *Client) GetPoolInfo(ctx context.Context, PoolID string) (blockfrost.Pool, error) {
-	return c.blockfrost.Pool(ctx, PoolID)
+	return mapResult(c.blockfrost.Pool(ctx, PoolID))
 }
 
-//nolint:wrapcheck
 func (c *Client) GetPoolMetadata(ctx context.Context, PoolID string) (blockfrost.PoolMetadata, error) {
-	return c.blockfrost.PoolMetadata(ctx, PoolID)
+	return mapResult(c.blockfrost.PoolMetadata(ctx, PoolID))
 }
 
-//nolint:wrapcheck
 func (c *Client) GetPoolRelays(ctx context.Context, PoolID string) ([]blockfrost.PoolRelay, error) {
-	return c.blockfrost.PoolRelays(ctx, PoolID)
+	return mapResult(c.blockfrost.PoolRelays(ctx, PoolID))
+}
+
+// GetPoolLatestBlocks returns the hashes of the latest blocks forged by a pool,
+// the most recent first.
+func (c *Client) GetPoolLatestBlocks(ctx context.Context, PoolID string, count int) ([]string, error) {
+	blocks, err := c.blockfrost.PoolBlocks(ctx, PoolID, blockfrost.APIQueryParams{Count: count, Order: "desc"})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return []string(blocks), nil
 }
 
 func (c *Client) GetBlockDistributionByPool(ctx context.Context, epoch int, PoolID string) ([]string, error) {
@@ -75,7 +93,7 @@ func (c *Client) GetBlockDistributionByPool(ctx context.Context, epoch int, Pool
 	results := []string{}
 	for result := range resultChan {
 		if result.Err != nil {
-			return nil, result.Err
+			return nil, mapError(result.Err)
 		}
 
 		results = append(results, result.Res...)
@@ -84,83 +102,92 @@ func (c *Client) GetBlockDistributionByPool(ctx context.Context, epoch int, Pool
 	return results, nil
 }
 
-//nolint:wrapcheck
 func (c *Client) GetEpochParameters(ctx context.Context, epoch int) (blockfrost.EpochParameters, error) {
-	return c.blockfrost.EpochParameters(ctx, epoch)
+	return mapResult(c.blockfrost.EpochParameters(ctx, epoch))
 }
 
-//nolint:wrapcheck
 func (c *Client) Health(ctx context.Context) (blockfrost.Health, error) {
-	return c.blockfrost.Health(ctx)
+	return mapResult(c.blockfrost.Health(ctx))
 }
 
-//nolint:wrapcheck
 func (c *Client) GetBlockBySlotAndEpoch(ctx context.Context, slot int, epoch int) (blockfrost.Block, error) {
-	return c.blockfrost.BlocksBySlotAndEpoch(ctx, slot, epoch)
+	return mapResult(c.blockfrost.BlocksBySlotAndEpoch(ctx, slot, epoch))
 }
 
-//nolint:wrapcheck
 func (c *Client) GetBlockBySlot(ctx context.Context, slot int) (blockfrost.Block, error) {
-	return c.blockfrost.BlockBySlot(ctx, slot)
+	return mapResult(c.blockfrost.BlockBySlot(ctx, slot))
+}
+
+func (c *Client) GetBlockByHeight(ctx context.Context, height int) (blockfrost.Block, error) {
+	return mapResult(c.blockfrost.Block(ctx, strconv.Itoa(height)))
+}
+
+func (c *Client) GetBlockByHash(ctx context.Context, hash string) (blockfrost.Block, error) {
+	return mapResult(c.blockfrost.Block(ctx, hash))
 }
 
-//nolint:wrapcheck
 func (c *Client) GetLastBlockFromPreviousEpoch(ctx context.Context, prevEpoch int) (blockfrost.Block, error) {
 	response := c.blockfrost.EpochBlockDistributionAll(ctx, prevEpoch)
 	results := []string{}
 
 	for result := range response {
 		if result.Err != nil {
-			return blockfrost.Block{}, result.Err
+			return blockfrost.Block{}, mapError(result.Err)
 		}
 
 		results = append(results, result.Res...)
 	}
 
+	if len(results) == 0 {
+		return blockfrost.Block{}, fmt.Errorf("no block in epoch %d: %w", prevEpoch, bf.ErrNotFound)
+	}
 	lastBlock := results[len(results)-1]
-	return c.blockfrost.Block(ctx, lastBlock)
+	return mapResult(c.blockfrost.Block(ctx, lastBlock))
 }
 
-//nolint:wrapcheck
 func (c *Client) GetFirstBlockInEpoch(ctx context.Context, epoch int) (blockfrost.Block, error) {
 	response := c.blockfrost.EpochBlockDistributionAll(ctx, epoch)
 	results := []string{}
 
 	for result := range response {
 		if result.Err != nil {
-			return blockfrost.Block{}, result.Err
+			return blockfrost.Block{}, mapError(result.Err)
 		}
 
 		results = append(results, result.Res...)
 	}
 
-	return c.blockfrost.Block(ctx, results[0])
+	if len(results) == 0 {
+		return blockfrost.Block{}, fmt.Errorf("no block in epoch %d: %w", epoch, bf.ErrNotFound)
+	}
+	return mapResult(c.blockfrost.Block(ctx, results[0]))
 }
 
-//nolint:wrapcheck
 func (c *Client) GetFirstSlotInEpoch(ctx context.Context, epoch int) (int, error) {
 	resultChan := c.blockfrost.EpochBlockDistributionAll(ctx, epoch)
 	results := []string{}
 	for result := range resultChan {
 		if result.Err != nil {
-			return 0, result.Err
+			return 0, mapError(result.Err)
 		}
 
 		results = append(results, result.Res...)
 	}
 
+	if len(results) == 0 {
+		return 0, fmt.Errorf("no block in epoch %d: %w", epoch, bf.ErrNotFound)
+	}
 	firstBlock := results[0]
 	block, err := c.blockfrost.Block(ctx, firstBlock)
 	if err != nil {
-		return 0, err
+		return 0, mapError(err)
 	}
 
 	return block.Slot, nil
 }
 
-//nolint:wrapcheck
 func (c *Client) GetGenesisInfo(ctx context.Context) (blockfrost.GenesisBlock, error) {
-	return c.blockfrost.Genesis(ctx)
+	return mapResult(c.blockfrost.Genesis(ctx))
 }
 
 func (c *Client) GetAllPools(ctx context.Context) ([]string, error) {
@@ -168,7 +195,23 @@ func (c *Client) GetAllPools(ctx context.Context) ([]string, error) {
 	results := []string{}
 	for result := range resultChan {
 		if result.Err != nil {
-			return nil, result.Err
+			return nil, mapError(result.Err)
+		}
+
+		results = append(results, result.Res...)
+	}
+
+	return results, nil
+}
+
+// GetRetiringPools returns the pools with a retirement scheduled and the epoch
+// at which they retire.
+func (c *Client) GetRetiringPools(ctx context.Context) ([]blockfrost.PoolRetiring, error) {
+	resultChan := c.blockfrost.PoolsRetiringAll(ctx)
+	results := []blockfrost.PoolRetiring{}
+	for result := range resultChan {
+		if result.Err != nil {
+			return nil, mapError(result.Err)
 		}
 
 		results = append(results, result.Res...)
@@ -177,9 +220,8 @@ func (c *Client) GetAllPools(ctx context.Context) ([]string, error) {
 	return results, nil
 }
 
-//nolint:wrapcheck
 func (c *Client) GetNetworkInfo(ctx context.Context) (blockfrost.NetworkInfo, error) {
-	return c.blockfrost.Network(ctx)
+	return mapResult(c.blockfrost.Network(ctx))
 }
 
 func (c *Client) GetAccountInfo(ctx context.Context, address string) (bf.Account, error) {
@@ -198,9 +240,9 @@ func (c *Client) GetAccountInfo(ctx context.Context, address string) (bf.Account
 	}
 
 	req.Header.Set("Project_id", c.projectID)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return account, fmt.Errorf("failed to send request to get account details: %w", err)
+		return account, fmt.Errorf("failed to send request to get account details: %w", mapError(err))
 	}
 	defer resp.Body.Close()
 
@@ -215,3 +257,60 @@ func (c *Client) GetAccountInfo(ctx context.Context, address string) (bf.Account
 
 	return account, nil
 }
+
+// mapResult returns the result of a blockfrost-go call with its error mapped
+// by mapError.
+func mapResult[T any](value T, err error) (T, error) {
+	return value, mapError(err)
+}
+
+// mapError wraps the errors returned by blockfrost-go into the errors of the
+// chain data client so callers can branch on errors.Is.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return bf.StatusError(statusErr.code, statusErr.body)
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return bf.TransportError(err)
+	}
+	return err
+}
+
+// maxErrorBodySize is the maximum number of bytes of an error response kept in the error.
+const maxErrorBodySize = 1024
+
+// statusError is the error of a request answered with a 4xx or 5xx status code.
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("status code %d: %s", e.code, e.body)
+}
+
+// statusTransport turns the responses with a 4xx or 5xx status code into a
+// statusError. blockfrost-go neither keeps the status code of the errors it
+// decodes nor decodes the bodies which are not JSON, e.g. the HTML pages of a
+// gateway, so the status code is captured before the response reaches it.
+type statusTransport struct {
+	next http.RoundTripper
+}
+
+func (t statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil || res.StatusCode < http.StatusBadRequest {
+		return res, err //nolint:wrapcheck
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+	return nil, &statusError{code: res.StatusCode, body: string(body)}
+}
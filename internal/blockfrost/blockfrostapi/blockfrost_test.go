@@ -3,6 +3,7 @@ package blockfrostapi
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/blockfrost/blockfrost-go"
+	bf "github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
 )
 
 var (
@@ -96,6 +98,44 @@ func TestGetLatestBlock(t *testing.T) {
 	assert.Equal(t, want, block)
 }
 
+func TestGetBlockByHeight(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	want := blockfrost.Block{
+		Time:       1641338934,
+		Height:     15243590,
+		Hash:       "4ea1ba291e8eef538635a53e59fddba7810d1679631cc3aed7c8e6c4091a516a",
+		Slot:       412162100,
+		Epoch:      425,
+		EpochSlot:  12,
+		SlotLeader: "kiln",
+	}
+	mux.HandleFunc("/api/v0/blocks/15243590", func(res http.ResponseWriter, _ *http.Request) {
+		payload, err := json.Marshal(want)
+		if err != nil {
+			t.Fatalf("could not marshal response: %v", err)
+		}
+		res.WriteHeader(http.StatusOK)
+		if _, err := res.Write(payload); err != nil {
+			t.Fatalf("could not write response: %v", err)
+		}
+	})
+	server = httptest.NewServer(mux)
+
+	serverURL, _ := url.JoinPath(server.URL, "/api/v0")
+	blockfrostClientOpts := ClientOptions{
+		ProjectID:   "projectID",
+		Server:      serverURL,
+		MaxRoutines: 0,
+		Timeout:     0,
+	}
+	client := NewClient(blockfrostClientOpts)
+	block, err := client.GetBlockByHeight(ctx, 15243590)
+	require.NoError(t, err)
+	assert.Equal(t, want, block)
+}
+
 func TestGetPoolInfo(t *testing.T) {
 	ctx := context.Background()
 	mux := http.NewServeMux()
@@ -206,3 +246,143 @@ func TestGetPoolRelays(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, want, poolRelays)
 }
+
+func TestErrorClassification(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	writeError := func(res http.ResponseWriter, code int, message string) {
+		res.WriteHeader(code)
+		payload := fmt.Sprintf(`{"status_code":%d,"error":"%s","message":"%s"}`, code, http.StatusText(code), message)
+		if _, err := res.Write([]byte(payload)); err != nil {
+			t.Fatalf("could not write response: %v", err)
+		}
+	}
+	mux.HandleFunc("/api/v0/blocks/slot/1", func(res http.ResponseWriter, _ *http.Request) {
+		writeError(res, http.StatusNotFound, "The requested component has not been found.")
+	})
+	mux.HandleFunc("/api/v0/blocks/latest", func(res http.ResponseWriter, _ *http.Request) {
+		writeError(res, http.StatusForbidden, "Invalid project token.")
+	})
+	mux.HandleFunc("/api/v0/epochs/latest", func(res http.ResponseWriter, _ *http.Request) {
+		writeError(res, http.StatusTooManyRequests, "Usage is over limit.")
+	})
+	mux.HandleFunc("/api/v0/genesis", func(res http.ResponseWriter, _ *http.Request) {
+		res.WriteHeader(http.StatusBadGateway)
+	})
+	mux.HandleFunc("/api/v0/accounts/stake-0", func(res http.ResponseWriter, _ *http.Request) {
+		writeError(res, http.StatusNotFound, "The requested component has not been found.")
+	})
+	server = httptest.NewServer(mux)
+
+	serverURL, _ := url.JoinPath(server.URL, "/api/v0")
+	client := NewClient(ClientOptions{
+		ProjectID: "projectID",
+		Server:    serverURL,
+	})
+
+	t.Run("SadPath_NotFound", func(t *testing.T) {
+		_, err := client.GetBlockBySlot(ctx, 1)
+		require.ErrorIs(t, err, bf.ErrNotFound)
+
+		_, err = client.GetAccountInfo(ctx, "stake-0")
+		require.ErrorIs(t, err, bf.ErrNotFound)
+	})
+
+	t.Run("SadPath_Unauthorized", func(t *testing.T) {
+		_, err := client.GetLatestBlock(ctx)
+		require.ErrorIs(t, err, bf.ErrUnauthorized)
+	})
+
+	t.Run("SadPath_RateLimited", func(t *testing.T) {
+		_, err := client.GetLatestEpoch(ctx)
+		require.ErrorIs(t, err, bf.ErrRateLimited)
+	})
+
+	t.Run("SadPath_UpstreamUnavailable", func(t *testing.T) {
+		_, err := client.GetGenesisInfo(ctx)
+		require.ErrorIs(t, err, bf.ErrUpstreamUnavailable)
+	})
+}
+
+func TestErrorClassification_StatusCodes(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		code        int
+		contentType string
+		body        string
+		expected    error
+	}{
+		{
+			name:        "SadPath_QuotaExceeded",
+			code:        http.StatusPaymentRequired,
+			contentType: "application/json",
+			body:        `{"status_code":402,"error":"Payment Required","message":"Project over limit"}`,
+			expected:    bf.ErrRateLimited,
+		},
+		{
+			name:        "SadPath_AutoBanned",
+			code:        http.StatusTeapot,
+			contentType: "application/json",
+			body:        `{"status_code":418,"error":"Requested Banned","message":"IP has been auto-banned for flooding."}`,
+			expected:    bf.ErrRateLimited,
+		},
+		{
+			name:        "SadPath_RateLimited",
+			code:        http.StatusTooManyRequests,
+			contentType: "application/json",
+			body:        `{"status_code":429,"error":"Project Over Limit","message":"Usage is over limit."}`,
+			expected:    bf.ErrRateLimited,
+		},
+		{
+			name:        "SadPath_InternalServerErrorWithHTMLBody",
+			code:        http.StatusInternalServerError,
+			contentType: "text/html",
+			body:        "<html><body><h1>500 Internal Server Error</h1></body></html>",
+			expected:    bf.ErrUpstreamUnavailable,
+		},
+		{
+			name:        "SadPath_BadGateway",
+			code:        http.StatusBadGateway,
+			contentType: "text/html",
+			body:        "<html><body><h1>502 Bad Gateway</h1></body></html>",
+			expected:    bf.ErrUpstreamUnavailable,
+		},
+		{
+			name:        "SadPath_GatewayTimeout",
+			code:        http.StatusGatewayTimeout,
+			contentType: "text/html",
+			body:        "<html><body><h1>504 Gateway Time-out</h1></body></html>",
+			expected:    bf.ErrTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(res http.ResponseWriter, _ *http.Request) {
+				res.Header().Set("Content-Type", tt.contentType)
+				res.WriteHeader(tt.code)
+				if _, err := res.Write([]byte(tt.body)); err != nil {
+					t.Fatalf("could not write response: %v", err)
+				}
+			})
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.JoinPath(server.URL, "/api/v0")
+			client := NewClient(ClientOptions{
+				ProjectID: "projectID",
+				Server:    serverURL,
+			})
+
+			_, err := client.GetBlockBySlot(ctx, 1)
+			require.ErrorIs(t, err, tt.expected)
+			require.ErrorContains(t, err, fmt.Sprintf("status code %d", tt.code))
+
+			_, err = client.GetAccountInfo(ctx, "stake-0")
+			require.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
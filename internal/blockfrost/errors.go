@@ -0,0 +1,61 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Errors returned by the chain data clients. Every implementation wraps the
+// errors of its provider into one of them so callers can branch on errors.Is.
+var (
+	// ErrNotFound is returned when the provider has no data for the requested resource.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited is returned when the provider rejects the request because of its rate limit or quota.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUnauthorized is returned when the provider rejects the credentials of the watcher.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrUpstreamUnavailable is returned when the provider cannot be reached or fails to answer.
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrTimeout is returned when the provider does not answer in time.
+	ErrTimeout = errors.New("timeout")
+)
+
+// StatusError returns the error matching an HTTP status code answered by a
+// provider. Status codes without a matching class return a plain error.
+func StatusError(code int, body string) error {
+	var sentinel error
+	switch {
+	case code == http.StatusNotFound:
+		sentinel = ErrNotFound
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		sentinel = ErrUnauthorized
+	// Blockfrost answers 402 once the daily request quota of the project is
+	// exceeded and 418 once a client has been banned for exceeding its rate limit
+	case code == http.StatusTooManyRequests || code == http.StatusPaymentRequired || code == http.StatusTeapot:
+		sentinel = ErrRateLimited
+	case code == http.StatusGatewayTimeout:
+		sentinel = ErrTimeout
+	case code >= http.StatusInternalServerError:
+		sentinel = ErrUpstreamUnavailable
+	default:
+		return fmt.Errorf("unexpected status code %d: %s", code, body)
+	}
+	return fmt.Errorf("%w: status code %d: %s", sentinel, code, body)
+}
+
+// TransportError returns the error matching a failure to send a request to a
+// provider. Canceled requests are returned unchanged.
+func TransportError(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
+}
@@ -0,0 +1,329 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	bf "github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
+	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
+)
+
+// failoverCooldown is how long a provider is skipped after a failed call. It
+// prevents paying the request timeout repeatedly on a provider that is down
+// while other providers are available, and lets the client fail back to a
+// higher-priority provider automatically once the cooldown expires.
+const failoverCooldown = 30 * time.Second
+
+// Provider is a named chain data client.
+type Provider struct {
+	Name   string
+	Client bf.Client
+}
+
+// provider tracks the availability of a single provider so failed providers
+// can be skipped for a cooldown window.
+type provider struct {
+	Provider
+
+	mu        sync.Mutex
+	downUntil time.Time
+}
+
+func (p *provider) isDown(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return now.Before(p.downUntil)
+}
+
+func (p *provider) markDown(until time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downUntil = until
+}
+
+func (p *provider) clearDown() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downUntil = time.Time{}
+}
+
+// Client is a chain data client routing every call to the first healthy
+// provider, in configured priority order.
+type Client struct {
+	providers []*provider
+	logger    *slog.Logger
+	metrics   *metrics.Collection
+}
+
+var _ bf.Client = (*Client)(nil)
+
+// NewClient creates the failover client. metrics may be nil, in which case no
+// per-provider metrics are emitted.
+func NewClient(providers []Provider, m *metrics.Collection) (*Client, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("failover client requires at least one provider")
+	}
+
+	wrapped := make([]*provider, 0, len(providers))
+	for _, p := range providers {
+		wrapped = append(wrapped, &provider{Provider: p})
+	}
+
+	c := &Client{
+		providers: wrapped,
+		logger:    slog.With(slog.String("component", "chain-data-failover")),
+		metrics:   m,
+	}
+	c.updateMetrics()
+	return c, nil
+}
+
+// updateMetrics publishes the up/active gauge for each provider. The active
+// provider is the highest-priority one that is not in cooldown, matching what
+// call would pick.
+func (c *Client) updateMetrics() {
+	if c.metrics == nil {
+		return
+	}
+
+	now := time.Now()
+	activeSet := false
+	for _, p := range c.providers {
+		up := !p.isDown(now)
+		if up {
+			c.metrics.ChainDataProviderUp.WithLabelValues(p.Name).Set(1)
+		} else {
+			c.metrics.ChainDataProviderUp.WithLabelValues(p.Name).Set(0)
+		}
+
+		active := 0.0
+		if up && !activeSet {
+			active = 1
+			activeSet = true
+		}
+		c.metrics.ChainDataProviderActive.WithLabelValues(p.Name).Set(active)
+	}
+}
+
+// isProviderFailure reports whether the error means the provider could not
+// answer, as opposed to a valid answer such as a missing resource.
+func isProviderFailure(err error) bool {
+	return !errors.Is(err, bf.ErrNotFound)
+}
+
+// call runs fn on the first available provider, trying them in configured
+// priority order. Providers that recently failed are skipped for
+// failoverCooldown; if every provider is in cooldown they are all retried as
+// a last resort.
+func call[T any](ctx context.Context, c *Client, method string, fn func(bf.Client) (T, error)) (T, error) {
+	defer c.updateMetrics()
+
+	now := time.Now()
+
+	candidates := make([]*provider, 0, len(c.providers))
+	for _, p := range c.providers {
+		if !p.isDown(now) {
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = c.providers
+	}
+
+	var result T
+	var lastErr error
+	for _, p := range candidates {
+		result, lastErr = fn(p.Client)
+		if lastErr == nil || !isProviderFailure(lastErr) {
+			p.clearDown()
+			return result, lastErr
+		}
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("context done while calling %s: %w", method, ctx.Err())
+		}
+
+		p.markDown(time.Now().Add(failoverCooldown))
+		if c.metrics != nil {
+			c.metrics.ChainDataProviderFailures.WithLabelValues(p.Name).Inc()
+		}
+		c.logger.WarnContext(ctx, "chain data provider failed, trying next provider",
+			slog.String("provider", p.Name),
+			slog.String("method", method),
+			slog.String("error", lastErr.Error()),
+		)
+	}
+
+	return result, lastErr
+}
+
+//nolint:wrapcheck
+func (c *Client) GetLatestEpoch(ctx context.Context) (blockfrost.Epoch, error) {
+	return call(ctx, c, "GetLatestEpoch", func(client bf.Client) (blockfrost.Epoch, error) {
+		return client.GetLatestEpoch(ctx)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetLatestBlock(ctx context.Context) (blockfrost.Block, error) {
+	return call(ctx, c, "GetLatestBlock", func(client bf.Client) (blockfrost.Block, error) {
+		return client.GetLatestBlock(ctx)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetPoolInfo(ctx context.Context, PoolID string) (blockfrost.Pool, error) {
+	return call(ctx, c, "GetPoolInfo", func(client bf.Client) (blockfrost.Pool, error) {
+		return client.GetPoolInfo(ctx, PoolID)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetPoolMetadata(ctx context.Context, PoolID string) (blockfrost.PoolMetadata, error) {
+	return call(ctx, c, "GetPoolMetadata", func(client bf.Client) (blockfrost.PoolMetadata, error) {
+		return client.GetPoolMetadata(ctx, PoolID)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetPoolRelays(ctx context.Context, PoolID string) ([]blockfrost.PoolRelay, error) {
+	return call(ctx, c, "GetPoolRelays", func(client bf.Client) ([]blockfrost.PoolRelay, error) {
+		return client.GetPoolRelays(ctx, PoolID)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetPoolLatestBlocks(ctx context.Context, PoolID string, count int) ([]string, error) {
+	return call(ctx, c, "GetPoolLatestBlocks", func(client bf.Client) ([]string, error) {
+		return client.GetPoolLatestBlocks(ctx, PoolID, count)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetBlockDistributionByPool(ctx context.Context, epoch int, PoolID string) ([]string, error) {
+	return call(ctx, c, "GetBlockDistributionByPool", func(client bf.Client) ([]string, error) {
+		return client.GetBlockDistributionByPool(ctx, epoch, PoolID)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetLastBlockFromPreviousEpoch(ctx context.Context, prevEpoch int) (blockfrost.Block, error) {
+	return call(ctx, c, "GetLastBlockFromPreviousEpoch", func(client bf.Client) (blockfrost.Block, error) {
+		return client.GetLastBlockFromPreviousEpoch(ctx, prevEpoch)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetEpochParameters(ctx context.Context, epoch int) (blockfrost.EpochParameters, error) {
+	return call(ctx, c, "GetEpochParameters", func(client bf.Client) (blockfrost.EpochParameters, error) {
+		return client.GetEpochParameters(ctx, epoch)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetBlockBySlotAndEpoch(ctx context.Context, epoch int, slot int) (blockfrost.Block, error) {
+	return call(ctx, c, "GetBlockBySlotAndEpoch", func(client bf.Client) (blockfrost.Block, error) {
+		return client.GetBlockBySlotAndEpoch(ctx, epoch, slot)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetBlockBySlot(ctx context.Context, slot int) (blockfrost.Block, error) {
+	return call(ctx, c, "GetBlockBySlot", func(client bf.Client) (blockfrost.Block, error) {
+		return client.GetBlockBySlot(ctx, slot)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetBlockByHeight(ctx context.Context, height int) (blockfrost.Block, error) {
+	return call(ctx, c, "GetBlockByHeight", func(client bf.Client) (blockfrost.Block, error) {
+		return client.GetBlockByHeight(ctx, height)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetBlockByHash(ctx context.Context, hash string) (blockfrost.Block, error) {
+	return call(ctx, c, "GetBlockByHash", func(client bf.Client) (blockfrost.Block, error) {
+		return client.GetBlockByHash(ctx, hash)
+	})
+}
+
+// Health probes every provider, including standby ones, so the per-provider
+// metrics reflect the true state of each of them. The client is healthy as
+// long as one provider is.
+func (c *Client) Health(ctx context.Context) (blockfrost.Health, error) {
+	defer c.updateMetrics()
+
+	var errs []error
+	for _, p := range c.providers {
+		health, err := p.Client.Health(ctx)
+		if err != nil || !health.IsHealthy {
+			p.markDown(time.Now().Add(failoverCooldown))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("provider %s: %w", p.Name, err))
+			}
+			continue
+		}
+		p.clearDown()
+	}
+
+	for _, p := range c.providers {
+		if !p.isDown(time.Now()) {
+			return blockfrost.Health{IsHealthy: true}, nil
+		}
+	}
+	return blockfrost.Health{IsHealthy: false}, errors.Join(errs...)
+}
+
+//nolint:wrapcheck
+func (c *Client) GetFirstSlotInEpoch(ctx context.Context, epoch int) (int, error) {
+	return call(ctx, c, "GetFirstSlotInEpoch", func(client bf.Client) (int, error) {
+		return client.GetFirstSlotInEpoch(ctx, epoch)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetFirstBlockInEpoch(ctx context.Context, epoch int) (blockfrost.Block, error) {
+	return call(ctx, c, "GetFirstBlockInEpoch", func(client bf.Client) (blockfrost.Block, error) {
+		return client.GetFirstBlockInEpoch(ctx, epoch)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetGenesisInfo(ctx context.Context) (blockfrost.GenesisBlock, error) {
+	return call(ctx, c, "GetGenesisInfo", func(client bf.Client) (blockfrost.GenesisBlock, error) {
+		return client.GetGenesisInfo(ctx)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetAllPools(ctx context.Context) ([]string, error) {
+	return call(ctx, c, "GetAllPools", func(client bf.Client) ([]string, error) {
+		return client.GetAllPools(ctx)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetRetiringPools(ctx context.Context) ([]blockfrost.PoolRetiring, error) {
+	return call(ctx, c, "GetRetiringPools", func(client bf.Client) ([]blockfrost.PoolRetiring, error) {
+		return client.GetRetiringPools(ctx)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetNetworkInfo(ctx context.Context) (blockfrost.NetworkInfo, error) {
+	return call(ctx, c, "GetNetworkInfo", func(client bf.Client) (blockfrost.NetworkInfo, error) {
+		return client.GetNetworkInfo(ctx)
+	})
+}
+
+//nolint:wrapcheck
+func (c *Client) GetAccountInfo(ctx context.Context, stakeAddress string) (bf.Account, error) {
+	return call(ctx, c, "GetAccountInfo", func(client bf.Client) (bf.Account, error) {
+		return client.GetAccountInfo(ctx, stakeAddress)
+	})
+}
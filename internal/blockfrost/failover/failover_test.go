@@ -0,0 +1,135 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	bf "github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
+	blockfrostmocks "github.com/kilnfi/cardano-validator-watcher/internal/blockfrost/mocks"
+	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
+	promutils "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, m *metrics.Collection) (*Client, *blockfrostmocks.MockClient, *blockfrostmocks.MockClient) {
+	t.Helper()
+
+	primary := blockfrostmocks.NewMockClient(t)
+	secondary := blockfrostmocks.NewMockClient(t)
+	client, err := NewClient([]Provider{
+		{Name: "primary", Client: primary},
+		{Name: "secondary", Client: secondary},
+	}, m)
+	require.NoError(t, err)
+
+	return client, primary, secondary
+}
+
+func TestNewClient_NoProviders(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClient(nil, nil)
+	require.Error(t, err)
+}
+
+func TestClient_UsesPrimaryProvider(t *testing.T) {
+	t.Parallel()
+
+	client, primary, _ := newTestClient(t, nil)
+	primary.EXPECT().GetLatestEpoch(context.Background()).Return(blockfrost.Epoch{Epoch: 100}, nil)
+
+	epoch, err := client.GetLatestEpoch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 100, epoch.Epoch)
+}
+
+func TestClient_FailsOverAndMarksProviderDown(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.NewCollection()
+	client, primary, secondary := newTestClient(t, m)
+
+	// The primary is only called once: it is in cooldown for the second call.
+	primary.EXPECT().GetLatestBlock(context.Background()).Return(blockfrost.Block{}, errors.New("connection refused")).Once()
+	secondary.EXPECT().GetLatestBlock(context.Background()).Return(blockfrost.Block{Height: 42}, nil).Twice()
+
+	block, err := client.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 42, block.Height)
+
+	require.True(t, client.providers[0].isDown(time.Now()), "failed primary should be in cooldown")
+	require.False(t, client.providers[1].isDown(time.Now()), "healthy provider should not be in cooldown")
+
+	block, err = client.GetLatestBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 42, block.Height)
+
+	require.InDelta(t, 0.0, promutils.ToFloat64(m.ChainDataProviderActive.WithLabelValues("primary")), 0.0001)
+	require.InDelta(t, 1.0, promutils.ToFloat64(m.ChainDataProviderActive.WithLabelValues("secondary")), 0.0001)
+	require.InDelta(t, 0.0, promutils.ToFloat64(m.ChainDataProviderUp.WithLabelValues("primary")), 0.0001)
+	require.InDelta(t, 1.0, promutils.ToFloat64(m.ChainDataProviderFailures.WithLabelValues("primary")), 0.0001)
+}
+
+func TestClient_NotFoundDoesNotFailOver(t *testing.T) {
+	t.Parallel()
+
+	client, primary, _ := newTestClient(t, nil)
+
+	primary.EXPECT().GetBlockBySlot(context.Background(), 1).Return(blockfrost.Block{}, bf.ErrNotFound)
+
+	_, err := client.GetBlockBySlot(context.Background(), 1)
+	require.ErrorIs(t, err, bf.ErrNotFound)
+
+	require.False(t, client.providers[0].isDown(time.Now()))
+}
+
+func TestClient_AllProvidersDown(t *testing.T) {
+	t.Parallel()
+
+	client, primary, secondary := newTestClient(t, nil)
+
+	primary.EXPECT().GetAllPools(context.Background()).Return(nil, errors.New("primary down"))
+	secondary.EXPECT().GetAllPools(context.Background()).Return(nil, errors.New("secondary down"))
+
+	_, err := client.GetAllPools(context.Background())
+	require.ErrorContains(t, err, "secondary down")
+
+	// Every provider is in cooldown: they are all retried as a last resort.
+	_, err = client.GetAllPools(context.Background())
+	require.Error(t, err)
+	primary.AssertNumberOfCalls(t, "GetAllPools", 2)
+}
+
+func TestClient_Health(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_OneProviderHealthy", func(t *testing.T) {
+		t.Parallel()
+
+		m := metrics.NewCollection()
+		client, primary, secondary := newTestClient(t, m)
+		primary.EXPECT().Health(context.Background()).Return(blockfrost.Health{IsHealthy: false}, nil)
+		secondary.EXPECT().Health(context.Background()).Return(blockfrost.Health{IsHealthy: true}, nil)
+
+		health, err := client.Health(context.Background())
+		require.NoError(t, err)
+		require.True(t, health.IsHealthy)
+		require.InDelta(t, 0.0, promutils.ToFloat64(m.ChainDataProviderUp.WithLabelValues("primary")), 0.0001)
+		require.InDelta(t, 1.0, promutils.ToFloat64(m.ChainDataProviderActive.WithLabelValues("secondary")), 0.0001)
+	})
+
+	t.Run("SadPath_AllProvidersUnhealthy", func(t *testing.T) {
+		t.Parallel()
+
+		client, primary, secondary := newTestClient(t, nil)
+		primary.EXPECT().Health(context.Background()).Return(blockfrost.Health{}, errors.New("primary down"))
+		secondary.EXPECT().Health(context.Background()).Return(blockfrost.Health{IsHealthy: false}, nil)
+
+		health, err := client.Health(context.Background())
+		require.ErrorContains(t, err, "provider primary: primary down")
+		require.False(t, health.IsHealthy)
+	})
+}
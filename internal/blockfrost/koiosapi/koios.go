@@ -0,0 +1,555 @@
+package koiosapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	bf "github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
+)
+
+// Maximum number of rows returned by Koios in a single response.
+const pageSize = 1000
+
+type Client struct {
+	httpClient *http.Client
+	apiURL     string
+	apiKey     string
+}
+
+var _ bf.Client = (*Client)(nil)
+
+type ClientOptions struct {
+	// Server is the base URL of the Koios API, e.g. https://api.koios.rest/api/v1
+	Server string
+	// APIKey is the optional bearer token used to get higher rate limits.
+	APIKey  string
+	Timeout time.Duration
+	// Transport is the optional round tripper used to send the requests,
+	// e.g. to rate limit and retry them. It defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+func NewClient(opts ClientOptions) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: opts.Transport,
+		},
+		apiURL: opts.Server,
+		apiKey: opts.APIKey,
+	}
+}
+
+func (c *Client) GetLatestEpoch(ctx context.Context) (blockfrost.Epoch, error) {
+	tip, err := c.getTip(ctx)
+	if err != nil {
+		return blockfrost.Epoch{}, err
+	}
+
+	epochs := []epochInfo{}
+	query := url.Values{"_epoch_no": {strconv.Itoa(tip.EpochNo)}}
+	if err := c.get(ctx, "epoch_info", query, &epochs); err != nil {
+		return blockfrost.Epoch{}, fmt.Errorf("failed to get epoch info: %w", err)
+	}
+	if len(epochs) == 0 {
+		return blockfrost.Epoch{}, fmt.Errorf("epoch %d %w", tip.EpochNo, bf.ErrNotFound)
+	}
+
+	epoch := epochs[0]
+	return blockfrost.Epoch{
+		ActiveStake:    epoch.ActiveStake,
+		BlockCount:     epoch.BlkCount,
+		EndTime:        epoch.EndTime,
+		Epoch:          epoch.EpochNo,
+		Fees:           epoch.Fees,
+		FirstBlockTime: epoch.FirstBlockTime,
+		LastBlockTime:  epoch.LastBlockTime,
+		Output:         epoch.OutSum,
+		StartTime:      epoch.StartTime,
+		TxCount:        epoch.TxCount,
+	}, nil
+}
+
+func (c *Client) GetLatestBlock(ctx context.Context) (blockfrost.Block, error) {
+	return c.getBlock(ctx, url.Values{"order": {"block_height.desc"}}, "latest block")
+}
+
+func (c *Client) GetPoolInfo(ctx context.Context, PoolID string) (blockfrost.Pool, error) {
+	pool, err := c.getPoolInfo(ctx, PoolID)
+	if err != nil {
+		return blockfrost.Pool{}, err
+	}
+
+	return blockfrost.Pool{
+		PoolID:         pool.PoolIDBech32,
+		Hex:            pool.PoolIDHex,
+		VrfKey:         pool.VrfKeyHash,
+		BlocksMinted:   pool.BlockCount,
+		LiveStake:      valueOrEmpty(pool.LiveStake),
+		LiveSaturation: valueOrZero(pool.LiveSaturation),
+		LiveDelegators: pool.LiveDelegators,
+		ActiveStake:    valueOrEmpty(pool.ActiveStake),
+		ActiveSize:     valueOrZero(pool.Sigma),
+		DeclaredPledge: pool.Pledge,
+		LivePledge:     valueOrEmpty(pool.LivePledge),
+		MarginCost:     pool.Margin,
+		FixedCost:      pool.FixedCost,
+		RewardAccount:  pool.RewardAddr,
+		Owners:         pool.Owners,
+	}, nil
+}
+
+func (c *Client) GetPoolMetadata(ctx context.Context, PoolID string) (blockfrost.PoolMetadata, error) {
+	pool, err := c.getPoolInfo(ctx, PoolID)
+	if err != nil {
+		return blockfrost.PoolMetadata{}, err
+	}
+
+	metadata := blockfrost.PoolMetadata{
+		PoolID: pool.PoolIDBech32,
+		Hex:    pool.PoolIDHex,
+		URL:    pool.MetaURL,
+		Hash:   pool.MetaHash,
+	}
+	if pool.MetaJSON != nil {
+		metadata.Ticker = pool.MetaJSON.Ticker
+		metadata.Name = pool.MetaJSON.Name
+		metadata.Description = pool.MetaJSON.Description
+		metadata.Homepage = pool.MetaJSON.Homepage
+	}
+	return metadata, nil
+}
+
+func (c *Client) GetPoolRelays(ctx context.Context, PoolID string) ([]blockfrost.PoolRelay, error) {
+	pool, err := c.getPoolInfo(ctx, PoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	relays := make([]blockfrost.PoolRelay, 0, len(pool.Relays))
+	for _, relay := range pool.Relays {
+		relays = append(relays, blockfrost.PoolRelay{
+			Ipv4:   relay.Ipv4,
+			Ipv6:   relay.Ipv6,
+			DNS:    relay.DNS,
+			DNSSrv: relay.Srv,
+			Port:   valueOrZero(relay.Port),
+		})
+	}
+	return relays, nil
+}
+
+// GetPoolLatestBlocks returns the hashes of the latest blocks forged by a pool,
+// the most recent first.
+func (c *Client) GetPoolLatestBlocks(ctx context.Context, PoolID string, count int) ([]string, error) {
+	blocks := []poolBlock{}
+	query := url.Values{
+		"_pool_bech32": {PoolID},
+		"order":        {"block_height.desc"},
+		"limit":        {strconv.Itoa(count)},
+	}
+	if err := c.get(ctx, "pool_blocks", query, &blocks); err != nil {
+		return nil, fmt.Errorf("failed to get latest blocks of pool %s: %w", PoolID, err)
+	}
+
+	results := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		results = append(results, block.BlockHash)
+	}
+	return results, nil
+}
+
+func (c *Client) GetBlockDistributionByPool(ctx context.Context, epoch int, PoolID string) ([]string, error) {
+	results := []string{}
+	for offset := 0; ; offset += pageSize {
+		blocks := []poolBlock{}
+		query := url.Values{
+			"_pool_bech32": {PoolID},
+			"_epoch_no":    {strconv.Itoa(epoch)},
+			"offset":       {strconv.Itoa(offset)},
+			"limit":        {strconv.Itoa(pageSize)},
+		}
+		if err := c.get(ctx, "pool_blocks", query, &blocks); err != nil {
+			return nil, fmt.Errorf("failed to get blocks of pool %s in epoch %d: %w", PoolID, epoch, err)
+		}
+
+		for _, block := range blocks {
+			results = append(results, block.BlockHash)
+		}
+		if len(blocks) < pageSize {
+			return results, nil
+		}
+	}
+}
+
+func (c *Client) GetLastBlockFromPreviousEpoch(ctx context.Context, prevEpoch int) (blockfrost.Block, error) {
+	query := url.Values{
+		"epoch_no": {"eq." + strconv.Itoa(prevEpoch)},
+		"order":    {"block_height.desc"},
+	}
+	return c.getBlock(ctx, query, fmt.Sprintf("last block of epoch %d", prevEpoch))
+}
+
+func (c *Client) GetEpochParameters(ctx context.Context, epoch int) (blockfrost.EpochParameters, error) {
+	params := []epochParams{}
+	if err := c.get(ctx, "epoch_params", url.Values{"_epoch_no": {strconv.Itoa(epoch)}}, &params); err != nil {
+		return blockfrost.EpochParameters{}, fmt.Errorf("failed to get parameters of epoch %d: %w", epoch, err)
+	}
+	if len(params) == 0 {
+		return blockfrost.EpochParameters{}, fmt.Errorf("parameters of epoch %d %w", epoch, bf.ErrNotFound)
+	}
+
+	p := params[0]
+	return blockfrost.EpochParameters{
+		A0:                    p.Influence,
+		DecentralisationParam: p.Decentralisation,
+		EMax:                  p.MaxEpoch,
+		Epoch:                 p.EpochNo,
+		KeyDeposit:            p.KeyDeposit,
+		MaxBlockHeaderSize:    p.MaxBhSize,
+		MaxBlockSize:          p.MaxBlockSize,
+		MaxTxSize:             p.MaxTxSize,
+		MinFeeA:               p.MinFeeA,
+		MinFeeB:               p.MinFeeB,
+		MinPoolCost:           p.MinPoolCost,
+		NOpt:                  p.OptimalPoolCount,
+		Nonce:                 p.Nonce,
+		PoolDeposit:           p.PoolDeposit,
+		ProtocolMajorVer:      p.ProtocolMajor,
+		ProtocolMinorVer:      p.ProtocolMinor,
+		Rho:                   p.MonetaryExpandRate,
+		Tau:                   p.TreasuryGrowthRate,
+	}, nil
+}
+
+func (c *Client) GetBlockBySlotAndEpoch(ctx context.Context, slot int, epoch int) (blockfrost.Block, error) {
+	query := url.Values{
+		"epoch_no":   {"eq." + strconv.Itoa(epoch)},
+		"epoch_slot": {"eq." + strconv.Itoa(slot)},
+	}
+	return c.getBlock(ctx, query, fmt.Sprintf("block at slot %d of epoch %d", slot, epoch))
+}
+
+func (c *Client) GetBlockBySlot(ctx context.Context, slot int) (blockfrost.Block, error) {
+	return c.getBlock(ctx, url.Values{"abs_slot": {"eq." + strconv.Itoa(slot)}}, fmt.Sprintf("block at slot %d", slot))
+}
+
+func (c *Client) GetBlockByHeight(ctx context.Context, height int) (blockfrost.Block, error) {
+	return c.getBlock(ctx, url.Values{"block_height": {"eq." + strconv.Itoa(height)}}, fmt.Sprintf("block at height %d", height))
+}
+
+// GetBlockByHash returns a block from its hash. Koios does not serve the hash
+// of the operational certificate of the blocks, only its counter.
+func (c *Client) GetBlockByHash(ctx context.Context, hash string) (blockfrost.Block, error) {
+	return c.getBlock(ctx, url.Values{"hash": {"eq." + hash}}, fmt.Sprintf("block %s", hash))
+}
+
+// Health reports Koios as healthy when it is able to serve the tip of the chain.
+func (c *Client) Health(ctx context.Context) (blockfrost.Health, error) {
+	if _, err := c.getTip(ctx); err != nil {
+		return blockfrost.Health{IsHealthy: false}, err
+	}
+	return blockfrost.Health{IsHealthy: true}, nil
+}
+
+func (c *Client) GetFirstSlotInEpoch(ctx context.Context, epoch int) (int, error) {
+	block, err := c.GetFirstBlockInEpoch(ctx, epoch)
+	if err != nil {
+		return 0, err
+	}
+	return block.Slot, nil
+}
+
+func (c *Client) GetFirstBlockInEpoch(ctx context.Context, epoch int) (blockfrost.Block, error) {
+	query := url.Values{
+		"epoch_no": {"eq." + strconv.Itoa(epoch)},
+		"order":    {"block_height.asc"},
+	}
+	return c.getBlock(ctx, query, fmt.Sprintf("first block of epoch %d", epoch))
+}
+
+func (c *Client) GetGenesisInfo(ctx context.Context) (blockfrost.GenesisBlock, error) {
+	results := []genesis{}
+	if err := c.get(ctx, "genesis", nil, &results); err != nil {
+		return blockfrost.GenesisBlock{}, fmt.Errorf("failed to get genesis: %w", err)
+	}
+	if len(results) == 0 {
+		return blockfrost.GenesisBlock{}, fmt.Errorf("genesis %w", bf.ErrNotFound)
+	}
+
+	// Koios serves the genesis parameters as strings
+	g := results[0]
+	ints := map[string]int{}
+	for name, value := range map[string]string{
+		"networkmagic":      g.NetworkMagic,
+		"epochlength":       g.EpochLength,
+		"slotlength":        g.SlotLength,
+		"slotsperkesperiod": g.SlotsPerKesPeriod,
+		"maxkesrevolutions": g.MaxKesRevolutions,
+		"securityparam":     g.SecurityParam,
+	} {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return blockfrost.GenesisBlock{}, fmt.Errorf("failed to parse genesis %s: %w", name, err)
+		}
+		ints[name] = parsed
+	}
+
+	activeSlotsCoeff, err := strconv.ParseFloat(g.ActiveSlotCoeff, 32)
+	if err != nil {
+		return blockfrost.GenesisBlock{}, fmt.Errorf("failed to parse genesis activeslotcoeff: %w", err)
+	}
+	updateQuorum, err := strconv.ParseFloat(g.UpdateQuorum, 32)
+	if err != nil {
+		return blockfrost.GenesisBlock{}, fmt.Errorf("failed to parse genesis updatequorum: %w", err)
+	}
+
+	return blockfrost.GenesisBlock{
+		ActiveSlotsCoefficient: float32(activeSlotsCoeff),
+		UpdateQuorum:           float32(updateQuorum),
+		MaxLovelaceSupply:      g.MaxLovelaceSupply,
+		NetworkMagic:           ints["networkmagic"],
+		EpochLength:            ints["epochlength"],
+		SystemStart:            g.SystemStart,
+		SlotsPerKesPeriod:      ints["slotsperkesperiod"],
+		SlotLength:             ints["slotlength"],
+		MaxKesEvolutions:       ints["maxkesrevolutions"],
+		SecurityParam:          ints["securityparam"],
+	}, nil
+}
+
+func (c *Client) GetAllPools(ctx context.Context) ([]string, error) {
+	results := []string{}
+	for offset := 0; ; offset += pageSize {
+		pools := []poolListItem{}
+		query := url.Values{
+			"select":      {"pool_id_bech32"},
+			"pool_status": {"eq.registered"},
+			"offset":      {strconv.Itoa(offset)},
+			"limit":       {strconv.Itoa(pageSize)},
+		}
+		if err := c.get(ctx, "pool_list", query, &pools); err != nil {
+			return nil, fmt.Errorf("failed to list pools: %w", err)
+		}
+
+		for _, pool := range pools {
+			results = append(results, pool.PoolIDBech32)
+		}
+		if len(pools) < pageSize {
+			return results, nil
+		}
+	}
+}
+
+// GetRetiringPools returns the pools with a retirement scheduled and the epoch
+// at which they retire.
+func (c *Client) GetRetiringPools(ctx context.Context) ([]blockfrost.PoolRetiring, error) {
+	results := []blockfrost.PoolRetiring{}
+	for offset := 0; ; offset += pageSize {
+		pools := []poolListItem{}
+		query := url.Values{
+			"select":      {"pool_id_bech32,retiring_epoch"},
+			"pool_status": {"eq.retiring"},
+			"offset":      {strconv.Itoa(offset)},
+			"limit":       {strconv.Itoa(pageSize)},
+		}
+		if err := c.get(ctx, "pool_list", query, &pools); err != nil {
+			return nil, fmt.Errorf("failed to list retiring pools: %w", err)
+		}
+
+		for _, pool := range pools {
+			results = append(results, blockfrost.PoolRetiring{
+				PoolID: pool.PoolIDBech32,
+				Epoch:  valueOrZero(pool.RetiringEpoch),
+			})
+		}
+		if len(pools) < pageSize {
+			return results, nil
+		}
+	}
+}
+
+// GetNetworkInfo returns the supply and the active stake of the current epoch.
+// Koios does not expose the live stake of the network so it is left empty.
+func (c *Client) GetNetworkInfo(ctx context.Context) (blockfrost.NetworkInfo, error) {
+	epoch, err := c.GetLatestEpoch(ctx)
+	if err != nil {
+		return blockfrost.NetworkInfo{}, err
+	}
+
+	supplies := []totals{}
+	if err := c.get(ctx, "totals", url.Values{"_epoch_no": {strconv.Itoa(epoch.Epoch)}}, &supplies); err != nil {
+		return blockfrost.NetworkInfo{}, fmt.Errorf("failed to get totals: %w", err)
+	}
+	if len(supplies) == 0 {
+		return blockfrost.NetworkInfo{}, fmt.Errorf("totals of epoch %d %w", epoch.Epoch, bf.ErrNotFound)
+	}
+
+	return blockfrost.NetworkInfo{
+		Supply: blockfrost.NetworkSupply{
+			Total:       supplies[0].Supply,
+			Circulating: supplies[0].Circulation,
+		},
+		Stake: blockfrost.NetworkStake{
+			Active: valueOrEmpty(epoch.ActiveStake),
+		},
+	}, nil
+}
+
+func (c *Client) GetAccountInfo(ctx context.Context, stakeAddress string) (bf.Account, error) {
+	accounts := []accountInfo{}
+	body := map[string][]string{"_stake_addresses": {stakeAddress}}
+	if err := c.post(ctx, "account_info", body, &accounts); err != nil {
+		return bf.Account{}, fmt.Errorf("failed to get account details: %w", err)
+	}
+	if len(accounts) == 0 {
+		return bf.Account{}, fmt.Errorf("account %s %w", stakeAddress, bf.ErrNotFound)
+	}
+
+	account := accounts[0]
+	return bf.Account{
+		StakeAddress:       account.StakeAddress,
+		Active:             account.Status == "registered",
+		ControlledAmount:   account.TotalBalance,
+		RewardsSum:         account.Rewards,
+		WithdrawalsSum:     account.Withdrawals,
+		ReservesSum:        account.Reserves,
+		TreasurySum:        account.Treasury,
+		WithdrawableAmount: account.RewardsAvailable,
+		PoolID:             account.DelegatedPool,
+		DrepID:             account.DelegatedDrep,
+	}, nil
+}
+
+func (c *Client) getTip(ctx context.Context) (tip, error) {
+	tips := []tip{}
+	if err := c.get(ctx, "tip", nil, &tips); err != nil {
+		return tip{}, fmt.Errorf("failed to get tip: %w", err)
+	}
+	if len(tips) == 0 {
+		return tip{}, fmt.Errorf("tip %w", bf.ErrNotFound)
+	}
+	return tips[0], nil
+}
+
+// getBlock returns the first block matching the given filters.
+func (c *Client) getBlock(ctx context.Context, query url.Values, description string) (blockfrost.Block, error) {
+	query.Set("limit", "1")
+
+	blocks := []block{}
+	if err := c.get(ctx, "blocks", query, &blocks); err != nil {
+		return blockfrost.Block{}, fmt.Errorf("failed to get %s: %w", description, err)
+	}
+	if len(blocks) == 0 {
+		return blockfrost.Block{}, fmt.Errorf("%s %w", description, bf.ErrNotFound)
+	}
+
+	b := blocks[0]
+	block := blockfrost.Block{
+		Time:          b.BlockTime,
+		Height:        b.BlockHeight,
+		Hash:          b.Hash,
+		Slot:          b.AbsSlot,
+		Epoch:         b.EpochNo,
+		EpochSlot:     b.EpochSlot,
+		SlotLeader:    valueOrEmpty(b.Pool),
+		Size:          b.BlockSize,
+		TxCount:       b.TxCount,
+		BlockVRF:      b.VrfKey,
+		PreviousBlock: b.ParentHash,
+	}
+	if b.OpCertCounter != nil {
+		counter := strconv.Itoa(*b.OpCertCounter)
+		block.OPCertCounter = &counter
+	}
+	return block, nil
+}
+
+func (c *Client) getPoolInfo(ctx context.Context, PoolID string) (poolInfo, error) {
+	pools := []poolInfo{}
+	body := map[string][]string{"_pool_bech32_ids": {PoolID}}
+	if err := c.post(ctx, "pool_info", body, &pools); err != nil {
+		return poolInfo{}, fmt.Errorf("failed to get pool info for %s: %w", PoolID, err)
+	}
+	if len(pools) == 0 {
+		return poolInfo{}, fmt.Errorf("pool %s %w", PoolID, bf.ErrNotFound)
+	}
+	return pools[0], nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+func (c *Client) post(ctx context.Context, path string, body any, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	return c.do(ctx, http.MethodPost, path, nil, bytes.NewReader(payload), out)
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, body io.Reader, out any) error {
+	endpoint, err := url.JoinPath(c.apiURL, path)
+	if err != nil {
+		return fmt.Errorf("failed to join URL path: %w", err)
+	}
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", bf.TransportError(err))
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %w", path, bf.StatusError(resp.StatusCode, strings.TrimSpace(string(content))))
+	}
+
+	if err := json.Unmarshal(content, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func valueOrZero[T int | float64](value *T) T {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -0,0 +1,382 @@
+package koiosapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/blockfrost/blockfrost-go"
+	bf "github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupClient(t *testing.T, mux *http.ServeMux) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	serverURL, _ := url.JoinPath(server.URL, "/api/v1")
+	return NewClient(ClientOptions{
+		Server: serverURL,
+		APIKey: "apikey",
+	})
+}
+
+func writeJSON(t *testing.T, res http.ResponseWriter, payload any) {
+	t.Helper()
+
+	content, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("could not marshal response: %v", err)
+	}
+	res.WriteHeader(http.StatusOK)
+	if _, err := res.Write(content); err != nil {
+		t.Fatalf("could not write response: %v", err)
+	}
+}
+
+func TestGetLatestEpoch(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/tip", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "Bearer apikey", req.Header.Get("Authorization"))
+		writeJSON(t, res, []tip{{EpochNo: 100, AbsSlot: 1000}})
+	})
+	mux.HandleFunc("/api/v1/epoch_info", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "100", req.URL.Query().Get("_epoch_no"))
+		writeJSON(t, res, []epochInfo{
+			{
+				EpochNo:        100,
+				OutSum:         "7849943934049314",
+				Fees:           "4203312194",
+				TxCount:        17856,
+				BlkCount:       21298,
+				StartTime:      1603403091,
+				EndTime:        1603835086,
+				FirstBlockTime: 1603403092,
+				LastBlockTime:  1603835084,
+				ActiveStake:    &[]string{"784953934049314"}[0],
+			},
+		})
+	})
+
+	client := setupClient(t, mux)
+	epoch, err := client.GetLatestEpoch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, blockfrost.Epoch{
+		ActiveStake:    &[]string{"784953934049314"}[0],
+		BlockCount:     21298,
+		EndTime:        1603835086,
+		Epoch:          100,
+		Fees:           "4203312194",
+		FirstBlockTime: 1603403092,
+		LastBlockTime:  1603835084,
+		Output:         "7849943934049314",
+		StartTime:      1603403091,
+		TxCount:        17856,
+	}, epoch)
+}
+
+func TestGetBlockBySlot(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/blocks", func(res http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("abs_slot") != "eq.412162133" {
+			writeJSON(t, res, []block{})
+			return
+		}
+		writeJSON(t, res, []block{
+			{
+				Hash:          "4ea1ba291e8eef538635a53e59fddba7810d1679631cc3aed7c8e6c4091a516a",
+				EpochNo:       425,
+				AbsSlot:       412162133,
+				EpochSlot:     12,
+				BlockHeight:   15243593,
+				BlockTime:     1641338934,
+				Pool:          &[]string{"pool-0"}[0],
+				OpCertCounter: &[]int{4}[0],
+				ParentHash:    "parent",
+			},
+		})
+	})
+
+	client := setupClient(t, mux)
+
+	t.Run("GoodPath_BlockFound", func(t *testing.T) {
+		block, err := client.GetBlockBySlot(ctx, 412162133)
+		require.NoError(t, err)
+		assert.Equal(t, blockfrost.Block{
+			Time:          1641338934,
+			Height:        15243593,
+			Hash:          "4ea1ba291e8eef538635a53e59fddba7810d1679631cc3aed7c8e6c4091a516a",
+			Slot:          412162133,
+			Epoch:         425,
+			EpochSlot:     12,
+			SlotLeader:    "pool-0",
+			OPCertCounter: &[]string{"4"}[0],
+			PreviousBlock: "parent",
+		}, block)
+	})
+
+	t.Run("SadPath_BlockNotFound", func(t *testing.T) {
+		_, err := client.GetBlockBySlot(ctx, 412162134)
+		require.ErrorIs(t, err, bf.ErrNotFound)
+	})
+}
+
+func TestGetPoolLatestBlocks(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/pool_blocks", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "pool-0", req.URL.Query().Get("_pool_bech32"))
+		assert.Equal(t, "block_height.desc", req.URL.Query().Get("order"))
+		assert.Equal(t, "2", req.URL.Query().Get("limit"))
+		writeJSON(t, res, []poolBlock{{BlockHash: "block-2"}, {BlockHash: "block-1"}})
+	})
+
+	client := setupClient(t, mux)
+	hashes, err := client.GetPoolLatestBlocks(ctx, "pool-0", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"block-2", "block-1"}, hashes)
+}
+
+func TestGetPoolInfo(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/pool_info", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		body := map[string][]string{}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, []string{"pool-0"}, body["_pool_bech32_ids"])
+
+		writeJSON(t, res, []poolInfo{
+			{
+				PoolIDBech32:   "pool-0",
+				PoolIDHex:      "pool-0-hex",
+				VrfKeyHash:     "pool-0-vrf",
+				Margin:         0.01,
+				FixedCost:      "340000000",
+				Pledge:         "100",
+				RewardAddr:     "stake-0",
+				Owners:         []string{"stake-0"},
+				BlockCount:     70,
+				LiveStake:      &[]string{"10000"}[0],
+				ActiveStake:    &[]string{"10000"}[0],
+				LivePledge:     &[]string{"200"}[0],
+				LiveSaturation: &[]float64{0.5}[0],
+				Relays: []poolRelay{
+					{
+						Ipv4: &[]string{"10.0.0.1"}[0],
+						DNS:  &[]string{"relay-0.example.com"}[0],
+						Port: &[]int{3001}[0],
+					},
+				},
+				MetaURL:  &[]string{"https://example.com/pool-0.json"}[0],
+				MetaHash: &[]string{"hash"}[0],
+				MetaJSON: &poolMetaJSON{
+					Name:   &[]string{"Pool-0"}[0],
+					Ticker: &[]string{"POOL0"}[0],
+				},
+			},
+		})
+	})
+
+	client := setupClient(t, mux)
+
+	pool, err := client.GetPoolInfo(ctx, "pool-0")
+	require.NoError(t, err)
+	assert.Equal(t, blockfrost.Pool{
+		PoolID:         "pool-0",
+		Hex:            "pool-0-hex",
+		VrfKey:         "pool-0-vrf",
+		BlocksMinted:   70,
+		LiveStake:      "10000",
+		LiveSaturation: 0.5,
+		ActiveStake:    "10000",
+		DeclaredPledge: "100",
+		LivePledge:     "200",
+		MarginCost:     0.01,
+		FixedCost:      "340000000",
+		RewardAccount:  "stake-0",
+		Owners:         []string{"stake-0"},
+	}, pool)
+
+	metadata, err := client.GetPoolMetadata(ctx, "pool-0")
+	require.NoError(t, err)
+	assert.Equal(t, blockfrost.PoolMetadata{
+		PoolID: "pool-0",
+		Hex:    "pool-0-hex",
+		URL:    &[]string{"https://example.com/pool-0.json"}[0],
+		Hash:   &[]string{"hash"}[0],
+		Name:   &[]string{"Pool-0"}[0],
+		Ticker: &[]string{"POOL0"}[0],
+	}, metadata)
+
+	relays, err := client.GetPoolRelays(ctx, "pool-0")
+	require.NoError(t, err)
+	assert.Equal(t, []blockfrost.PoolRelay{
+		{
+			Ipv4: &[]string{"10.0.0.1"}[0],
+			DNS:  &[]string{"relay-0.example.com"}[0],
+			Port: 3001,
+		},
+	}, relays)
+}
+
+func TestGetAccountInfo(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/account_info", func(res http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, res, []accountInfo{
+			{
+				StakeAddress:     "stake-0",
+				Status:           "registered",
+				DelegatedPool:    &[]string{"pool-0"}[0],
+				TotalBalance:     "1000",
+				Rewards:          "10",
+				Withdrawals:      "5",
+				RewardsAvailable: "5",
+				Reserves:         "0",
+				Treasury:         "0",
+			},
+		})
+	})
+
+	client := setupClient(t, mux)
+	account, err := client.GetAccountInfo(ctx, "stake-0")
+	require.NoError(t, err)
+	assert.Equal(t, bf.Account{
+		StakeAddress:       "stake-0",
+		Active:             true,
+		ControlledAmount:   "1000",
+		RewardsSum:         "10",
+		WithdrawalsSum:     "5",
+		ReservesSum:        "0",
+		TreasurySum:        "0",
+		WithdrawableAmount: "5",
+		PoolID:             &[]string{"pool-0"}[0],
+	}, account)
+}
+
+func TestGetAllPools(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	// the first page is full so the client must request the next one
+	mux.HandleFunc("/api/v1/pool_list", func(res http.ResponseWriter, req *http.Request) {
+		offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+		pools := []poolListItem{}
+		if offset == 0 {
+			for i := range pageSize {
+				pools = append(pools, poolListItem{PoolIDBech32: "pool-" + strconv.Itoa(i)})
+			}
+		} else {
+			pools = append(pools, poolListItem{PoolIDBech32: "pool-last"})
+		}
+		writeJSON(t, res, pools)
+	})
+
+	client := setupClient(t, mux)
+	pools, err := client.GetAllPools(ctx)
+	require.NoError(t, err)
+	assert.Len(t, pools, pageSize+1)
+	assert.Equal(t, "pool-last", pools[pageSize])
+}
+
+func TestGetRetiringPools(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/pool_list", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "eq.retiring", req.URL.Query().Get("pool_status"))
+		epoch := 520
+		writeJSON(t, res, []poolListItem{{PoolIDBech32: "pool-0", RetiringEpoch: &epoch}})
+	})
+
+	client := setupClient(t, mux)
+	pools, err := client.GetRetiringPools(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []blockfrost.PoolRetiring{{PoolID: "pool-0", Epoch: 520}}, pools)
+}
+
+func TestGetGenesisInfo(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/genesis", func(res http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, res, []genesis{
+			{
+				NetworkMagic:      "764824073",
+				EpochLength:       "432000",
+				SlotLength:        "1",
+				MaxLovelaceSupply: "45000000000000000",
+				SystemStart:       1506203091,
+				ActiveSlotCoeff:   "0.05",
+				SlotsPerKesPeriod: "129600",
+				MaxKesRevolutions: "62",
+				SecurityParam:     "2160",
+				UpdateQuorum:      "5",
+			},
+		})
+	})
+
+	client := setupClient(t, mux)
+	genesis, err := client.GetGenesisInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, blockfrost.GenesisBlock{
+		ActiveSlotsCoefficient: 0.05,
+		UpdateQuorum:           5,
+		MaxLovelaceSupply:      "45000000000000000",
+		NetworkMagic:           764824073,
+		EpochLength:            432000,
+		SystemStart:            1506203091,
+		SlotsPerKesPeriod:      129600,
+		SlotLength:             1,
+		MaxKesEvolutions:       62,
+		SecurityParam:          2160,
+	}, genesis)
+}
+
+func TestErrorClassification(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/tip", func(res http.ResponseWriter, _ *http.Request) {
+		res.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/api/v1/genesis", func(res http.ResponseWriter, _ *http.Request) {
+		res.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/api/v1/pool_list", func(res http.ResponseWriter, _ *http.Request) {
+		res.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	client := setupClient(t, mux)
+
+	t.Run("SadPath_UpstreamUnavailable", func(t *testing.T) {
+		health, err := client.Health(ctx)
+		require.ErrorIs(t, err, bf.ErrUpstreamUnavailable)
+		assert.False(t, health.IsHealthy)
+	})
+
+	t.Run("SadPath_Unauthorized", func(t *testing.T) {
+		_, err := client.GetGenesisInfo(ctx)
+		require.ErrorIs(t, err, bf.ErrUnauthorized)
+	})
+
+	t.Run("SadPath_RateLimited", func(t *testing.T) {
+		_, err := client.GetAllPools(ctx)
+		require.ErrorIs(t, err, bf.ErrRateLimited)
+	})
+}
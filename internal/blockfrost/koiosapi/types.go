@@ -0,0 +1,148 @@
+package koiosapi
+
+// The types below map the responses of the Koios REST API.
+// See https://api.koios.rest for the full specification.
+
+type tip struct {
+	Hash        string `json:"hash"`
+	EpochNo     int    `json:"epoch_no"`
+	AbsSlot     int    `json:"abs_slot"`
+	EpochSlot   int    `json:"epoch_slot"`
+	BlockHeight int    `json:"block_height"`
+	BlockTime   int    `json:"block_time"`
+}
+
+type epochInfo struct {
+	EpochNo        int     `json:"epoch_no"`
+	OutSum         string  `json:"out_sum"`
+	Fees           string  `json:"fees"`
+	TxCount        int     `json:"tx_count"`
+	BlkCount       int     `json:"blk_count"`
+	StartTime      int     `json:"start_time"`
+	EndTime        int     `json:"end_time"`
+	FirstBlockTime int     `json:"first_block_time"`
+	LastBlockTime  int     `json:"last_block_time"`
+	ActiveStake    *string `json:"active_stake"`
+}
+
+type epochParams struct {
+	EpochNo            int     `json:"epoch_no"`
+	MinFeeA            int     `json:"min_fee_a"`
+	MinFeeB            int     `json:"min_fee_b"`
+	MaxBlockSize       int     `json:"max_block_size"`
+	MaxTxSize          int     `json:"max_tx_size"`
+	MaxBhSize          int     `json:"max_bh_size"`
+	KeyDeposit         string  `json:"key_deposit"`
+	PoolDeposit        string  `json:"pool_deposit"`
+	MaxEpoch           int     `json:"max_epoch"`
+	OptimalPoolCount   int     `json:"optimal_pool_count"`
+	Influence          float32 `json:"influence"`
+	MonetaryExpandRate float32 `json:"monetary_expand_rate"`
+	TreasuryGrowthRate float32 `json:"treasury_growth_rate"`
+	Decentralisation   float32 `json:"decentralisation"`
+	ProtocolMajor      int     `json:"protocol_major"`
+	ProtocolMinor      int     `json:"protocol_minor"`
+	MinPoolCost        string  `json:"min_pool_cost"`
+	Nonce              string  `json:"nonce"`
+}
+
+type block struct {
+	Hash          string  `json:"hash"`
+	EpochNo       int     `json:"epoch_no"`
+	AbsSlot       int     `json:"abs_slot"`
+	EpochSlot     int     `json:"epoch_slot"`
+	BlockHeight   int     `json:"block_height"`
+	BlockSize     int     `json:"block_size"`
+	BlockTime     int     `json:"block_time"`
+	TxCount       int     `json:"tx_count"`
+	VrfKey        *string `json:"vrf_key"`
+	Pool          *string `json:"pool"`
+	OpCertCounter *int    `json:"op_cert_counter"`
+	ParentHash    string  `json:"parent_hash"`
+}
+
+type poolBlock struct {
+	EpochNo     int    `json:"epoch_no"`
+	AbsSlot     int    `json:"abs_slot"`
+	BlockHeight int    `json:"block_height"`
+	BlockHash   string `json:"block_hash"`
+}
+
+type poolRelay struct {
+	DNS  *string `json:"dns"`
+	Srv  *string `json:"srv"`
+	Ipv4 *string `json:"ipv4"`
+	Ipv6 *string `json:"ipv6"`
+	Port *int    `json:"port"`
+}
+
+type poolMetaJSON struct {
+	Name        *string `json:"name"`
+	Ticker      *string `json:"ticker"`
+	Homepage    *string `json:"homepage"`
+	Description *string `json:"description"`
+}
+
+type poolInfo struct {
+	PoolIDBech32   string        `json:"pool_id_bech32"`
+	PoolIDHex      string        `json:"pool_id_hex"`
+	VrfKeyHash     string        `json:"vrf_key_hash"`
+	Margin         float64       `json:"margin"`
+	FixedCost      string        `json:"fixed_cost"`
+	Pledge         string        `json:"pledge"`
+	RewardAddr     string        `json:"reward_addr"`
+	Owners         []string      `json:"owners"`
+	Relays         []poolRelay   `json:"relays"`
+	MetaURL        *string       `json:"meta_url"`
+	MetaHash       *string       `json:"meta_hash"`
+	MetaJSON       *poolMetaJSON `json:"meta_json"`
+	PoolStatus     string        `json:"pool_status"`
+	RetiringEpoch  *int          `json:"retiring_epoch"`
+	ActiveStake    *string       `json:"active_stake"`
+	Sigma          *float64      `json:"sigma"`
+	BlockCount     int           `json:"block_count"`
+	LivePledge     *string       `json:"live_pledge"`
+	LiveStake      *string       `json:"live_stake"`
+	LiveDelegators int           `json:"live_delegators"`
+	LiveSaturation *float64      `json:"live_saturation"`
+}
+
+type poolListItem struct {
+	PoolIDBech32  string `json:"pool_id_bech32"`
+	RetiringEpoch *int   `json:"retiring_epoch"`
+}
+
+type genesis struct {
+	NetworkMagic      string `json:"networkmagic"`
+	EpochLength       string `json:"epochlength"`
+	SlotLength        string `json:"slotlength"`
+	MaxLovelaceSupply string `json:"maxlovelacesupply"`
+	SystemStart       int    `json:"systemstart"`
+	ActiveSlotCoeff   string `json:"activeslotcoeff"`
+	SlotsPerKesPeriod string `json:"slotsperkesperiod"`
+	MaxKesRevolutions string `json:"maxkesrevolutions"`
+	SecurityParam     string `json:"securityparam"`
+	UpdateQuorum      string `json:"updatequorum"`
+}
+
+type totals struct {
+	EpochNo     int    `json:"epoch_no"`
+	Circulation string `json:"circulation"`
+	Treasury    string `json:"treasury"`
+	Reward      string `json:"reward"`
+	Supply      string `json:"supply"`
+	Reserves    string `json:"reserves"`
+}
+
+type accountInfo struct {
+	StakeAddress     string  `json:"stake_address"`
+	Status           string  `json:"status"`
+	DelegatedDrep    *string `json:"delegated_drep"`
+	DelegatedPool    *string `json:"delegated_pool"`
+	TotalBalance     string  `json:"total_balance"`
+	Rewards          string  `json:"rewards"`
+	Withdrawals      string  `json:"withdrawals"`
+	RewardsAvailable string  `json:"rewards_available"`
+	Reserves         string  `json:"reserves"`
+	Treasury         string  `json:"treasury"`
+}
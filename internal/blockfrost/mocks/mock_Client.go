@@ -139,6 +139,120 @@ func (_c *MockClient_GetAllPools_Call) RunAndReturn(run func(context.Context) ([
 	return _c
 }
 
+// GetBlockByHash provides a mock function with given fields: ctx, hash
+func (_m *MockClient) GetBlockByHash(ctx context.Context, hash string) (blockfrost_go.Block, error) {
+	ret := _m.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockByHash")
+	}
+
+	var r0 blockfrost_go.Block
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (blockfrost_go.Block, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) blockfrost_go.Block); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		r0 = ret.Get(0).(blockfrost_go.Block)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_GetBlockByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBlockByHash'
+type MockClient_GetBlockByHash_Call struct {
+	*mock.Call
+}
+
+// GetBlockByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+func (_e *MockClient_Expecter) GetBlockByHash(ctx interface{}, hash interface{}) *MockClient_GetBlockByHash_Call {
+	return &MockClient_GetBlockByHash_Call{Call: _e.mock.On("GetBlockByHash", ctx, hash)}
+}
+
+func (_c *MockClient_GetBlockByHash_Call) Run(run func(ctx context.Context, hash string)) *MockClient_GetBlockByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockClient_GetBlockByHash_Call) Return(_a0 blockfrost_go.Block, _a1 error) *MockClient_GetBlockByHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_GetBlockByHash_Call) RunAndReturn(run func(context.Context, string) (blockfrost_go.Block, error)) *MockClient_GetBlockByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBlockByHeight provides a mock function with given fields: ctx, height
+func (_m *MockClient) GetBlockByHeight(ctx context.Context, height int) (blockfrost_go.Block, error) {
+	ret := _m.Called(ctx, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockByHeight")
+	}
+
+	var r0 blockfrost_go.Block
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (blockfrost_go.Block, error)); ok {
+		return rf(ctx, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) blockfrost_go.Block); ok {
+		r0 = rf(ctx, height)
+	} else {
+		r0 = ret.Get(0).(blockfrost_go.Block)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_GetBlockByHeight_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBlockByHeight'
+type MockClient_GetBlockByHeight_Call struct {
+	*mock.Call
+}
+
+// GetBlockByHeight is a helper method to define mock.On call
+//   - ctx context.Context
+//   - height int
+func (_e *MockClient_Expecter) GetBlockByHeight(ctx interface{}, height interface{}) *MockClient_GetBlockByHeight_Call {
+	return &MockClient_GetBlockByHeight_Call{Call: _e.mock.On("GetBlockByHeight", ctx, height)}
+}
+
+func (_c *MockClient_GetBlockByHeight_Call) Run(run func(ctx context.Context, height int)) *MockClient_GetBlockByHeight_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockClient_GetBlockByHeight_Call) Return(_a0 blockfrost_go.Block, _a1 error) *MockClient_GetBlockByHeight_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_GetBlockByHeight_Call) RunAndReturn(run func(context.Context, int) (blockfrost_go.Block, error)) *MockClient_GetBlockByHeight_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetBlockBySlot provides a mock function with given fields: ctx, slot
 func (_m *MockClient) GetBlockBySlot(ctx context.Context, slot int) (blockfrost_go.Block, error) {
 	ret := _m.Called(ctx, slot)
@@ -823,6 +937,66 @@ func (_c *MockClient_GetPoolInfo_Call) RunAndReturn(run func(context.Context, st
 	return _c
 }
 
+// GetPoolLatestBlocks provides a mock function with given fields: ctx, PoolID, count
+func (_m *MockClient) GetPoolLatestBlocks(ctx context.Context, PoolID string, count int) ([]string, error) {
+	ret := _m.Called(ctx, PoolID, count)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPoolLatestBlocks")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]string, error)); ok {
+		return rf(ctx, PoolID, count)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []string); ok {
+		r0 = rf(ctx, PoolID, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, PoolID, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_GetPoolLatestBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPoolLatestBlocks'
+type MockClient_GetPoolLatestBlocks_Call struct {
+	*mock.Call
+}
+
+// GetPoolLatestBlocks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - PoolID string
+//   - count int
+func (_e *MockClient_Expecter) GetPoolLatestBlocks(ctx interface{}, PoolID interface{}, count interface{}) *MockClient_GetPoolLatestBlocks_Call {
+	return &MockClient_GetPoolLatestBlocks_Call{Call: _e.mock.On("GetPoolLatestBlocks", ctx, PoolID, count)}
+}
+
+func (_c *MockClient_GetPoolLatestBlocks_Call) Run(run func(ctx context.Context, PoolID string, count int)) *MockClient_GetPoolLatestBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockClient_GetPoolLatestBlocks_Call) Return(_a0 []string, _a1 error) *MockClient_GetPoolLatestBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_GetPoolLatestBlocks_Call) RunAndReturn(run func(context.Context, string, int) ([]string, error)) *MockClient_GetPoolLatestBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetPoolMetadata provides a mock function with given fields: ctx, PoolID
 func (_m *MockClient) GetPoolMetadata(ctx context.Context, PoolID string) (blockfrost_go.PoolMetadata, error) {
 	ret := _m.Called(ctx, PoolID)
@@ -939,6 +1113,64 @@ func (_c *MockClient_GetPoolRelays_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// GetRetiringPools provides a mock function with given fields: ctx
+func (_m *MockClient) GetRetiringPools(ctx context.Context) ([]blockfrost_go.PoolRetiring, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRetiringPools")
+	}
+
+	var r0 []blockfrost_go.PoolRetiring
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]blockfrost_go.PoolRetiring, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []blockfrost_go.PoolRetiring); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]blockfrost_go.PoolRetiring)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_GetRetiringPools_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRetiringPools'
+type MockClient_GetRetiringPools_Call struct {
+	*mock.Call
+}
+
+// GetRetiringPools is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockClient_Expecter) GetRetiringPools(ctx interface{}) *MockClient_GetRetiringPools_Call {
+	return &MockClient_GetRetiringPools_Call{Call: _e.mock.On("GetRetiringPools", ctx)}
+}
+
+func (_c *MockClient_GetRetiringPools_Call) Run(run func(ctx context.Context)) *MockClient_GetRetiringPools_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockClient_GetRetiringPools_Call) Return(_a0 []blockfrost_go.PoolRetiring, _a1 error) *MockClient_GetRetiringPools_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_GetRetiringPools_Call) RunAndReturn(run func(context.Context) ([]blockfrost_go.PoolRetiring, error)) *MockClient_GetRetiringPools_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Health provides a mock function with given fields: ctx
 func (_m *MockClient) Health(ctx context.Context) (blockfrost_go.Health, error) {
 	ret := _m.Called(ctx)
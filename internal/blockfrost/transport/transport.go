@@ -0,0 +1,305 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
+)
+
+// Defaults match the Blockfrost rate limits: 10 requests per second with a
+// burst of 500 requests.
+const (
+	DefaultRequestsPerSecond = 10
+	DefaultBurst             = 500
+	DefaultMaxRetries        = 3
+	DefaultBaseBackoff       = 500 * time.Millisecond
+	// maxBackoff bounds the wait between two attempts, including the wait
+	// requested by a Retry-After header.
+	maxBackoff = 30 * time.Second
+)
+
+// Error classes reported by the request errors metric.
+const (
+	ErrorClassRateLimited = "rate_limited"
+	ErrorClassServer      = "server_error"
+	ErrorClassClient      = "client_error"
+	ErrorClassTimeout     = "timeout"
+	ErrorClassNetwork     = "network"
+)
+
+// Options configures the rate limiting and retries of a Transport. Zero
+// values are replaced by their default and a negative MaxRetries disables
+// retries.
+type Options struct {
+	Provider          string
+	RequestsPerSecond float64
+	Burst             int
+	MaxRetries        int
+	BaseBackoff       time.Duration
+}
+
+// Transport is an http.RoundTripper limiting the request rate with a token
+// bucket and retrying rate limited and failed requests with jittered
+// exponential backoff.
+type Transport struct {
+	next        http.RoundTripper
+	provider    string
+	bucket      *tokenBucket
+	maxRetries  int
+	baseBackoff time.Duration
+	logger      *slog.Logger
+	metrics     *metrics.Collection
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// NewTransport wraps next, which defaults to http.DefaultTransport. metrics
+// may be nil, in which case no per-endpoint metrics are emitted.
+func NewTransport(next http.RoundTripper, opts Options, m *metrics.Collection) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if opts.RequestsPerSecond <= 0 {
+		opts.RequestsPerSecond = DefaultRequestsPerSecond
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = DefaultBurst
+	}
+	switch {
+	case opts.MaxRetries == 0:
+		opts.MaxRetries = DefaultMaxRetries
+	case opts.MaxRetries < 0:
+		opts.MaxRetries = 0
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = DefaultBaseBackoff
+	}
+
+	return &Transport{
+		next:        next,
+		provider:    opts.Provider,
+		bucket:      newTokenBucket(opts.RequestsPerSecond, opts.Burst),
+		maxRetries:  opts.MaxRetries,
+		baseBackoff: opts.BaseBackoff,
+		logger: slog.With(
+			slog.String("component", "chain-data-transport"),
+			slog.String("provider", opts.Provider),
+		),
+		metrics: m,
+	}
+}
+
+// RoundTrip sends the request once a token is available, retrying it on
+// network errors, HTTP 429 and HTTP 5xx responses.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	endpoint := Endpoint(req.URL.Path)
+
+	for attempt := 0; ; attempt++ {
+		if err := t.bucket.wait(ctx); err != nil {
+			return nil, fmt.Errorf("unable to acquire rate limit token: %w", err)
+		}
+
+		attemptReq, err := rewind(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		res, err := t.next.RoundTrip(attemptReq)
+		t.observe(endpoint, res, err, time.Since(start))
+
+		if !isRetryable(res, err) || attempt >= t.maxRetries || ctx.Err() != nil {
+			return res, err //nolint:wrapcheck
+		}
+
+		wait := t.backoff(attempt, res)
+		t.logger.WarnContext(ctx, "chain data request failed, retrying",
+			slog.String("endpoint", endpoint),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("backoff", wait),
+			slog.String("reason", reason(res, err)),
+		)
+		if res != nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		if t.metrics != nil {
+			t.metrics.ChainDataRequestRetries.WithLabelValues(t.provider, endpoint).Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("context done while waiting to retry: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// observe records the outcome and latency of a single attempt.
+func (t *Transport) observe(endpoint string, res *http.Response, err error, duration time.Duration) {
+	if t.metrics == nil {
+		return
+	}
+
+	code := "error"
+	if res != nil {
+		code = strconv.Itoa(res.StatusCode)
+	}
+	t.metrics.ChainDataRequests.WithLabelValues(t.provider, endpoint, code).Inc()
+	t.metrics.ChainDataRequestDuration.WithLabelValues(t.provider, endpoint).Observe(duration.Seconds())
+
+	if class := errorClass(res, err); class != "" {
+		t.metrics.ChainDataRequestErrors.WithLabelValues(t.provider, endpoint, class).Inc()
+	}
+}
+
+// backoff returns the wait before the next attempt: the Retry-After delay when
+// the provider sends one, otherwise an exponential backoff with full jitter.
+func (t *Transport) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			return min(time.Duration(seconds)*time.Second, maxBackoff)
+		}
+	}
+
+	ceiling := min(t.baseBackoff<<attempt, maxBackoff)
+	return time.Duration(rand.Int64N(int64(ceiling)) + 1) //nolint:gosec
+}
+
+// rewind returns the request to send for the given attempt. Retried requests
+// with a body get a fresh copy of it.
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("unable to rewind request body: %w", err)
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+func isRetryable(res *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError
+}
+
+func errorClass(res *http.Response, err error) string {
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return ErrorClassTimeout
+		}
+		return ErrorClassNetwork
+	}
+
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		return ErrorClassRateLimited
+	case res.StatusCode >= http.StatusInternalServerError:
+		return ErrorClassServer
+	case res.StatusCode >= http.StatusBadRequest && res.StatusCode != http.StatusNotFound:
+		return ErrorClassClient
+	}
+	return ""
+}
+
+func reason(res *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return res.Status
+}
+
+var (
+	numberSegment = regexp.MustCompile(`^[0-9]+$`)
+	hashSegment   = regexp.MustCompile(`^[0-9a-fA-F]{56,64}$`)
+	bech32Segment = regexp.MustCompile(`^(pool|stake|stake_test|addr|addr_test|drep)1[0-9a-z]+$`)
+)
+
+// Endpoint returns the path of a request with its identifiers replaced by
+// placeholders, e.g. /api/v0/blocks/slot/:number, so it can be used as a
+// metric label.
+func Endpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case numberSegment.MatchString(segment):
+			segments[i] = ":number"
+		case hashSegment.MatchString(segment):
+			segments[i] = ":hash"
+		case bech32Segment.MatchString(segment):
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// tokenBucket allows bursts of up to capacity requests and refills at rate
+// tokens per second.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate float64, capacity int) *tokenBucket {
+	return &tokenBucket{
+		rate:     rate,
+		capacity: float64(capacity),
+		tokens:   float64(capacity),
+		last:     time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before
+// using it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	case <-timer.C:
+		return nil
+	}
+}
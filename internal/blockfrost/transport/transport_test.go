@@ -0,0 +1,172 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
+	promutils "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransport(opts Options, m *metrics.Collection) *Transport {
+	opts.Provider = "test"
+	opts.BaseBackoff = time.Millisecond
+	return NewTransport(nil, opts, m)
+}
+
+func TestTransport_RetriesRetryableResponses(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		assert.Equal(t, "payload", string(body))
+
+		switch calls.Add(1) {
+		case 1:
+			res.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			res.WriteHeader(http.StatusBadGateway)
+		default:
+			res.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	m := metrics.NewCollection()
+	client := &http.Client{Transport: newTestTransport(Options{}, m)}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL+"/blocks/slot/42", bytes.NewReader([]byte("payload")))
+	require.NoError(t, err)
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, int32(3), calls.Load())
+
+	endpoint := "/blocks/slot/:number"
+	require.InDelta(t, 1.0, promutils.ToFloat64(m.ChainDataRequests.WithLabelValues("test", endpoint, "429")), 0.0001)
+	require.InDelta(t, 1.0, promutils.ToFloat64(m.ChainDataRequests.WithLabelValues("test", endpoint, "502")), 0.0001)
+	require.InDelta(t, 1.0, promutils.ToFloat64(m.ChainDataRequests.WithLabelValues("test", endpoint, "200")), 0.0001)
+	require.InDelta(t, 1.0, promutils.ToFloat64(m.ChainDataRequestErrors.WithLabelValues("test", endpoint, ErrorClassRateLimited)), 0.0001)
+	require.InDelta(t, 1.0, promutils.ToFloat64(m.ChainDataRequestErrors.WithLabelValues("test", endpoint, ErrorClassServer)), 0.0001)
+	require.InDelta(t, 2.0, promutils.ToFloat64(m.ChainDataRequestRetries.WithLabelValues("test", endpoint)), 0.0001)
+}
+
+func TestTransport_DoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		res.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: newTestTransport(Options{}, nil)}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		res.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("GoodPath_RetriesUpToMaxRetries", func(t *testing.T) {
+		client := &http.Client{Transport: newTestTransport(Options{MaxRetries: 2}, nil)}
+		calls.Store(0)
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		require.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("GoodPath_NegativeMaxRetriesDisablesRetries", func(t *testing.T) {
+		client := &http.Client{Transport: newTestTransport(Options{MaxRetries: -1}, nil)}
+		calls.Store(0)
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestTransport_RateLimitsRequests(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, _ *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	// A burst of 1 at 20 requests per second spaces the 3 requests by 50ms.
+	client := &http.Client{Transport: newTestTransport(Options{RequestsPerSecond: 20, Burst: 1}, nil)}
+
+	start := time.Now()
+	for range 3 {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestTokenBucket_WaitHonorsContext(t *testing.T) {
+	t.Parallel()
+
+	bucket := newTokenBucket(0.1, 1)
+	require.NoError(t, bucket.wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, bucket.wait(ctx), context.DeadlineExceeded)
+}
+
+func TestEndpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"/api/v0/epochs/latest":                     "/api/v0/epochs/latest",
+		"/api/v0/blocks/slot/412162133":             "/api/v0/blocks/slot/:number",
+		"/api/v0/epochs/500/blocks/pool1abcdef0123": "/api/v0/epochs/:number/blocks/:id",
+		"/api/v0/accounts/stake1u9xyz":              "/api/v0/accounts/:id",
+		"/api/v0/pools/0f292fcaa02b8b2f9b3c8f9fd8e0bb21abedb692a6d5058df3ef2735/relays": "/api/v0/pools/:hash/relays",
+		"/api/v1/pool_info": "/api/v1/pool_info",
+	}
+	for path, expected := range tests {
+		require.Equal(t, expected, Endpoint(path), path)
+	}
+}
@@ -7,8 +7,11 @@ import (
 )
 
 type CardanoClient interface {
-	LeaderLogs(ctx context.Context, ledgerSet string, epochNonce string, pool pools.Pool) (ClientLeaderLogsResponse, error)
-	LeaderLogsNextEpoch(ctx context.Context, pool pools.Pool) (ClientLeaderLogsResponse, error)
+	// LeaderLogs computes the schedule of the pool in epoch, from the stake of the ledger set and the epoch nonce.
+	LeaderLogs(ctx context.Context, epoch int, ledgerSet string, epochNonce string, pool pools.Pool) (ClientLeaderLogsResponse, error)
+	// LeaderLogsNextEpoch computes the schedule of the pool in epoch, the next epoch, once its nonce is frozen.
+	LeaderLogsNextEpoch(ctx context.Context, epoch int, pool pools.Pool) (ClientLeaderLogsResponse, error)
 	StakeSnapshot(ctx context.Context, PoolID string) (ClientQueryStakeSnapshotResponse, error)
+	KESPeriodInfo(ctx context.Context, opCertFile string) (ClientKESPeriodInfoResponse, error)
 	Ping(ctx context.Context) error
 }
@@ -27,6 +27,7 @@ const (
 	pingTimeout          = 10 * time.Second
 	stakeSnapshotTimeout = 30 * time.Second
 	leaderLogsTimeout    = 5 * time.Minute
+	kesPeriodInfoTimeout = 30 * time.Second
 )
 
 type Client struct {
@@ -40,23 +41,13 @@ var _ cardano.CardanoClient = (*Client)(nil)
 
 type ClientOptions struct {
 	ConfigDir  string
-	Network    string
+	Network    cardano.Network
 	SocketPath string
 	Timezone   string
 }
 
 func (c *Client) appendNetworkArgs(args []string) []string {
-	switch c.opts.Network {
-	case "mainnet":
-		return append(args, "--mainnet")
-	case "preprod":
-		return append(args, "--testnet-magic", "1")
-	case "sanchonet":
-		return append(args, "--testnet-magic", "4")
-	case "preview":
-		return append(args, "--testnet-magic", "2")
-	}
-	return args
+	return append(args, c.opts.Network.CLIArgs()...)
 }
 
 func NewClient(opts ClientOptions, blockfrost blockfrost.Client, executor CommandExecutor) *Client {
@@ -119,27 +110,62 @@ func (c *Client) StakeSnapshot(ctx context.Context, PoolID string) (cardano.Clie
 	return response, nil
 }
 
-func (c *Client) LeaderLogsNextEpoch(ctx context.Context, pool pools.Pool) (cardano.ClientLeaderLogsResponse, error) {
-	start := time.Now()
-	ctx = context.WithValue(ctx, poolNameCtxKey, pool.Name)
+// KESPeriodInfo checks an operational certificate against the KES period of
+// the chain and the state of the node.
+func (c *Client) KESPeriodInfo(ctx context.Context, opCertFile string) (cardano.ClientKESPeriodInfoResponse, error) {
+	if _, err := os.Stat(opCertFile); errors.Is(err, os.ErrNotExist) {
+		return cardano.ClientKESPeriodInfoResponse{}, fmt.Errorf("unable to find operational certificate file: %w", err)
+	}
 
-	protocolState, err := c.getProtocolState(ctx)
+	// The output file only holds the JSON report, without the checks printed on stdout
+	tmpDir, err := os.MkdirTemp("", "kes-period-info-*")
 	if err != nil {
-		return cardano.ClientLeaderLogsResponse{}, fmt.Errorf("unable to get protocol state: %w", err)
+		return cardano.ClientKESPeriodInfoResponse{}, fmt.Errorf("unable to create temp dir for kes-period-info: %w", err)
 	}
+	defer os.RemoveAll(tmpDir)
+	outFile := filepath.Join(tmpDir, "kes-period-info.json")
 
-	nextEpochNonce, err := deriveNextEpochNonce(protocolState.CandidateNonce, protocolState.LastEpochBlockNonce)
+	args := []string{
+		"query",
+		"kes-period-info",
+		"--op-cert-file",
+		opCertFile,
+		"--socket-path",
+		c.opts.SocketPath,
+	}
+	args = c.appendNetworkArgs(args)
+	args = append(args, "--out-file", outFile)
+
+	output, err := c.executor.ExecCommand(ctx, kesPeriodInfoTimeout, nil, "cardano-cli", args...)
 	if err != nil {
-		return cardano.ClientLeaderLogsResponse{}, fmt.Errorf("unable to derive next epoch nonce: %w", err)
+		if len(output) > 0 {
+			return cardano.ClientKESPeriodInfoResponse{}, fmt.Errorf("unable to query kes period info of %s: %w: %s", opCertFile, err, output)
+		}
+		return cardano.ClientKESPeriodInfoResponse{}, fmt.Errorf("unable to query kes period info of %s: %w", opCertFile, err)
 	}
 
-	c.logger.DebugContext(ctx, "derived next epoch nonce",
-		slog.String("candidate_nonce", protocolState.CandidateNonce),
-		slog.String("last_epoch_block_nonce", protocolState.LastEpochBlockNonce),
-		slog.String("next_epoch_nonce", nextEpochNonce),
-	)
+	content, err := os.ReadFile(outFile) //nolint:gosec
+	if err != nil {
+		return cardano.ClientKESPeriodInfoResponse{}, fmt.Errorf("unable to read kes-period-info output: %w", err)
+	}
+
+	response := cardano.ClientKESPeriodInfoResponse{}
+	if err := json.Unmarshal(content, &response); err != nil {
+		return cardano.ClientKESPeriodInfoResponse{}, fmt.Errorf("unable to unmarshal response for kes-period-info command: %w", err)
+	}
+	return response, nil
+}
 
-	resp, err := c.LeaderLogs(ctx, "next", nextEpochNonce, pool)
+func (c *Client) LeaderLogsNextEpoch(ctx context.Context, epoch int, pool pools.Pool) (cardano.ClientLeaderLogsResponse, error) {
+	start := time.Now()
+	ctx = context.WithValue(ctx, poolNameCtxKey, pool.Name)
+
+	nextEpochNonce, err := c.NextEpochNonce(ctx)
+	if err != nil {
+		return cardano.ClientLeaderLogsResponse{}, err
+	}
+
+	resp, err := c.LeaderLogs(ctx, epoch, "next", nextEpochNonce, pool)
 	if err != nil {
 		return cardano.ClientLeaderLogsResponse{}, err
 	}
@@ -153,6 +179,28 @@ func (c *Client) LeaderLogsNextEpoch(ctx context.Context, pool pools.Pool) (card
 	return resp, nil
 }
 
+// NextEpochNonce derives the nonce of the next epoch from the protocol state
+// of the node. It is only final once the stability window of the current epoch
+// has passed.
+func (c *Client) NextEpochNonce(ctx context.Context) (string, error) {
+	protocolState, err := c.getProtocolState(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to get protocol state: %w", err)
+	}
+
+	nextEpochNonce, err := deriveNextEpochNonce(protocolState.CandidateNonce, protocolState.LastEpochBlockNonce)
+	if err != nil {
+		return "", fmt.Errorf("unable to derive next epoch nonce: %w", err)
+	}
+
+	c.logger.DebugContext(ctx, "derived next epoch nonce",
+		slog.String("candidate_nonce", protocolState.CandidateNonce),
+		slog.String("last_epoch_block_nonce", protocolState.LastEpochBlockNonce),
+		slog.String("next_epoch_nonce", nextEpochNonce),
+	)
+	return nextEpochNonce, nil
+}
+
 func (c *Client) getProtocolState(ctx context.Context) (cardano.ClientProtocolStateResponse, error) {
 	args := []string{
 		"query", "protocol-state",
@@ -188,7 +236,7 @@ func deriveNextEpochNonce(candidateNonce, lastEpochBlockNonce string) (string, e
 	return hex.EncodeToString(hash[:]), nil
 }
 
-func (c *Client) LeaderLogs(ctx context.Context, ledgerSet string, epochNonce string, pool pools.Pool) (cardano.ClientLeaderLogsResponse, error) {
+func (c *Client) LeaderLogs(ctx context.Context, epoch int, ledgerSet string, epochNonce string, pool pools.Pool) (cardano.ClientLeaderLogsResponse, error) {
 	// Inject pool name for subprocess memory logs; may already be set by LeaderLogsNextEpoch
 	if _, ok := ctx.Value(poolNameCtxKey).(string); !ok {
 		ctx = context.WithValue(ctx, poolNameCtxKey, pool.Name)
@@ -256,17 +304,12 @@ func (c *Client) LeaderLogs(ctx context.Context, ledgerSet string, epochNonce st
 		return cardano.ClientLeaderLogsResponse{}, err
 	}
 
-	switch ledgerSet {
-	case "prev":
-		args = append(args, "--pool-stake", strconv.Itoa(poolstakeSnapshot.Pools[poolInfo.Hex].StakeGo))
-		args = append(args, "--active-stake", strconv.Itoa(poolstakeSnapshot.Total.StakeGo))
-	case "current":
-		args = append(args, "--pool-stake", strconv.Itoa(poolstakeSnapshot.Pools[poolInfo.Hex].StakeSet))
-		args = append(args, "--active-stake", strconv.Itoa(poolstakeSnapshot.Total.StakeSet))
-	case "next":
-		args = append(args, "--pool-stake", strconv.Itoa(poolstakeSnapshot.Pools[poolInfo.Hex].StakeMark))
-		args = append(args, "--active-stake", strconv.Itoa(poolstakeSnapshot.Total.StakeMark))
+	poolStake, activeStake, err := poolstakeSnapshot.LedgerSetStake(poolInfo.Hex, ledgerSet)
+	if err != nil {
+		return cardano.ClientLeaderLogsResponse{}, err
 	}
+	args = append(args, "--pool-stake", strconv.Itoa(poolStake))
+	args = append(args, "--active-stake", strconv.Itoa(activeStake))
 
 	envs := []string{
 		"RUST_LOG=error",
@@ -290,6 +333,10 @@ func (c *Client) LeaderLogs(ctx context.Context, ledgerSet string, epochNonce st
 	if response.Status == "error" {
 		return cardano.ClientLeaderLogsResponse{}, fmt.Errorf("cncli leaderlog: %s", response.ErrorMessage)
 	}
+	// cncli derives the epoch of the ledger set from its clock, which may already be in another epoch
+	if response.Epoch != epoch {
+		return cardano.ClientLeaderLogsResponse{}, fmt.Errorf("cncli leaderlog: computed the schedule of epoch %d instead of epoch %d", response.Epoch, epoch)
+	}
 
 	return response, nil
 }
@@ -34,7 +34,7 @@ func TestDeriveNextEpochNonce(t *testing.T) {
 func TestPing(t *testing.T) {
 	t.Run("GoodPath", func(t *testing.T) {
 		clientopts := ClientOptions{
-			Network:    "preprod",
+			Network:    cardano.Network{Name: cardano.NetworkPreprod, Magic: 1},
 			SocketPath: "/tmp/cardano.socket",
 		}
 
@@ -62,7 +62,7 @@ func TestPing(t *testing.T) {
 
 	t.Run("SadPath", func(t *testing.T) {
 		clientopts := ClientOptions{
-			Network:    "preprod",
+			Network:    cardano.Network{Name: cardano.NetworkPreprod, Magic: 1},
 			SocketPath: "/tmp/cardano.socket",
 		}
 
@@ -91,7 +91,7 @@ func TestPing(t *testing.T) {
 
 func TestStakeSnapshot(t *testing.T) {
 	clientopts := ClientOptions{
-		Network:    "preprod",
+		Network:    cardano.Network{Name: cardano.NetworkPreprod, Magic: 1},
 		SocketPath: "/tmp/cardano.socket",
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -135,6 +135,70 @@ func TestStakeSnapshot(t *testing.T) {
 	assert.Equal(t, expected, response)
 }
 
+func TestKESPeriodInfo(t *testing.T) {
+	clientopts := ClientOptions{
+		Network:    cardano.Network{Name: cardano.NetworkPreprod, Magic: 1},
+		SocketPath: "/tmp/cardano.socket",
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	opCertFile := filepath.Join(t.TempDir(), "node.cert")
+	require.NoError(t, os.WriteFile(opCertFile, []byte("{}"), 0o600))
+
+	t.Run("GoodPath", func(t *testing.T) {
+		nodeCounter := 4
+		expected := cardano.ClientKESPeriodInfoResponse{
+			CurrentKESPeriod:          420,
+			StartKESInterval:          400,
+			EndKESInterval:            462,
+			RemainingSlotsInKESPeriod: 100,
+			OnDiskOpCertCounter:       5,
+			NodeStateOpCertCounter:    &nodeCounter,
+			MaxKESEvolutions:          62,
+			SlotsPerKESPeriod:         129600,
+		}
+		expectedByte, err := json.Marshal(expected)
+		require.NoError(t, err)
+
+		exec := &mocks.MockCommandExecutor{}
+		exec.EXPECT().ExecCommand(
+			ctx,
+			kesPeriodInfoTimeout,
+			mock.Anything,
+			"cardano-cli",
+			"query",
+			"kes-period-info",
+			"--op-cert-file",
+			opCertFile,
+			"--socket-path",
+			clientopts.SocketPath,
+			"--testnet-magic",
+			"1",
+			"--out-file",
+			mock.Anything,
+		).RunAndReturn(func(_ context.Context, _ time.Duration, _ []string, _ string, args ...string) ([]byte, error) {
+			// the checks are printed on stdout, only the out file holds the JSON report
+			return []byte("✓ The operational certificate counter agrees with the node protocol state counter"),
+				os.WriteFile(args[len(args)-1], expectedByte, 0o600)
+		})
+
+		client := NewClient(clientopts, nil, exec)
+		response, err := client.KESPeriodInfo(ctx, opCertFile)
+		require.NoError(t, err)
+		assert.Equal(t, expected, response)
+		assert.Equal(t, 42, response.RemainingKESPeriods())
+	})
+
+	t.Run("SadPath_MissingOpCertFile", func(t *testing.T) {
+		exec := &mocks.MockCommandExecutor{}
+
+		client := NewClient(clientopts, nil, exec)
+		_, err := client.KESPeriodInfo(ctx, filepath.Join(t.TempDir(), "missing.cert"))
+		require.ErrorContains(t, err, "unable to find operational certificate file")
+	})
+}
+
 func TestLeaderLogs(t *testing.T) {
 	pool := pools.Pool{
 		Instance: "pool-0",
@@ -143,7 +207,7 @@ func TestLeaderLogs(t *testing.T) {
 		Key:      "pool-0.vrf.skey",
 	}
 	clientopts := ClientOptions{
-		Network:    "preprod",
+		Network:    cardano.Network{Name: cardano.NetworkPreprod, Magic: 1},
 		SocketPath: "/tmp/cardano.socket",
 		Timezone:   "UTC",
 	}
@@ -248,9 +312,13 @@ func TestLeaderLogs(t *testing.T) {
 	).Return(expectedOutputByte, nil)
 
 	client := NewClient(clientopts, bf, exec)
-	response, err := client.LeaderLogs(ctx, "current", "nonce", pool)
+	response, err := client.LeaderLogs(ctx, 100, "current", "nonce", pool)
 	require.NoError(t, err)
 	require.Equal(t, expectedOutput, response)
+
+	// a schedule computed by cncli for another epoch than the target epoch is rejected
+	_, err = client.LeaderLogs(ctx, 101, "current", "nonce", pool)
+	require.ErrorContains(t, err, "computed the schedule of epoch 100 instead of epoch 101")
 }
 
 func TestLeaderLogsNextEpoch(t *testing.T) {
@@ -261,7 +329,7 @@ func TestLeaderLogsNextEpoch(t *testing.T) {
 		Key:      "pool-0.vrf.skey",
 	}
 	clientopts := ClientOptions{
-		Network:    "preprod",
+		Network:    cardano.Network{Name: cardano.NetworkPreprod, Magic: 1},
 		SocketPath: "/tmp/cardano.socket",
 		Timezone:   "UTC",
 	}
@@ -360,7 +428,7 @@ func TestLeaderLogsNextEpoch(t *testing.T) {
 	).Return(expectedOutputByte, nil)
 
 	client := NewClient(clientopts, bf, exec)
-	response, err := client.LeaderLogsNextEpoch(ctx, pool)
+	response, err := client.LeaderLogsNextEpoch(ctx, 628, pool)
 	require.NoError(t, err)
 	require.Equal(t, expectedOutput, response)
 }
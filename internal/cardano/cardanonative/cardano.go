@@ -0,0 +1,158 @@
+package cardanonative
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanocli"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/praos"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+)
+
+// consensus is reported in the leader logs like cncli does for the eras
+// since Babbage.
+const consensus = "praos"
+
+// epochNonceSize is the size of an epoch nonce in bytes.
+const epochNonceSize = 32
+
+// Client computes the leader schedule of the pools in process with the Praos
+// leader check instead of running cncli. The node queries, such as the stake
+// snapshot and the protocol state, still go through cardano-cli.
+type Client struct {
+	*cardanocli.Client
+
+	logger     *slog.Logger
+	blockfrost blockfrost.Client
+	timeline   *cardanotime.Timeline
+	opts       ClientOptions
+}
+
+var _ cardano.CardanoClient = (*Client)(nil)
+
+type ClientOptions struct {
+	Timezone string
+}
+
+func NewClient(opts ClientOptions, node *cardanocli.Client, blockfrost blockfrost.Client, timeline *cardanotime.Timeline) *Client {
+	logger := slog.With(
+		slog.String("component", "cardano-native-client"),
+	)
+	return &Client{
+		Client:     node,
+		logger:     logger,
+		blockfrost: blockfrost,
+		timeline:   timeline,
+		opts:       opts,
+	}
+}
+
+func (c *Client) LeaderLogsNextEpoch(ctx context.Context, epoch int, pool pools.Pool) (cardano.ClientLeaderLogsResponse, error) {
+	start := time.Now()
+
+	nextEpochNonce, err := c.NextEpochNonce(ctx)
+	if err != nil {
+		return cardano.ClientLeaderLogsResponse{}, err //nolint:wrapcheck
+	}
+
+	resp, err := c.LeaderLogs(ctx, epoch, "next", nextEpochNonce, pool)
+	if err != nil {
+		return cardano.ClientLeaderLogsResponse{}, err
+	}
+
+	c.logger.InfoContext(ctx, "next epoch slot schedule computed",
+		slog.String("pool", pool.Name),
+		slog.String("duration", time.Since(start).Round(time.Second).String()),
+		slog.Int("assigned_slots", len(resp.AssignedSlots)),
+	)
+
+	return resp, nil
+}
+
+// LeaderLogs evaluates the VRF of the pool on every slot of the epoch and
+// returns the slots it leads, in the format of cncli leaderlog. The stake of
+// the pool is taken from the ledger set, which must be the snapshot of epoch.
+func (c *Client) LeaderLogs(ctx context.Context, epoch int, ledgerSet string, epochNonce string, pool pools.Pool) (cardano.ClientLeaderLogsResponse, error) {
+	nonce, err := hex.DecodeString(epochNonce)
+	if err != nil || len(nonce) != epochNonceSize {
+		return cardano.ClientLeaderLogsResponse{}, fmt.Errorf("invalid epoch nonce %q", epochNonce)
+	}
+
+	location, err := time.LoadLocation(c.opts.Timezone)
+	if err != nil {
+		return cardano.ClientLeaderLogsResponse{}, fmt.Errorf("invalid timezone %s: %w", c.opts.Timezone, err)
+	}
+
+	key, err := praos.ReadVRFSigningKey(pool.Key)
+	if err != nil {
+		return cardano.ClientLeaderLogsResponse{}, fmt.Errorf("unable to load pool vrf skey: %w", err)
+	}
+
+	poolInfo, err := c.blockfrost.GetPoolInfo(ctx, pool.ID)
+	if err != nil {
+		return cardano.ClientLeaderLogsResponse{}, fmt.Errorf("unable to fetch pool info for %s: %w", pool.ID, err)
+	}
+
+	poolStakeSnapshot, err := c.StakeSnapshot(ctx, poolInfo.PoolID)
+	if err != nil {
+		return cardano.ClientLeaderLogsResponse{}, err //nolint:wrapcheck
+	}
+
+	poolStake, activeStake, err := poolStakeSnapshot.LedgerSetStake(poolInfo.Hex, ledgerSet)
+	if err != nil {
+		return cardano.ClientLeaderLogsResponse{}, err //nolint:wrapcheck
+	}
+	if activeStake <= 0 {
+		return cardano.ClientLeaderLogsResponse{}, fmt.Errorf("no active stake in the %s snapshot", ledgerSet)
+	}
+
+	sigma := big.NewRat(int64(poolStake), int64(activeStake))
+	threshold := praos.LeaderThreshold(sigma, c.timeline.ActiveSlotsCoeff())
+
+	firstSlot := c.timeline.FirstSlotOfEpoch(epoch)
+	slots, err := praos.LeaderSlots(ctx, key, nonce, firstSlot, c.timeline.LastSlotOfEpoch(epoch), threshold)
+	if err != nil {
+		return cardano.ClientLeaderLogsResponse{}, fmt.Errorf("unable to compute leader schedule of pool %s: %w", pool.Name, err)
+	}
+
+	sigmaValue, _ := sigma.Float64()
+	ideal := sigmaValue * float64(c.timeline.EpochLength()) * c.timeline.ActiveSlotsCoeff()
+	response := cardano.ClientLeaderLogsResponse{
+		Status:           "ok",
+		Epoch:            epoch,
+		EpochNonce:       epochNonce,
+		Consensus:        consensus,
+		EpochSlots:       len(slots),
+		EpochSlotsIdeal:  round2(ideal),
+		PoolID:           pool.ID,
+		Sigma:            sigmaValue,
+		ActiveStake:      poolStake,
+		TotalActiveStake: activeStake,
+		AssignedSlots:    make([]cardano.SlotSchedule, 0, len(slots)),
+	}
+	if ideal > 0 {
+		response.MaxPerformance = round2(float64(len(slots)) / ideal * 100)
+	}
+	for i, slot := range slots {
+		response.AssignedSlots = append(response.AssignedSlots, cardano.SlotSchedule{
+			No:          i + 1,
+			Slot:        slot,
+			SlotInEpoch: slot - firstSlot,
+			At:          c.timeline.SlotToTime(slot).In(location),
+		})
+	}
+
+	return response, nil
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
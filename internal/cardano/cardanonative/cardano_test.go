@@ -0,0 +1,203 @@
+package cardanonative
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blockfrost/blockfrost-go"
+	blockfrostmocks "github.com/kilnfi/cardano-validator-watcher/internal/blockfrost/mocks"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanocli"
+	mocks "github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanocli/mocks"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// vrfSigningKey is the secret key of example 10 of draft-irtf-cfrg-vrf-03.
+const vrfSigningKey = `{
+    "type": "VrfSigningKey_PraosVRF",
+    "description": "VRF Signing Key",
+    "cborHex": "58409d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+}`
+
+const epochNonce = "47c2b7ae9e783b753afe7fd28986ac1b39c37220e13b5df09245cd4c1125f759"
+
+// testGenesis is a network of 2000 slots per epoch, epoch 11 starts at slot 22000.
+var testGenesis = cardanotime.ShelleyGenesis{
+	SystemStart:      time.Date(2022, 10, 25, 0, 0, 0, 0, time.UTC),
+	NetworkMagic:     2,
+	EpochLength:      2000,
+	SlotLength:       1,
+	ActiveSlotsCoeff: 0.05,
+	SecurityParam:    100,
+}
+
+func setupClient(t *testing.T, vrfKey string, genesis cardanotime.ShelleyGenesis, snapshot cardano.ClientQueryStakeSnapshotResponse) (*Client, pools.Pool) {
+	t.Helper()
+
+	keyPath := filepath.Join(t.TempDir(), "pool-0.vrf.skey")
+	require.NoError(t, os.WriteFile(keyPath, []byte(vrfKey), 0o600))
+	pool := pools.Pool{
+		Instance: "pool-0",
+		ID:       "pool-0",
+		Name:     "pool-0",
+		Key:      keyPath,
+	}
+
+	bf := blockfrostmocks.NewMockClient(t)
+	bf.EXPECT().GetPoolInfo(mock.Anything, "pool-0").Return(blockfrost.Pool{
+		PoolID: "pool-0",
+		Hex:    "pool-0-hex",
+	}, nil)
+
+	output, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	exec := mocks.NewMockCommandExecutor(t)
+	exec.EXPECT().ExecCommand(
+		mock.Anything, mock.Anything, mock.Anything, "cardano-cli",
+		"query", "stake-snapshot",
+		"--stake-pool-id", "pool-0",
+		"--socket-path", "/tmp/cardano.socket",
+		"--testnet-magic", "2",
+	).Return(output, nil)
+
+	node := cardanocli.NewClient(cardanocli.ClientOptions{
+		Network:    cardano.Network{Name: cardano.NetworkPreview, Magic: 2},
+		SocketPath: "/tmp/cardano.socket",
+	}, bf, exec)
+
+	client := NewClient(ClientOptions{Timezone: "Europe/Paris"}, node, bf, cardanotime.NewTimeline(genesis, 0))
+	return client, pool
+}
+
+func TestLeaderLogs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_ScheduleOfTheNextEpoch", func(t *testing.T) {
+		t.Parallel()
+
+		client, pool := setupClient(t, vrfSigningKey, testGenesis, cardano.ClientQueryStakeSnapshotResponse{
+			Pools: map[string]cardano.PoolStakeInfo{
+				"pool-0-hex": {StakeGo: 1, StakeSet: 1, StakeMark: 250},
+			},
+			Total: cardano.TotalStakeInfo{StakeGo: 1000, StakeSet: 1000, StakeMark: 1000},
+		})
+
+		response, err := client.LeaderLogs(context.Background(), 11, "next", epochNonce, pool)
+		require.NoError(t, err)
+
+		assert.Equal(t, "ok", response.Status)
+		assert.Equal(t, "praos", response.Consensus)
+		assert.Equal(t, 11, response.Epoch)
+		assert.Equal(t, epochNonce, response.EpochNonce)
+		assert.Equal(t, "pool-0", response.PoolID)
+		assert.InDelta(t, 0.25, response.Sigma, 0.0001)
+		assert.Equal(t, 250, response.ActiveStake)
+		assert.Equal(t, 1000, response.TotalActiveStake)
+		assert.InDelta(t, 25.0, response.EpochSlotsIdeal, 0.0001)
+		assert.Len(t, response.AssignedSlots, response.EpochSlots)
+		assert.NotEmpty(t, response.AssignedSlots)
+
+		// Slots won by the key at sigma 0.25, computed with an independent ECVRF
+		// implementation and an exact leader check
+		expected := []int{
+			22117, 22180, 22183, 22223, 22240, 22306, 22402, 22487, 22623, 22728, 22729,
+			22828, 22959, 22976, 22978, 23075, 23090, 23122, 23162, 23332, 23374, 23842,
+		}
+		require.Len(t, response.AssignedSlots, len(expected))
+
+		paris, err := time.LoadLocation("Europe/Paris")
+		require.NoError(t, err)
+		for i, slot := range response.AssignedSlots {
+			assert.Equal(t, i+1, slot.No)
+			assert.Equal(t, expected[i], slot.Slot)
+			assert.Equal(t, slot.Slot-22000, slot.SlotInEpoch)
+			assert.Equal(t, client.timeline.SlotToTime(slot.Slot).In(paris), slot.At)
+		}
+	})
+
+	t.Run("SadPath_InvalidEpochNonce", func(t *testing.T) {
+		t.Parallel()
+
+		client := NewClient(ClientOptions{Timezone: "UTC"}, nil, nil, cardanotime.NewTimeline(cardanotime.ShelleyGenesis{EpochLength: 100, SlotLength: 1}, 0))
+		_, err := client.LeaderLogs(context.Background(), 10, "current", "nonce", pools.Pool{})
+		require.ErrorContains(t, err, "invalid epoch nonce")
+	})
+
+	t.Run("SadPath_NoActiveStake", func(t *testing.T) {
+		t.Parallel()
+
+		client, pool := setupClient(t, vrfSigningKey, testGenesis, cardano.ClientQueryStakeSnapshotResponse{})
+		_, err := client.LeaderLogs(context.Background(), 10, "current", epochNonce, pool)
+		require.ErrorContains(t, err, "no active stake")
+	})
+}
+
+// leaderLogFixture is a leader schedule computed outside of this package, in
+// testdata/leaderlog. Each case is the pool stake and the slots it wins.
+type leaderLogFixture struct {
+	Description      string          `json:"description"`
+	Source           string          `json:"source"`
+	VRFSigningKey    json.RawMessage `json:"vrf_skey"`
+	Epoch            int             `json:"epoch"`
+	EpochLength      int             `json:"epoch_length"`
+	EpochNonce       string          `json:"epoch_nonce"`
+	ActiveSlotsCoeff float64         `json:"active_slots_coeff"`
+	TotalActiveStake int             `json:"total_active_stake"`
+	Cases            []struct {
+		PoolStake     int   `json:"pool_stake"`
+		AssignedSlots []int `json:"assigned_slots"`
+	} `json:"cases"`
+}
+
+// TestLeaderLogs_Fixtures compares the schedule with the slots of the fixtures.
+// The fixtures of the threshold edge put a slot one lovelace away from the
+// leader threshold, a rounding of sigma or of the threshold loses or wins it.
+func TestLeaderLogs_Fixtures(t *testing.T) {
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "leaderlog", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		fixture := leaderLogFixture{}
+		require.NoError(t, json.Unmarshal(content, &fixture))
+
+		genesis := testGenesis
+		genesis.EpochLength = fixture.EpochLength
+		genesis.ActiveSlotsCoeff = fixture.ActiveSlotsCoeff
+
+		for _, tc := range fixture.Cases {
+			t.Run(fmt.Sprintf("%s/%d", filepath.Base(path), tc.PoolStake), func(t *testing.T) {
+				t.Parallel()
+
+				client, pool := setupClient(t, string(fixture.VRFSigningKey), genesis, cardano.ClientQueryStakeSnapshotResponse{
+					Pools: map[string]cardano.PoolStakeInfo{
+						"pool-0-hex": {StakeSet: tc.PoolStake},
+					},
+					Total: cardano.TotalStakeInfo{StakeSet: fixture.TotalActiveStake},
+				})
+
+				response, err := client.LeaderLogs(context.Background(), fixture.Epoch, "current", fixture.EpochNonce, pool)
+				require.NoError(t, err)
+
+				slots := make([]int, 0, len(response.AssignedSlots))
+				for _, slot := range response.AssignedSlots {
+					slots = append(slots, slot.Slot)
+				}
+				require.Equal(t, tc.AssignedSlots, slots, fixture.Description)
+			})
+		}
+	}
+}
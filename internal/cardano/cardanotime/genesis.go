@@ -0,0 +1,57 @@
+package cardanotime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ShelleyGenesis holds the shelley genesis parameters needed to derive the chain time.
+type ShelleyGenesis struct {
+	SystemStart      time.Time `json:"systemStart"`
+	NetworkMagic     int       `json:"networkMagic"`
+	EpochLength      int       `json:"epochLength"`
+	SlotLength       float64   `json:"slotLength"`
+	ActiveSlotsCoeff float64   `json:"activeSlotsCoeff"`
+	SecurityParam    int       `json:"securityParam"`
+}
+
+// LoadShelleyGenesis reads and validates the shelley genesis file located at path.
+func LoadShelleyGenesis(path string) (ShelleyGenesis, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ShelleyGenesis{}, fmt.Errorf("unable to read shelley genesis file: %w", err)
+	}
+
+	genesis := ShelleyGenesis{}
+	if err := json.Unmarshal(content, &genesis); err != nil {
+		return ShelleyGenesis{}, fmt.Errorf("unable to unmarshal shelley genesis file: %w", err)
+	}
+
+	if err := genesis.Validate(); err != nil {
+		return ShelleyGenesis{}, fmt.Errorf("invalid shelley genesis file %s: %w", path, err)
+	}
+	return genesis, nil
+}
+
+// Validate ensures that the genesis parameters can be used to compute slots and epochs.
+func (g ShelleyGenesis) Validate() error {
+	if g.SystemStart.IsZero() {
+		return errors.New("systemStart is required")
+	}
+	if g.EpochLength <= 0 {
+		return errors.New("epochLength must be greater than 0")
+	}
+	if g.SlotLength <= 0 {
+		return errors.New("slotLength must be greater than 0")
+	}
+	if g.ActiveSlotsCoeff <= 0 || g.ActiveSlotsCoeff > 1 {
+		return errors.New("activeSlotsCoeff must be in ]0, 1]")
+	}
+	if g.SecurityParam <= 0 {
+		return errors.New("securityParam must be greater than 0")
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cardanotime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadShelleyGenesis(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_GenesisIsLoaded", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "shelley.json")
+		content := `{
+			"activeSlotsCoeff": 0.05,
+			"epochLength": 86400,
+			"networkId": "Testnet",
+			"networkMagic": 2,
+			"securityParam": 432,
+			"slotLength": 1,
+			"systemStart": "2022-10-25T00:00:00Z"
+		}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		genesis, err := LoadShelleyGenesis(path)
+		require.NoError(t, err)
+		require.Equal(t, previewGenesis(), genesis)
+		require.Equal(t, time.Date(2022, 10, 25, 0, 0, 0, 0, time.UTC), genesis.SystemStart)
+	})
+
+	t.Run("SadPath_GenesisNotFound", func(t *testing.T) {
+		t.Parallel()
+		_, err := LoadShelleyGenesis(filepath.Join(t.TempDir(), "shelley.json"))
+		require.ErrorContains(t, err, "unable to read shelley genesis file")
+	})
+
+	t.Run("SadPath_InvalidEpochLength", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "shelley.json")
+		content := `{"activeSlotsCoeff": 0.05, "epochLength": 0, "securityParam": 432, "slotLength": 1, "systemStart": "2022-10-25T00:00:00Z"}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		_, err := LoadShelleyGenesis(path)
+		require.ErrorContains(t, err, "epochLength must be greater than 0")
+	})
+}
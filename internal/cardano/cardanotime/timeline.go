@@ -0,0 +1,169 @@
+package cardanotime
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
+)
+
+// Byron era parameters. Every network that started in the Byron era used
+// 20 seconds slots and epochs of 10k slots.
+const (
+	byronSlotLength      = 20 * time.Second
+	byronEpochLengthPerK = 10
+)
+
+// Epochs at which the known networks entered the Shelley era.
+const (
+	mainnetShelleyEpoch = 208
+	preprodShelleyEpoch = 4
+	defaultShelleyEpoch = 0
+)
+
+// ShelleyTransitionEpoch returns the epoch at which the given network hard forked
+// from Byron to Shelley. Networks started directly in Shelley return 0.
+func ShelleyTransitionEpoch(network string) int {
+	switch network {
+	case cardano.NetworkMainnet:
+		return mainnetShelleyEpoch
+	case cardano.NetworkPreprod:
+		return preprodShelleyEpoch
+	}
+	return defaultShelleyEpoch
+}
+
+// Timeline converts between slots, epochs and wall-clock time for a network.
+type Timeline struct {
+	genesis          ShelleyGenesis
+	slotLength       time.Duration
+	byronEpochLength int
+	shelleyEpoch     int
+	shelleySlot      int
+	shelleyStart     time.Time
+}
+
+// NewTimeline creates a timeline from the shelley genesis of a network and
+// the epoch at which it entered the Shelley era.
+func NewTimeline(genesis ShelleyGenesis, shelleyEpoch int) *Timeline {
+	byronEpochLength := byronEpochLengthPerK * genesis.SecurityParam
+	shelleySlot := shelleyEpoch * byronEpochLength
+
+	return &Timeline{
+		genesis:          genesis,
+		slotLength:       time.Duration(genesis.SlotLength * float64(time.Second)),
+		byronEpochLength: byronEpochLength,
+		shelleyEpoch:     shelleyEpoch,
+		shelleySlot:      shelleySlot,
+		shelleyStart:     genesis.SystemStart.Add(time.Duration(shelleySlot) * byronSlotLength),
+	}
+}
+
+// EpochLength returns the number of slots in a Shelley epoch.
+func (t *Timeline) EpochLength() int {
+	return t.genesis.EpochLength
+}
+
+// SlotLength returns the duration of a Shelley slot.
+func (t *Timeline) SlotLength() time.Duration {
+	return t.slotLength
+}
+
+// EpochDuration returns the duration of a Shelley epoch.
+func (t *Timeline) EpochDuration() time.Duration {
+	return time.Duration(t.genesis.EpochLength) * t.slotLength
+}
+
+// ActiveSlotsCoeff returns the fraction of slots expected to contain a block.
+func (t *Timeline) ActiveSlotsCoeff() float64 {
+	return t.genesis.ActiveSlotsCoeff
+}
+
+// SecurityParam returns the security parameter k of the network.
+func (t *Timeline) SecurityParam() int {
+	return t.genesis.SecurityParam
+}
+
+// NetworkMagic returns the network magic declared in the genesis.
+func (t *Timeline) NetworkMagic() int {
+	return t.genesis.NetworkMagic
+}
+
+// EpochOfSlot returns the epoch containing the given slot.
+func (t *Timeline) EpochOfSlot(slot int) int {
+	if slot < t.shelleySlot {
+		return slot / t.byronEpochLength
+	}
+	return t.shelleyEpoch + (slot-t.shelleySlot)/t.genesis.EpochLength
+}
+
+// FirstSlotOfEpoch returns the first slot of the given epoch.
+func (t *Timeline) FirstSlotOfEpoch(epoch int) int {
+	if epoch < t.shelleyEpoch {
+		return epoch * t.byronEpochLength
+	}
+	return t.shelleySlot + (epoch-t.shelleyEpoch)*t.genesis.EpochLength
+}
+
+// LastSlotOfEpoch returns the last slot of the given epoch.
+func (t *Timeline) LastSlotOfEpoch(epoch int) int {
+	return t.FirstSlotOfEpoch(epoch+1) - 1
+}
+
+// SlotInEpoch returns the position of the given slot inside its epoch.
+func (t *Timeline) SlotInEpoch(slot int) int {
+	return slot - t.FirstSlotOfEpoch(t.EpochOfSlot(slot))
+}
+
+// SlotToTime returns the wall-clock time at which the given slot starts.
+func (t *Timeline) SlotToTime(slot int) time.Time {
+	if slot < t.shelleySlot {
+		return t.genesis.SystemStart.Add(time.Duration(slot) * byronSlotLength)
+	}
+	return t.shelleyStart.Add(time.Duration(slot-t.shelleySlot) * t.slotLength)
+}
+
+// TimeToSlot returns the slot in progress at the given time.
+// Times before the system start are mapped to slot 0.
+func (t *Timeline) TimeToSlot(at time.Time) int {
+	if at.Before(t.genesis.SystemStart) {
+		return 0
+	}
+	if at.Before(t.shelleyStart) {
+		return int(at.Sub(t.genesis.SystemStart) / byronSlotLength)
+	}
+	return t.shelleySlot + int(at.Sub(t.shelleyStart)/t.slotLength)
+}
+
+// EpochStartTime returns the wall-clock time at which the given epoch starts.
+func (t *Timeline) EpochStartTime(epoch int) time.Time {
+	return t.SlotToTime(t.FirstSlotOfEpoch(epoch))
+}
+
+// EpochEndTime returns the wall-clock time at which the given epoch ends.
+func (t *Timeline) EpochEndTime(epoch int) time.Time {
+	return t.EpochStartTime(epoch + 1)
+}
+
+// RandomnessStabilisationWindow returns the number of slots at the end of an
+// epoch during which the candidate nonce of the next epoch no longer evolves.
+// It is ceil(4k/f) since Babbage, 172800 slots (2 days) on mainnet.
+func (t *Timeline) RandomnessStabilisationWindow() int {
+	// The ledger reads f as a decimal from the genesis, so 0.05 is exactly 1/20
+	f, _ := new(big.Rat).SetString(strconv.FormatFloat(t.genesis.ActiveSlotsCoeff, 'g', -1, 64))
+	window := new(big.Rat).Quo(big.NewRat(4*int64(t.genesis.SecurityParam), 1), f)
+
+	quo, rem := new(big.Int).QuoRem(window.Num(), window.Denom(), new(big.Int))
+	if rem.Sign() > 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	return int(quo.Int64())
+}
+
+// NextEpochNonceFreezeTime returns the wall-clock time from which the nonce of
+// the epoch following the given one is fixed, and so from which the leader
+// schedule of this next epoch can be computed.
+func (t *Timeline) NextEpochNonceFreezeTime(epoch int) time.Time {
+	return t.SlotToTime(t.FirstSlotOfEpoch(epoch+1) - t.RandomnessStabilisationWindow())
+}
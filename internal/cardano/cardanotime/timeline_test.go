@@ -0,0 +1,74 @@
+package cardanotime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mainnetGenesis() ShelleyGenesis {
+	return ShelleyGenesis{
+		SystemStart:      time.Date(2017, 9, 23, 21, 44, 51, 0, time.UTC),
+		NetworkMagic:     764824073,
+		EpochLength:      432000,
+		SlotLength:       1,
+		ActiveSlotsCoeff: 0.05,
+		SecurityParam:    2160,
+	}
+}
+
+func previewGenesis() ShelleyGenesis {
+	return ShelleyGenesis{
+		SystemStart:      time.Date(2022, 10, 25, 0, 0, 0, 0, time.UTC),
+		NetworkMagic:     2,
+		EpochLength:      86400,
+		SlotLength:       1,
+		ActiveSlotsCoeff: 0.05,
+		SecurityParam:    432,
+	}
+}
+
+func TestTimeline_Mainnet(t *testing.T) {
+	t.Parallel()
+	timeline := NewTimeline(mainnetGenesis(), ShelleyTransitionEpoch("mainnet"))
+
+	require.Equal(t, 5*24*time.Hour, timeline.EpochDuration())
+
+	// Byron era
+	require.Equal(t, 1, timeline.EpochOfSlot(21600))
+	require.Equal(t, 21600, timeline.FirstSlotOfEpoch(1))
+	require.Equal(t, time.Date(2017, 9, 28, 21, 44, 51, 0, time.UTC), timeline.SlotToTime(21600))
+
+	// Shelley hard fork
+	require.Equal(t, 207, timeline.EpochOfSlot(4492799))
+	require.Equal(t, 208, timeline.EpochOfSlot(4492800))
+	require.Equal(t, 4492800, timeline.FirstSlotOfEpoch(208))
+	require.Equal(t, 4492799, timeline.LastSlotOfEpoch(207))
+	require.Equal(t, time.Date(2020, 7, 29, 21, 44, 51, 0, time.UTC), timeline.EpochStartTime(208))
+	require.Equal(t, time.Date(2020, 8, 3, 21, 44, 51, 0, time.UTC), timeline.EpochEndTime(208))
+
+	// Shelley era
+	require.Equal(t, 4924799, timeline.LastSlotOfEpoch(208))
+	require.Equal(t, 100, timeline.SlotInEpoch(4492900))
+	require.Equal(t, time.Date(2020, 7, 29, 21, 46, 31, 0, time.UTC), timeline.SlotToTime(4492900))
+	require.Equal(t, 4492900, timeline.TimeToSlot(time.Date(2020, 7, 29, 21, 46, 31, 500, time.UTC)))
+
+	// Stability window
+	require.Equal(t, 172800, timeline.RandomnessStabilisationWindow())
+	require.Equal(t, time.Date(2020, 8, 1, 21, 44, 51, 0, time.UTC), timeline.NextEpochNonceFreezeTime(208))
+}
+
+func TestTimeline_Preview(t *testing.T) {
+	t.Parallel()
+	timeline := NewTimeline(previewGenesis(), ShelleyTransitionEpoch("preview"))
+
+	require.Equal(t, 24*time.Hour, timeline.EpochDuration())
+	require.Equal(t, 0, timeline.EpochOfSlot(86399))
+	require.Equal(t, 1, timeline.EpochOfSlot(86400))
+	require.Equal(t, 172799, timeline.LastSlotOfEpoch(1))
+	require.Equal(t, time.Date(2022, 10, 26, 0, 0, 0, 0, time.UTC), timeline.EpochStartTime(1))
+	require.Equal(t, 0, timeline.TimeToSlot(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, 34560, timeline.RandomnessStabilisationWindow())
+	require.Equal(t, time.Date(2022, 10, 25, 14, 24, 0, 0, time.UTC), timeline.NextEpochNonceFreezeTime(0))
+}
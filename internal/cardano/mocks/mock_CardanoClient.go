@@ -25,9 +25,66 @@ func (_m *MockCardanoClient) EXPECT() *MockCardanoClient_Expecter {
 	return &MockCardanoClient_Expecter{mock: &_m.Mock}
 }
 
-// LeaderLogs provides a mock function with given fields: ctx, ledgerSet, epochNonce, pool
-func (_m *MockCardanoClient) LeaderLogs(ctx context.Context, ledgerSet string, epochNonce string, pool pools.Pool) (cardano.ClientLeaderLogsResponse, error) {
-	ret := _m.Called(ctx, ledgerSet, epochNonce, pool)
+// KESPeriodInfo provides a mock function with given fields: ctx, opCertFile
+func (_m *MockCardanoClient) KESPeriodInfo(ctx context.Context, opCertFile string) (cardano.ClientKESPeriodInfoResponse, error) {
+	ret := _m.Called(ctx, opCertFile)
+
+	if len(ret) == 0 {
+		panic("no return value specified for KESPeriodInfo")
+	}
+
+	var r0 cardano.ClientKESPeriodInfoResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (cardano.ClientKESPeriodInfoResponse, error)); ok {
+		return rf(ctx, opCertFile)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) cardano.ClientKESPeriodInfoResponse); ok {
+		r0 = rf(ctx, opCertFile)
+	} else {
+		r0 = ret.Get(0).(cardano.ClientKESPeriodInfoResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, opCertFile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCardanoClient_KESPeriodInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'KESPeriodInfo'
+type MockCardanoClient_KESPeriodInfo_Call struct {
+	*mock.Call
+}
+
+// KESPeriodInfo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opCertFile string
+func (_e *MockCardanoClient_Expecter) KESPeriodInfo(ctx interface{}, opCertFile interface{}) *MockCardanoClient_KESPeriodInfo_Call {
+	return &MockCardanoClient_KESPeriodInfo_Call{Call: _e.mock.On("KESPeriodInfo", ctx, opCertFile)}
+}
+
+func (_c *MockCardanoClient_KESPeriodInfo_Call) Run(run func(ctx context.Context, opCertFile string)) *MockCardanoClient_KESPeriodInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCardanoClient_KESPeriodInfo_Call) Return(_a0 cardano.ClientKESPeriodInfoResponse, _a1 error) *MockCardanoClient_KESPeriodInfo_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCardanoClient_KESPeriodInfo_Call) RunAndReturn(run func(context.Context, string) (cardano.ClientKESPeriodInfoResponse, error)) *MockCardanoClient_KESPeriodInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeaderLogs provides a mock function with given fields: ctx, epoch, ledgerSet, epochNonce, pool
+func (_m *MockCardanoClient) LeaderLogs(ctx context.Context, epoch int, ledgerSet string, epochNonce string, pool pools.Pool) (cardano.ClientLeaderLogsResponse, error) {
+	ret := _m.Called(ctx, epoch, ledgerSet, epochNonce, pool)
 
 	if len(ret) == 0 {
 		panic("no return value specified for LeaderLogs")
@@ -35,17 +92,17 @@ func (_m *MockCardanoClient) LeaderLogs(ctx context.Context, ledgerSet string, e
 
 	var r0 cardano.ClientLeaderLogsResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, pools.Pool) (cardano.ClientLeaderLogsResponse, error)); ok {
-		return rf(ctx, ledgerSet, epochNonce, pool)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, pools.Pool) (cardano.ClientLeaderLogsResponse, error)); ok {
+		return rf(ctx, epoch, ledgerSet, epochNonce, pool)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, pools.Pool) cardano.ClientLeaderLogsResponse); ok {
-		r0 = rf(ctx, ledgerSet, epochNonce, pool)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, pools.Pool) cardano.ClientLeaderLogsResponse); ok {
+		r0 = rf(ctx, epoch, ledgerSet, epochNonce, pool)
 	} else {
 		r0 = ret.Get(0).(cardano.ClientLeaderLogsResponse)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, pools.Pool) error); ok {
-		r1 = rf(ctx, ledgerSet, epochNonce, pool)
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string, pools.Pool) error); ok {
+		r1 = rf(ctx, epoch, ledgerSet, epochNonce, pool)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -60,16 +117,17 @@ type MockCardanoClient_LeaderLogs_Call struct {
 
 // LeaderLogs is a helper method to define mock.On call
 //   - ctx context.Context
+//   - epoch int
 //   - ledgerSet string
 //   - epochNonce string
 //   - pool pools.Pool
-func (_e *MockCardanoClient_Expecter) LeaderLogs(ctx interface{}, ledgerSet interface{}, epochNonce interface{}, pool interface{}) *MockCardanoClient_LeaderLogs_Call {
-	return &MockCardanoClient_LeaderLogs_Call{Call: _e.mock.On("LeaderLogs", ctx, ledgerSet, epochNonce, pool)}
+func (_e *MockCardanoClient_Expecter) LeaderLogs(ctx interface{}, epoch interface{}, ledgerSet interface{}, epochNonce interface{}, pool interface{}) *MockCardanoClient_LeaderLogs_Call {
+	return &MockCardanoClient_LeaderLogs_Call{Call: _e.mock.On("LeaderLogs", ctx, epoch, ledgerSet, epochNonce, pool)}
 }
 
-func (_c *MockCardanoClient_LeaderLogs_Call) Run(run func(ctx context.Context, ledgerSet string, epochNonce string, pool pools.Pool)) *MockCardanoClient_LeaderLogs_Call {
+func (_c *MockCardanoClient_LeaderLogs_Call) Run(run func(ctx context.Context, epoch int, ledgerSet string, epochNonce string, pool pools.Pool)) *MockCardanoClient_LeaderLogs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(pools.Pool))
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string), args[4].(pools.Pool))
 	})
 	return _c
 }
@@ -79,14 +137,14 @@ func (_c *MockCardanoClient_LeaderLogs_Call) Return(_a0 cardano.ClientLeaderLogs
 	return _c
 }
 
-func (_c *MockCardanoClient_LeaderLogs_Call) RunAndReturn(run func(context.Context, string, string, pools.Pool) (cardano.ClientLeaderLogsResponse, error)) *MockCardanoClient_LeaderLogs_Call {
+func (_c *MockCardanoClient_LeaderLogs_Call) RunAndReturn(run func(context.Context, int, string, string, pools.Pool) (cardano.ClientLeaderLogsResponse, error)) *MockCardanoClient_LeaderLogs_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// LeaderLogsNextEpoch provides a mock function with given fields: ctx, pool
-func (_m *MockCardanoClient) LeaderLogsNextEpoch(ctx context.Context, pool pools.Pool) (cardano.ClientLeaderLogsResponse, error) {
-	ret := _m.Called(ctx, pool)
+// LeaderLogsNextEpoch provides a mock function with given fields: ctx, epoch, pool
+func (_m *MockCardanoClient) LeaderLogsNextEpoch(ctx context.Context, epoch int, pool pools.Pool) (cardano.ClientLeaderLogsResponse, error) {
+	ret := _m.Called(ctx, epoch, pool)
 
 	if len(ret) == 0 {
 		panic("no return value specified for LeaderLogsNextEpoch")
@@ -94,17 +152,17 @@ func (_m *MockCardanoClient) LeaderLogsNextEpoch(ctx context.Context, pool pools
 
 	var r0 cardano.ClientLeaderLogsResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, pools.Pool) (cardano.ClientLeaderLogsResponse, error)); ok {
-		return rf(ctx, pool)
+	if rf, ok := ret.Get(0).(func(context.Context, int, pools.Pool) (cardano.ClientLeaderLogsResponse, error)); ok {
+		return rf(ctx, epoch, pool)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, pools.Pool) cardano.ClientLeaderLogsResponse); ok {
-		r0 = rf(ctx, pool)
+	if rf, ok := ret.Get(0).(func(context.Context, int, pools.Pool) cardano.ClientLeaderLogsResponse); ok {
+		r0 = rf(ctx, epoch, pool)
 	} else {
 		r0 = ret.Get(0).(cardano.ClientLeaderLogsResponse)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, pools.Pool) error); ok {
-		r1 = rf(ctx, pool)
+	if rf, ok := ret.Get(1).(func(context.Context, int, pools.Pool) error); ok {
+		r1 = rf(ctx, epoch, pool)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -119,14 +177,15 @@ type MockCardanoClient_LeaderLogsNextEpoch_Call struct {
 
 // LeaderLogsNextEpoch is a helper method to define mock.On call
 //   - ctx context.Context
+//   - epoch int
 //   - pool pools.Pool
-func (_e *MockCardanoClient_Expecter) LeaderLogsNextEpoch(ctx interface{}, pool interface{}) *MockCardanoClient_LeaderLogsNextEpoch_Call {
-	return &MockCardanoClient_LeaderLogsNextEpoch_Call{Call: _e.mock.On("LeaderLogsNextEpoch", ctx, pool)}
+func (_e *MockCardanoClient_Expecter) LeaderLogsNextEpoch(ctx interface{}, epoch interface{}, pool interface{}) *MockCardanoClient_LeaderLogsNextEpoch_Call {
+	return &MockCardanoClient_LeaderLogsNextEpoch_Call{Call: _e.mock.On("LeaderLogsNextEpoch", ctx, epoch, pool)}
 }
 
-func (_c *MockCardanoClient_LeaderLogsNextEpoch_Call) Run(run func(ctx context.Context, pool pools.Pool)) *MockCardanoClient_LeaderLogsNextEpoch_Call {
+func (_c *MockCardanoClient_LeaderLogsNextEpoch_Call) Run(run func(ctx context.Context, epoch int, pool pools.Pool)) *MockCardanoClient_LeaderLogsNextEpoch_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(pools.Pool))
+		run(args[0].(context.Context), args[1].(int), args[2].(pools.Pool))
 	})
 	return _c
 }
@@ -136,7 +195,7 @@ func (_c *MockCardanoClient_LeaderLogsNextEpoch_Call) Return(_a0 cardano.ClientL
 	return _c
 }
 
-func (_c *MockCardanoClient_LeaderLogsNextEpoch_Call) RunAndReturn(run func(context.Context, pools.Pool) (cardano.ClientLeaderLogsResponse, error)) *MockCardanoClient_LeaderLogsNextEpoch_Call {
+func (_c *MockCardanoClient_LeaderLogsNextEpoch_Call) RunAndReturn(run func(context.Context, int, pools.Pool) (cardano.ClientLeaderLogsResponse, error)) *MockCardanoClient_LeaderLogsNextEpoch_Call {
 	_c.Call.Return(run)
 	return _c
 }
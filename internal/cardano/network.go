@@ -0,0 +1,48 @@
+package cardano
+
+import "strconv"
+
+// Names of the public Cardano networks.
+const (
+	NetworkMainnet   = "mainnet"
+	NetworkPreprod   = "preprod"
+	NetworkPreview   = "preview"
+	NetworkSanchonet = "sanchonet"
+)
+
+// knownNetworkMagics maps the public networks to their network magic.
+var knownNetworkMagics = map[string]int{
+	NetworkMainnet:   764824073,
+	NetworkPreprod:   1,
+	NetworkPreview:   2,
+	NetworkSanchonet: 4,
+}
+
+// Network identifies the Cardano network the watcher is connected to.
+type Network struct {
+	Name  string
+	Magic int
+}
+
+// KnownNetwork returns the public network with the given name.
+// It returns false if the name does not match any public network.
+func KnownNetwork(name string) (Network, bool) {
+	magic, ok := knownNetworkMagics[name]
+	if !ok {
+		return Network{}, false
+	}
+	return Network{Name: name, Magic: magic}, true
+}
+
+// IsMainnet reports whether the network is the Cardano mainnet.
+func (n Network) IsMainnet() bool {
+	return n.Name == NetworkMainnet
+}
+
+// CLIArgs returns the cardano-cli arguments selecting the network.
+func (n Network) CLIArgs() []string {
+	if n.IsMainnet() {
+		return []string{"--mainnet"}
+	}
+	return []string{"--testnet-magic", strconv.Itoa(n.Magic)}
+}
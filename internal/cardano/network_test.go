@@ -0,0 +1,25 @@
+package cardano
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetwork_CLIArgs(t *testing.T) {
+	t.Parallel()
+
+	mainnet, ok := KnownNetwork(NetworkMainnet)
+	require.True(t, ok)
+	require.Equal(t, []string{"--mainnet"}, mainnet.CLIArgs())
+
+	preview, ok := KnownNetwork(NetworkPreview)
+	require.True(t, ok)
+	require.Equal(t, []string{"--testnet-magic", "2"}, preview.CLIArgs())
+
+	_, ok = KnownNetwork("devnet")
+	require.False(t, ok)
+
+	devnet := Network{Name: "devnet", Magic: 42}
+	require.Equal(t, []string{"--testnet-magic", "42"}, devnet.CLIArgs())
+}
@@ -0,0 +1,51 @@
+package praos
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// cborBytes64 is the CBOR header of a 64 bytes byte string.
+var cborBytes64 = []byte{0x58, 0x40}
+
+// vrfSigningKeyType is the text envelope type of the VRF signing keys generated by cardano-cli.
+const vrfSigningKeyType = "VrfSigningKey_PraosVRF"
+
+type textEnvelope struct {
+	Type    string `json:"type"`
+	CborHex string `json:"cborHex"`
+}
+
+// ReadVRFSigningKey reads a VRF signing key in the text envelope format of
+// cardano-cli, e.g. a pool vrf.skey file.
+func ReadVRFSigningKey(path string) (*VRFPrivateKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read vrf signing key: %w", err)
+	}
+
+	var envelope textEnvelope
+	if err := json.Unmarshal(content, &envelope); err != nil {
+		return nil, fmt.Errorf("unable to parse vrf signing key %s: %w", path, err)
+	}
+	if envelope.Type != vrfSigningKeyType {
+		return nil, fmt.Errorf("key %s has type %q, expected a %s key", path, envelope.Type, vrfSigningKeyType)
+	}
+
+	raw, err := hex.DecodeString(envelope.CborHex)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode vrf signing key %s: %w", path, err)
+	}
+	if len(raw) != len(cborBytes64)+VRFPrivateKeySize || !bytes.HasPrefix(raw, cborBytes64) {
+		return nil, fmt.Errorf("vrf signing key %s is not a %d bytes key", path, VRFPrivateKeySize)
+	}
+
+	key, err := NewVRFPrivateKey(raw[len(cborBytes64):])
+	if err != nil {
+		return nil, fmt.Errorf("invalid vrf signing key %s: %w", path, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,115 @@
+package praos
+
+import (
+	"encoding/binary"
+	"math/big"
+	"strconv"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// precision of the floating point computations of the leader threshold, well
+// above the 256 bits of the leader values it is compared to.
+const precision = 512
+
+// leaderValuePrefix is prepended to the VRF output to derive the leader value.
+const leaderValuePrefix = 'L'
+
+// certNatMax is the exclusive upper bound of the leader values.
+var certNatMax = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// VRFInput returns the VRF input of a slot: the blake2b-256 hash of the slot
+// as a big endian uint64 followed by the epoch nonce.
+func VRFInput(slot uint64, epochNonce []byte) []byte {
+	input := make([]byte, 8, 8+len(epochNonce))
+	binary.BigEndian.PutUint64(input, slot)
+	hash := blake2b.Sum256(append(input, epochNonce...))
+	return hash[:]
+}
+
+// LeaderValue returns the leader value derived from a VRF output as a natural
+// number lower than 2^256.
+func LeaderValue(output []byte) *big.Int {
+	hash := blake2b.Sum256(append([]byte{leaderValuePrefix}, output...))
+	return new(big.Int).SetBytes(hash[:])
+}
+
+// LeaderThreshold returns the bound below which a leader value wins a slot
+// for a pool holding sigma of the active stake: 2^256 * (1 - (1 - f)^sigma)
+// where f is the active slots coefficient.
+func LeaderThreshold(sigma *big.Rat, activeSlotsCoeff float64) *big.Int {
+	f := exactRat(activeSlotsCoeff)
+	one := big.NewRat(1, 1)
+	switch {
+	case f.Cmp(one) >= 0:
+		return new(big.Int).Set(certNatMax)
+	case sigma.Sign() <= 0:
+		return new(big.Int)
+	}
+
+	// (1 - f)^sigma = exp(sigma * ln(1 - f))
+	x := newFloat().SetRat(new(big.Rat).Sub(one, f))
+	x = ln(x)
+	x.Mul(x, newFloat().SetRat(sigma))
+	x = exp(x)
+
+	threshold := newFloat().Sub(newFloat().SetInt64(1), x)
+	threshold.Mul(threshold, newFloat().SetInt(certNatMax))
+	bound, _ := threshold.Int(nil)
+	return bound
+}
+
+// IsLeader reports whether a VRF output wins the slot for the given threshold.
+func IsLeader(output []byte, threshold *big.Int) bool {
+	return LeaderValue(output).Cmp(threshold) < 0
+}
+
+// exactRat returns the shortest decimal representation of f as a rational,
+// e.g. 1/20 for 0.05, like the ledger reads it from the genesis.
+func exactRat(f float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return r
+}
+
+func newFloat() *big.Float {
+	return new(big.Float).SetPrec(precision)
+}
+
+// ln returns the natural logarithm of z > 0 with the series
+// ln(z) = 2 * sum(u^(2k+1) / (2k+1)) where u = (z - 1) / (z + 1).
+func ln(z *big.Float) *big.Float {
+	one := newFloat().SetInt64(1)
+	u := newFloat().Quo(newFloat().Sub(z, one), newFloat().Add(z, one))
+	u2 := newFloat().Mul(u, u)
+
+	sum := newFloat()
+	power := newFloat().Set(u)
+	for k := int64(0); ; k++ {
+		term := newFloat().Quo(power, newFloat().SetInt64(2*k+1))
+		if converged(term) {
+			break
+		}
+		sum.Add(sum, term)
+		power.Mul(power, u2)
+	}
+	return sum.Mul(sum, newFloat().SetInt64(2))
+}
+
+// exp returns e^x with its Taylor series.
+func exp(x *big.Float) *big.Float {
+	sum := newFloat().SetInt64(1)
+	term := newFloat().SetInt64(1)
+	for n := int64(1); ; n++ {
+		term.Mul(term, x)
+		term.Quo(term, newFloat().SetInt64(n))
+		if converged(term) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	return sum
+}
+
+func converged(term *big.Float) bool {
+	return term.Sign() == 0 || term.MantExp(nil) < -precision
+}
@@ -0,0 +1,143 @@
+package praos
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVRFInput(t *testing.T) {
+	t.Parallel()
+
+	nonce := decodeHex(t, "47c2b7ae9e783b753afe7fd28986ac1b39c37220e13b5df09245cd4c1125f759")
+	require.Len(t, VRFInput(42, nonce), 32)
+	require.Equal(t, VRFInput(42, nonce), VRFInput(42, nonce))
+	require.NotEqual(t, VRFInput(42, nonce), VRFInput(43, nonce))
+}
+
+func TestLeaderThreshold(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_WholeStakeWinsActiveSlotsCoeff", func(t *testing.T) {
+		t.Parallel()
+
+		// 1 - (1 - f)^1 = f
+		expected := new(big.Int).Div(certNatMax, big.NewInt(20))
+		threshold := LeaderThreshold(big.NewRat(1, 1), 0.05)
+		diff := new(big.Int).Sub(expected, threshold)
+		require.LessOrEqual(t, diff.Abs(diff).Cmp(big.NewInt(1)), 0)
+	})
+
+	t.Run("GoodPath_HalfStake", func(t *testing.T) {
+		t.Parallel()
+
+		// 1 - (1 - 0.75)^0.5 = 0.5
+		expected := new(big.Int).Div(certNatMax, big.NewInt(2))
+		threshold := LeaderThreshold(big.NewRat(1, 2), 0.75)
+		diff := new(big.Int).Sub(expected, threshold)
+		require.LessOrEqual(t, diff.Abs(diff).Cmp(big.NewInt(1)), 0)
+	})
+
+	t.Run("GoodPath_NoStakeNeverWins", func(t *testing.T) {
+		t.Parallel()
+
+		require.Zero(t, LeaderThreshold(new(big.Rat), 0.05).Sign())
+	})
+
+	t.Run("GoodPath_EverySlotIsActive", func(t *testing.T) {
+		t.Parallel()
+
+		require.Zero(t, LeaderThreshold(big.NewRat(1, 1000), 1).Cmp(certNatMax))
+	})
+
+	t.Run("GoodPath_ThresholdGrowsWithStake", func(t *testing.T) {
+		t.Parallel()
+
+		small := LeaderThreshold(big.NewRat(1, 10000), 0.05)
+		large := LeaderThreshold(big.NewRat(1, 1000), 0.05)
+		require.Equal(t, -1, small.Cmp(large))
+		// The threshold is slightly above sigma * f for small stakes
+		approx := new(big.Int).Div(certNatMax, big.NewInt(200000))
+		require.Equal(t, 1, small.Cmp(approx))
+	})
+}
+
+func TestLeaderSlots(t *testing.T) {
+	t.Parallel()
+
+	tv := vrfTestVectors[0]
+	key, err := NewVRFPrivateKey(append(decodeHex(t, tv.secretKey), decodeHex(t, tv.publicKey)...))
+	require.NoError(t, err)
+	nonce := decodeHex(t, "47c2b7ae9e783b753afe7fd28986ac1b39c37220e13b5df09245cd4c1125f759")
+	threshold := LeaderThreshold(big.NewRat(1, 1), 0.05)
+
+	slots, err := LeaderSlots(context.Background(), key, nonce, 1000, 4999, threshold)
+	require.NoError(t, err)
+
+	// About 5% of the slots are won with the whole stake
+	require.InDelta(t, 200, len(slots), 50)
+	require.IsIncreasing(t, slots)
+	for _, slot := range slots {
+		output, err := key.Output(VRFInput(uint64(slot), nonce))
+		require.NoError(t, err)
+		require.True(t, IsLeader(output, threshold))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = LeaderSlots(ctx, key, nonce, 1000, 4999, threshold)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestReadVRFSigningKey(t *testing.T) {
+	t.Parallel()
+
+	tv := vrfTestVectors[1]
+	dir := t.TempDir()
+
+	t.Run("GoodPath_TextEnvelope", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(dir, "vrf.skey")
+		content := `{"type": "VrfSigningKey_PraosVRF", "description": "VRF Signing Key", "cborHex": "5840` + tv.secretKey + tv.publicKey + `"}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		key, err := ReadVRFSigningKey(path)
+		require.NoError(t, err)
+		require.Equal(t, tv.publicKey, hex.EncodeToString(key.PublicKey()))
+	})
+
+	t.Run("SadPath_NotAVRFKey", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(dir, "kes.skey")
+		content := `{"type": "KesSigningKey_ed25519_kes_2^6", "cborHex": "5820` + tv.secretKey + `"}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		_, err := ReadVRFSigningKey(path)
+		require.ErrorContains(t, err, `has type "KesSigningKey_ed25519_kes_2^6", expected a VrfSigningKey_PraosVRF key`)
+	})
+
+	t.Run("SadPath_WrongTypeWithVRFSizedKey", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(dir, "node.skey")
+		content := `{"type": "StakePoolExtendedSigningKey_ed25519_bip32", "cborHex": "5840` + tv.secretKey + tv.publicKey + `"}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		_, err := ReadVRFSigningKey(path)
+		require.ErrorContains(t, err, `has type "StakePoolExtendedSigningKey_ed25519_bip32", expected a VrfSigningKey_PraosVRF key`)
+	})
+
+	t.Run("SadPath_MissingFile", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ReadVRFSigningKey(filepath.Join(dir, "missing.skey"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,59 @@
+package praos
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"runtime"
+	"slices"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// scheduleChunkSize is the number of slots evaluated by a worker between two
+// context checks.
+const scheduleChunkSize = 1000
+
+// LeaderSlots returns the slots between firstSlot and lastSlot, both
+// included, won by key for the given epoch nonce and leader threshold. The
+// slots are evaluated in parallel on all the available CPUs.
+func LeaderSlots(ctx context.Context, key *VRFPrivateKey, epochNonce []byte, firstSlot, lastSlot int, threshold *big.Int) ([]int, error) {
+	var (
+		mu    sync.Mutex
+		slots []int
+	)
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(runtime.GOMAXPROCS(0))
+	for start := firstSlot; start <= lastSlot; start += scheduleChunkSize {
+		end := min(start+scheduleChunkSize-1, lastSlot)
+		eg.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("leader schedule computation interrupted: %w", err)
+			}
+
+			var won []int
+			for slot := start; slot <= end; slot++ {
+				output, err := key.Output(VRFInput(uint64(slot), epochNonce)) //nolint:gosec
+				if err != nil {
+					return fmt.Errorf("unable to evaluate vrf for slot %d: %w", slot, err)
+				}
+				if IsLeader(output, threshold) {
+					won = append(won, slot)
+				}
+			}
+
+			mu.Lock()
+			slots = append(slots, won...)
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	slices.Sort(slots)
+	return slots, nil
+}
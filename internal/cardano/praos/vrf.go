@@ -0,0 +1,215 @@
+package praos
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"filippo.io/edwards25519/field"
+	"golang.org/x/crypto/blake2b"
+)
+
+// VRF sizes of ECVRF-ED25519-SHA512-Elligator2 as specified by
+// draft-irtf-cfrg-vrf-03, the VRF used by Praos.
+const (
+	VRFSeedSize       = 32
+	VRFPrivateKeySize = 64
+	VRFPublicKeySize  = 32
+	VRFProofSize      = 80
+	VRFOutputSize     = 64
+)
+
+// Domain separation bytes of the draft-03 Elligator2 suite.
+const (
+	suiteString     = 0x04
+	hashToCurveByte = 0x01
+	hashPointsByte  = 0x02
+	proofToHashByte = 0x03
+)
+
+// curve25519A is the Montgomery A coefficient of curve25519.
+var curve25519A = new(field.Element).Mult32(new(field.Element).One(), 486662)
+
+// VRFPrivateKey is a VRF signing key made of a 32 bytes seed followed by the
+// public key, like the ones generated by cardano-cli.
+type VRFPrivateKey struct {
+	secret    *edwards25519.Scalar
+	nonceKey  []byte
+	publicKey []byte
+}
+
+// NewVRFPrivateKey parses a 64 bytes VRF signing key and checks that its
+// public key matches its seed.
+func NewVRFPrivateKey(key []byte) (*VRFPrivateKey, error) {
+	if len(key) != VRFPrivateKeySize {
+		return nil, fmt.Errorf("invalid vrf private key size %d, expected %d", len(key), VRFPrivateKeySize)
+	}
+
+	az := sha512.Sum512(key[:VRFSeedSize])
+	secret, err := edwards25519.NewScalar().SetBytesWithClamping(az[:32])
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive vrf secret scalar: %w", err)
+	}
+
+	publicKey := new(edwards25519.Point).ScalarBaseMult(secret).Bytes()
+	if !ed25519.PublicKey(publicKey).Equal(ed25519.PublicKey(key[VRFSeedSize:])) {
+		return nil, errors.New("vrf public key does not match the private key seed")
+	}
+
+	return &VRFPrivateKey{
+		secret:    secret,
+		nonceKey:  az[32:],
+		publicKey: publicKey,
+	}, nil
+}
+
+// PublicKey returns the VRF verification key.
+func (k *VRFPrivateKey) PublicKey() []byte {
+	return k.publicKey
+}
+
+// KeyHash returns the hex encoded blake2b-256 hash of the VRF verification
+// key, the vrf_key registered on-chain with the pool.
+func (k *VRFPrivateKey) KeyHash() string {
+	hash := blake2b.Sum256(k.publicKey)
+	return hex.EncodeToString(hash[:])
+}
+
+// Prove returns the VRF proof of alpha.
+func (k *VRFPrivateKey) Prove(alpha []byte) ([]byte, error) {
+	h, hString, err := k.hashToCurve(alpha)
+	if err != nil {
+		return nil, err
+	}
+	gamma := new(edwards25519.Point).ScalarMult(k.secret, h)
+
+	digest := sha512.New()
+	digest.Write(k.nonceKey)
+	digest.Write(hString)
+	nonce, err := edwards25519.NewScalar().SetUniformBytes(digest.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive vrf nonce: %w", err)
+	}
+
+	kB := new(edwards25519.Point).ScalarBaseMult(nonce)
+	kH := new(edwards25519.Point).ScalarMult(nonce, h)
+
+	// c is the 16 bytes prefix of the hash of the points
+	digest = sha512.New()
+	digest.Write([]byte{suiteString, hashPointsByte})
+	digest.Write(hString)
+	digest.Write(gamma.Bytes())
+	digest.Write(kB.Bytes())
+	digest.Write(kH.Bytes())
+	cBytes := make([]byte, 32)
+	copy(cBytes, digest.Sum(nil)[:16])
+	c, err := edwards25519.NewScalar().SetCanonicalBytes(cBytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive vrf challenge: %w", err)
+	}
+	s := edwards25519.NewScalar().MultiplyAdd(c, k.secret, nonce)
+
+	proof := make([]byte, 0, VRFProofSize)
+	proof = append(proof, gamma.Bytes()...)
+	proof = append(proof, cBytes[:16]...)
+	proof = append(proof, s.Bytes()...)
+	return proof, nil
+}
+
+// Output returns the VRF output of alpha without building the proof, which
+// is all the leader check needs.
+func (k *VRFPrivateKey) Output(alpha []byte) ([]byte, error) {
+	h, _, err := k.hashToCurve(alpha)
+	if err != nil {
+		return nil, err
+	}
+	return gammaToHash(new(edwards25519.Point).ScalarMult(k.secret, h)), nil
+}
+
+// ProofToHash returns the VRF output of a proof.
+func ProofToHash(proof []byte) ([]byte, error) {
+	if len(proof) != VRFProofSize {
+		return nil, fmt.Errorf("invalid vrf proof size %d, expected %d", len(proof), VRFProofSize)
+	}
+	gamma, err := new(edwards25519.Point).SetBytes(proof[:32])
+	if err != nil {
+		return nil, fmt.Errorf("invalid vrf proof gamma: %w", err)
+	}
+	return gammaToHash(gamma), nil
+}
+
+func gammaToHash(gamma *edwards25519.Point) []byte {
+	digest := sha512.New()
+	digest.Write([]byte{suiteString, proofToHashByte})
+	digest.Write(new(edwards25519.Point).MultByCofactor(gamma).Bytes())
+	return digest.Sum(nil)
+}
+
+// hashToCurve maps the public key and alpha to a point of the prime order
+// subgroup with Elligator2, returning the point and its encoding.
+func (k *VRFPrivateKey) hashToCurve(alpha []byte) (*edwards25519.Point, []byte, error) {
+	digest := sha512.New()
+	digest.Write([]byte{suiteString, hashToCurveByte})
+	digest.Write(k.publicKey)
+	digest.Write(alpha)
+	r := digest.Sum(nil)[:32]
+	r[31] &= 0x7f
+
+	y, err := elligator2(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	point, err := new(edwards25519.Point).SetBytes(y)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to decode elligator2 point: %w", err)
+	}
+	point.MultByCofactor(point)
+	return point, point.Bytes(), nil
+}
+
+// elligator2 maps a field element to the encoding of a point of the Edwards
+// curve, following ge25519_from_uniform of libsodium.
+func elligator2(r []byte) ([]byte, error) {
+	rr2, err := new(field.Element).SetBytes(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid elligator2 input: %w", err)
+	}
+	one := new(field.Element).One()
+
+	// x = -A / (1 + 2r^2)
+	rr2.Square(rr2)
+	rr2.Add(rr2, rr2)
+	rr2.Add(rr2, one)
+	x := new(field.Element).Invert(rr2)
+	x.Multiply(x, curve25519A)
+	x.Negate(x)
+
+	// e = legendre(x^3 + Ax^2 + x)
+	x2 := new(field.Element).Square(x)
+	e := new(field.Element).Multiply(x2, x)
+	e.Add(e, x)
+	e.Add(e, new(field.Element).Multiply(x2, curve25519A))
+	e = legendre(e)
+
+	// x = -x - A when e is not a square
+	eIsMinusOne := int(e.Bytes()[1] & 1)
+	negX := new(field.Element).Negate(x)
+	x.Select(negX, x, eIsMinusOne)
+	x.Subtract(x, new(field.Element).Select(curve25519A, new(field.Element).Zero(), eIsMinusOne))
+
+	// y = (x - 1) / (x + 1)
+	y := new(field.Element).Subtract(x, one)
+	y.Multiply(y, new(field.Element).Invert(new(field.Element).Add(x, one)))
+	return y.Bytes(), nil
+}
+
+// legendre returns e^((p-1)/2), computed as (e^((p-5)/8))^4 * e^2.
+func legendre(e *field.Element) *field.Element {
+	chi := new(field.Element).Pow22523(e)
+	chi.Square(chi)
+	chi.Square(chi)
+	return chi.Multiply(chi, new(field.Element).Square(e))
+}
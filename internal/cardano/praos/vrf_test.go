@@ -0,0 +1,106 @@
+package praos
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test vectors of ECVRF-ED25519-SHA512-Elligator2 from draft-irtf-cfrg-vrf-03,
+// the ones the libsodium VRF used by cardano-node and cncli is tested against.
+var vrfTestVectors = []struct {
+	name      string
+	secretKey string
+	publicKey string
+	alpha     string
+	proof     string
+	output    string
+}{
+	{
+		name:      "Example10",
+		secretKey: "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+		publicKey: "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		alpha:     "",
+		proof:     "b6b4699f87d56126c9117a7da55bd0085246f4c56dbc95d20172612e9d38e8d7ca65e573a126ed88d4e30a46f80a666854d675cf3ba81de0de043c3774f061560f55edc256a787afe701677c0f602900",
+		output:    "5b49b554d05c0cd5a5325376b3387de59d924fd1e13ded44648ab33c21349a603f25b84ec5ed887995b33da5e3bfcb87cd2f64521c4c62cf825cffabbe5d31cc",
+	},
+	{
+		name:      "Example11",
+		secretKey: "4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+		publicKey: "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		alpha:     "72",
+		proof:     "ae5b66bdf04b4c010bfe32b2fc126ead2107b697634f6f7337b9bff8785ee111200095ece87dde4dbe87343f6df3b107d91798c8a7eb1245d3bb9c5aafb093358c13e6ae1111a55717e895fd15f99f07",
+		output:    "94f4487e1b2fec954309ef1289ecb2e15043a2461ecc7b2ae7d4470607ef82eb1cfa97d84991fe4a7bfdfd715606bc27e2967a6c557cfb5875879b671740b7d8",
+	},
+	{
+		name:      "Example12",
+		secretKey: "c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+		publicKey: "fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+		alpha:     "af82",
+		proof:     "dfa2cba34b611cc8c833a6ea83b8eb1bb5e2ef2dd1b0c481bc42ff36ae7847f6ab52b976cfd5def172fa412defde270c8b8bdfbaae1c7ece17d9833b1bcf31064fff78ef493f820055b561ece45e1009",
+		output:    "2031837f582cd17a9af9e0c7ef5a6540e3453ed894b62c293686ca3c1e319dde9d0aa489a4b59a9594fc2328bc3deff3c8a0929a369a72b1180a596e016b5ded",
+	},
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func TestVRF(t *testing.T) {
+	t.Parallel()
+
+	for _, tv := range vrfTestVectors {
+		t.Run(tv.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := NewVRFPrivateKey(append(decodeHex(t, tv.secretKey), decodeHex(t, tv.publicKey)...))
+			require.NoError(t, err)
+			require.Equal(t, tv.publicKey, hex.EncodeToString(key.PublicKey()))
+
+			alpha := decodeHex(t, tv.alpha)
+			proof, err := key.Prove(alpha)
+			require.NoError(t, err)
+			require.Equal(t, tv.proof, hex.EncodeToString(proof))
+
+			output, err := ProofToHash(proof)
+			require.NoError(t, err)
+			require.Equal(t, tv.output, hex.EncodeToString(output))
+
+			output, err = key.Output(alpha)
+			require.NoError(t, err)
+			require.Equal(t, tv.output, hex.EncodeToString(output))
+		})
+	}
+}
+
+func TestNewVRFPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SadPath_InvalidSize", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewVRFPrivateKey(make([]byte, 32))
+		require.Error(t, err)
+	})
+
+	t.Run("SadPath_PublicKeyDoesNotMatchSeed", func(t *testing.T) {
+		t.Parallel()
+
+		key := append(decodeHex(t, vrfTestVectors[0].secretKey), decodeHex(t, vrfTestVectors[1].publicKey)...)
+		_, err := NewVRFPrivateKey(key)
+		require.Error(t, err)
+	})
+}
+
+func TestKeyHash(t *testing.T) {
+	t.Parallel()
+
+	// vrf_key of a pool registered with the key of example 10
+	key, err := NewVRFPrivateKey(append(decodeHex(t, vrfTestVectors[0].secretKey), decodeHex(t, vrfTestVectors[0].publicKey)...))
+	require.NoError(t, err)
+	require.Equal(t, "7849ac3049680be1ef762efe0d36e01733c3464eb0c7c558138acf24bb263bd3", key.KeyHash())
+}
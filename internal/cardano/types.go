@@ -1,6 +1,9 @@
 package cardano
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type ClientLeaderLogsResponse struct {
 	Status           string         `json:"status"`
@@ -30,6 +33,21 @@ type ClientQueryStakeSnapshotResponse struct {
 	Total TotalStakeInfo           `json:"total,omitempty"`
 }
 
+// LedgerSetStake returns the stake of a pool and the total active stake in
+// the snapshot used by the given ledger set: prev, current or next.
+func (r ClientQueryStakeSnapshotResponse) LedgerSetStake(poolHex string, ledgerSet string) (int, int, error) {
+	pool := r.Pools[poolHex]
+	switch ledgerSet {
+	case "prev":
+		return pool.StakeGo, r.Total.StakeGo, nil
+	case "current":
+		return pool.StakeSet, r.Total.StakeSet, nil
+	case "next":
+		return pool.StakeMark, r.Total.StakeMark, nil
+	}
+	return 0, 0, fmt.Errorf("invalid ledger set %s", ledgerSet)
+}
+
 type PoolStakeInfo struct {
 	StakeGo   int `json:"stakeGo,omitempty"`
 	StakeMark int `json:"stakeMark,omitempty"`
@@ -46,3 +64,25 @@ type ClientProtocolStateResponse struct {
 	CandidateNonce      string `json:"candidateNonce"`
 	LastEpochBlockNonce string `json:"lastEpochBlockNonce"`
 }
+
+// ClientKESPeriodInfoResponse is the output of cardano-cli query kes-period-info
+// for the operational certificate of a pool.
+//
+//nolint:tagliatelle
+type ClientKESPeriodInfoResponse struct {
+	CurrentKESPeriod          int        `json:"qKesCurrentKesPeriod"`
+	StartKESInterval          int        `json:"qKesStartKesInterval"`
+	EndKESInterval            int        `json:"qKesEndKesInterval"`
+	RemainingSlotsInKESPeriod int        `json:"qKesRemainingSlotsInKesPeriod"`
+	OnDiskOpCertCounter       int        `json:"qKesOnDiskOperationalCertificateNumber"`
+	NodeStateOpCertCounter    *int       `json:"qKesNodeStateOperationalCertificateNumber"`
+	MaxKESEvolutions          int        `json:"qKesMaxKESEvolutions"`
+	SlotsPerKESPeriod         int        `json:"qKesSlotsPerKesPeriod"`
+	KESKeyExpiry              *time.Time `json:"qKesKesKeyExpiry"`
+}
+
+// RemainingKESPeriods returns the number of KES periods left before the KES
+// key of the operational certificate expires.
+func (r ClientKESPeriodInfoResponse) RemainingKESPeriods() int {
+	return max(r.EndKESInterval-r.CurrentKESPeriod, 0)
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"time"
@@ -11,6 +12,9 @@ import (
 	"github.com/pressly/goose/v3"
 )
 
+// ErrPendingMigrations is returned when the schema of the database is behind the migrations of the watcher.
+var ErrPendingMigrations = errors.New("database has pending migrations")
+
 type Options struct {
 	URL          string
 	Path         string
@@ -55,3 +59,26 @@ func (db *database) MigrateUp(migrationsFS fs.FS) error {
 
 	return nil
 }
+
+// CheckMigrations returns ErrPendingMigrations when a migration of migrationsFS
+// is not applied to the database. Unlike MigrateUp, the schema is left as is.
+func (db *database) CheckMigrations(ctx context.Context, migrationsFS fs.FS) error {
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db.DB.DB, migrationsFS)
+	if err != nil {
+		return fmt.Errorf("failed to check migrations: %w", err)
+	}
+
+	pending, err := provider.HasPending(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check migrations: %w", err)
+	}
+	if !pending {
+		return nil
+	}
+
+	current, target, err := provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check migrations: %w", err)
+	}
+	return fmt.Errorf("%w: schema is at version %d, version %d is expected", ErrPendingMigrations, current, target)
+}
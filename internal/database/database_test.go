@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabase_CheckMigrations(t *testing.T) {
+	ctx := context.Background()
+
+	applied := fstest.MapFS{
+		"00001_first.sql": {Data: []byte("-- +goose Up\nCREATE TABLE first (id INTEGER);\n-- +goose Down\nDROP TABLE first;\n")},
+	}
+	latest := fstest.MapFS{
+		"00001_first.sql":  applied["00001_first.sql"],
+		"00002_second.sql": {Data: []byte("-- +goose Up\nCREATE TABLE second (id INTEGER);\n-- +goose Down\nDROP TABLE second;\n")},
+	}
+
+	path := filepath.Join(t.TempDir(), "watcher.db")
+	db := NewDatabase(Options{URL: "?_journal=WAL", Path: path, MaxOpenConns: 1})
+	require.NoError(t, db.Connect(ctx))
+	require.NoError(t, db.MigrateUp(applied))
+	require.NoError(t, db.Close())
+
+	t.Run("GoodPath_SchemaUpToDate", func(t *testing.T) {
+		readOnly := NewDatabase(Options{URL: "?mode=ro", Path: path, MaxOpenConns: 1})
+		require.NoError(t, readOnly.Connect(ctx))
+		defer readOnly.Close()
+
+		require.NoError(t, readOnly.CheckMigrations(ctx, applied))
+	})
+
+	t.Run("SadPath_SchemaBehind", func(t *testing.T) {
+		readOnly := NewDatabase(Options{URL: "?mode=ro", Path: path, MaxOpenConns: 1})
+		require.NoError(t, readOnly.Connect(ctx))
+		defer readOnly.Close()
+
+		err := readOnly.CheckMigrations(ctx, latest)
+		require.ErrorIs(t, err, ErrPendingMigrations)
+		require.ErrorContains(t, err, "schema is at version 1, version 2 is expected")
+
+		// the pending migration is not applied
+		var tables int
+		require.NoError(t, readOnly.GetContext(ctx, &tables, "SELECT COUNT(*) FROM sqlite_master WHERE name = 'second'"))
+		require.Equal(t, 0, tables)
+	})
+}
@@ -20,17 +20,59 @@ type Collection struct {
 	PoolsPledgeMet                    *prometheus.GaugeVec
 	PoolsSaturationLevel              *prometheus.GaugeVec
 	PoolsDRepRegistered               *prometheus.GaugeVec
+	PoolRelayUp                       *prometheus.GaugeVec
+	PoolRelayLatency                  *prometheus.GaugeVec
+	PoolRelayDNSResolved              *prometheus.GaugeVec
+	PoolRelaysUnreachable             *prometheus.GaugeVec
+	PoolMetadataValid                 *prometheus.GaugeVec
+	PoolLivePledge                    *prometheus.GaugeVec
+	PoolDeclaredPledge                *prometheus.GaugeVec
+	PoolPledgeMargin                  *prometheus.GaugeVec
+	PoolPledgeMarginWarning           *prometheus.GaugeVec
+	PoolOwnerBalance                  *prometheus.GaugeVec
+	PoolRetiringEpoch                 *prometheus.GaugeVec
+	PoolParamChanges                  *prometheus.CounterVec
 	MonitoredValidatorsCount          *prometheus.GaugeVec
 	MissedBlocks                      *prometheus.CounterVec
 	ConsecutiveMissedBlocks           *prometheus.GaugeVec
 	OrphanedBlocks                    *prometheus.CounterVec
 	ValidatedBlocks                   *prometheus.CounterVec
 	ExpectedBlocks                    *prometheus.GaugeVec
+	NextEpochExpectedBlocks           *prometheus.GaugeVec
+	NextEpochScheduleReady            *prometheus.GaugeVec
+	SlotScheduleRefreshStatus         *prometheus.GaugeVec
+	VRFKeyMatch                       *prometheus.GaugeVec
+	VRFKeyRotations                   *prometheus.CounterVec
+	OpCertCounter                     *prometheus.GaugeVec
+	OpCertCounterRegressions          *prometheus.CounterVec
+	OpCertStale                       *prometheus.GaugeVec
+	KESRemainingPeriods               *prometheus.GaugeVec
+	KESExpiryTimestamp                *prometheus.GaugeVec
+	IdealSlots                        *prometheus.GaugeVec
+	PoolSigma                         *prometheus.GaugeVec
+	MaxPerformance                    *prometheus.GaugeVec
+	LeaderLuck                        *prometheus.GaugeVec
+	ReconciliationUnscheduledBlocks   *prometheus.GaugeVec
+	ReconciliationUnrecordedBlocks    *prometheus.GaugeVec
+	ReconciliationUnrecordedSlots     *prometheus.GaugeVec
+	ReconciliationBlockMismatch       *prometheus.GaugeVec
+	SlotScheduleCacheHits             prometheus.Counter
+	SlotScheduleCacheMisses           prometheus.Counter
 	LatestSlotProcessedByBlockWatcher prometheus.Gauge
+	BlockWatcherProcessingDuration    prometheus.Histogram
 	NextSlotLeader                    *prometheus.GaugeVec
+	RemainingLeaderSlots              *prometheus.GaugeVec
 	HealthStatus                      prometheus.Gauge
+	HealthState                       *prometheus.GaugeVec
 	CardanoNodeUp                     *prometheus.GaugeVec
 	CardanoNodeActive                 *prometheus.GaugeVec
+	ChainDataProviderUp               *prometheus.GaugeVec
+	ChainDataProviderActive           *prometheus.GaugeVec
+	ChainDataProviderFailures         *prometheus.CounterVec
+	ChainDataRequests                 *prometheus.CounterVec
+	ChainDataRequestDuration          *prometheus.HistogramVec
+	ChainDataRequestErrors            *prometheus.CounterVec
+	ChainDataRequestRetries           *prometheus.CounterVec
 }
 
 func NewCollection() *Collection {
@@ -138,6 +180,102 @@ func NewCollection() *Collection {
 			},
 			[]string{"pool_name", "pool_id", "pool_instance"},
 		),
+		PoolRelayUp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_relay_up",
+				Help:      "Reachability of each relay registered by the pool: 1 = reachable, 0 = down",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "relay"},
+		),
+		PoolRelayLatency: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_relay_latency_seconds",
+				Help:      "Time spent to open a connection to the relay at the last successful probe",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "relay"},
+		),
+		PoolRelayDNSResolved: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_relay_dns_resolved",
+				Help:      "Whether the DNS name of the relay resolves: 1 = resolved, 0 = unresolved",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "relay"},
+		),
+		PoolRelaysUnreachable: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_relays_unreachable",
+				Help:      "Whether none of the relays registered by the pool is reachable (0 or 1)",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		PoolMetadataValid: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_metadata_valid",
+				Help:      "Whether the metadata served by the pool matches its on-chain hash and CIP-6, with the reason of the last check (0 or 1)",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "reason"},
+		),
+		PoolLivePledge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_live_pledge",
+				Help:      "Live pledge of the pool in lovelace",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		PoolDeclaredPledge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_declared_pledge",
+				Help:      "Pledge declared in the registration of the pool in lovelace",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		PoolPledgeMargin: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_pledge_margin",
+				Help:      "Live pledge minus declared pledge of the pool in lovelace",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		PoolPledgeMarginWarning: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_pledge_margin_warning",
+				Help:      "Whether the pledge margin of the pool is under the warning threshold (0 or 1)",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		PoolOwnerBalance: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_owner_balance",
+				Help:      "Balance controlled by each owner stake address of the pool in lovelace",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "owner"},
+		),
+		PoolRetiringEpoch: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_retiring_epoch",
+				Help:      "Epoch at which the retirement of the pool is scheduled, 0 when no retirement is scheduled",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		PoolParamChanges: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_param_changes_total",
+				Help:      "Number of changes detected in the registered parameters of the pool",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "param"},
+		),
 		MonitoredValidatorsCount: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "cardano_validator_watcher",
@@ -186,6 +324,164 @@ func NewCollection() *Collection {
 			},
 			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
 		),
+		NextEpochExpectedBlocks: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "next_epoch_expected_blocks",
+				Help:      "number of expected blocks in the next epoch",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
+		),
+		NextEpochScheduleReady: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "next_epoch_schedule_ready",
+				Help:      "Leader schedule of the next epoch computed: 1 = ready, 0 = pending",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
+		),
+		SlotScheduleRefreshStatus: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "slot_schedule_refresh_status",
+				Help:      "Status of the last refresh of the leader schedule of the current epoch: 1 = ok, 0 = failed",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		VRFKeyMatch: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "vrf_key_match",
+				Help:      "Whether the VRF signing key of the pool matches the vrf_key registered on-chain: 1 = match, 0 = mismatch",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		VRFKeyRotations: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "vrf_key_rotations_total",
+				Help:      "Number of changes of the vrf_key registered on-chain for the pool",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		OpCertCounter: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "op_cert_counter",
+				Help:      "Operational certificate counter of the latest block forged by the pool",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		OpCertCounterRegressions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "op_cert_counter_regressions_total",
+				Help:      "Number of blocks forged by the pool with an operational certificate counter lower than a previous block",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		OpCertStale: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "op_cert_stale",
+				Help:      "Whether the operational certificate on disk is rejected by the counter seen on-chain: 1 = stale, 0 = valid",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		KESRemainingPeriods: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "kes_remaining_periods",
+				Help:      "Number of KES periods left before the KES key of the operational certificate expires",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		KESExpiryTimestamp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "kes_expiry_timestamp_seconds",
+				Help:      "Unix timestamp at which the KES key of the operational certificate expires",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance"},
+		),
+		IdealSlots: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "ideal_slots",
+				Help:      "number of leader slots expected from the stake of the pool in the epoch",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
+		),
+		PoolSigma: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "pool_sigma",
+				Help:      "share of the active stake delegated to the pool in the epoch",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
+		),
+		MaxPerformance: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "max_performance",
+				Help:      "assigned leader slots over ideal slots in the epoch, in percent",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
+		),
+		LeaderLuck: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "leader_luck",
+				Help:      "ratio of assigned leader slots to ideal slots in the epoch, 1 meaning as many slots as expected",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
+		),
+		ReconciliationUnscheduledBlocks: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "reconciliation_unscheduled_blocks",
+				Help:      "number of blocks forged on-chain by the pool in the closed epoch in slots missing from its schedule",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
+		),
+		ReconciliationUnrecordedBlocks: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "reconciliation_unrecorded_blocks",
+				Help:      "number of blocks forged on-chain by the pool in leader slots of the closed epoch that were not recorded as validated",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
+		),
+		ReconciliationUnrecordedSlots: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "reconciliation_unrecorded_slots",
+				Help:      "number of leader slots of the closed epoch without a recorded outcome",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
+		),
+		ReconciliationBlockMismatch: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "reconciliation_block_mismatch",
+				Help:      "difference between the blocks forged on-chain and the validated blocks recorded for the closed epoch",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
+		),
+		SlotScheduleCacheHits: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "slot_schedule_cache_hits_total",
+				Help:      "number of slot leader schedule lookups served from memory",
+			},
+		),
+		SlotScheduleCacheMisses: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "slot_schedule_cache_misses_total",
+				Help:      "number of slot leader schedule lookups loaded from the database",
+			},
+		),
 		LatestSlotProcessedByBlockWatcher: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: "cardano_validator_watcher",
@@ -193,6 +489,14 @@ func NewCollection() *Collection {
 				Help:      "latest slot processed by the block watcher",
 			},
 		),
+		BlockWatcherProcessingDuration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "block_watcher_processing_duration_seconds",
+				Help:      "time spent by the block watcher to process a range of slots",
+				Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300},
+			},
+		),
 		NextSlotLeader: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "cardano_validator_watcher",
@@ -201,6 +505,14 @@ func NewCollection() *Collection {
 			},
 			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
 		),
+		RemainingLeaderSlots: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "remaining_leader_slots",
+				Help:      "number of leader slots remaining in the current epoch for each monitored pool",
+			},
+			[]string{"pool_name", "pool_id", "pool_instance", "epoch"},
+		),
 		HealthStatus: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: "cardano_validator_watcher",
@@ -208,6 +520,14 @@ func NewCollection() *Collection {
 				Help:      "Health status of the Cardano validator watcher: 1 = healthy, 0 = unhealthy",
 			},
 		),
+		HealthState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "health_state",
+				Help:      "Health state of the Cardano validator watcher, set to 1 for the current state (healthy, unhealthy or unauthorized)",
+			},
+			[]string{"state"},
+		),
 		CardanoNodeUp: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "cardano_validator_watcher",
@@ -224,6 +544,63 @@ func NewCollection() *Collection {
 			},
 			[]string{"remote"},
 		),
+		ChainDataProviderUp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "chain_data_provider_up",
+				Help:      "Availability of each configured chain data provider: 1 = available, 0 = in cooldown",
+			},
+			[]string{"provider"},
+		),
+		ChainDataProviderActive: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "chain_data_provider_active",
+				Help:      "Chain data provider currently serving requests: 1 = active, 0 = standby",
+			},
+			[]string{"provider"},
+		),
+		ChainDataProviderFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "chain_data_provider_failures_total",
+				Help:      "number of failed calls that caused a chain data provider to be skipped",
+			},
+			[]string{"provider"},
+		),
+		ChainDataRequests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "chain_data_requests_total",
+				Help:      "number of HTTP requests sent to the chain data providers",
+			},
+			[]string{"provider", "endpoint", "code"},
+		),
+		ChainDataRequestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "chain_data_request_duration_seconds",
+				Help:      "time spent waiting for the chain data providers to answer a request",
+				Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+			},
+			[]string{"provider", "endpoint"},
+		),
+		ChainDataRequestErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "chain_data_request_errors_total",
+				Help:      "number of failed requests to the chain data providers by error class",
+			},
+			[]string{"provider", "endpoint", "class"},
+		),
+		ChainDataRequestRetries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "cardano_validator_watcher",
+				Name:      "chain_data_request_retries_total",
+				Help:      "number of retried requests to the chain data providers",
+			},
+			[]string{"provider", "endpoint"},
+		),
 	}
 }
 
@@ -244,15 +621,57 @@ func (m *Collection) MustRegister(reg prometheus.Registerer) {
 	reg.MustRegister(m.PoolsPledgeMet)
 	reg.MustRegister(m.PoolsSaturationLevel)
 	reg.MustRegister(m.PoolsDRepRegistered)
+	reg.MustRegister(m.PoolRelayUp)
+	reg.MustRegister(m.PoolRelayLatency)
+	reg.MustRegister(m.PoolRelayDNSResolved)
+	reg.MustRegister(m.PoolRelaysUnreachable)
+	reg.MustRegister(m.PoolMetadataValid)
+	reg.MustRegister(m.PoolLivePledge)
+	reg.MustRegister(m.PoolDeclaredPledge)
+	reg.MustRegister(m.PoolPledgeMargin)
+	reg.MustRegister(m.PoolPledgeMarginWarning)
+	reg.MustRegister(m.PoolOwnerBalance)
+	reg.MustRegister(m.PoolRetiringEpoch)
+	reg.MustRegister(m.PoolParamChanges)
 	reg.MustRegister(m.MonitoredValidatorsCount)
 	reg.MustRegister(m.MissedBlocks)
 	reg.MustRegister(m.ConsecutiveMissedBlocks)
 	reg.MustRegister(m.ValidatedBlocks)
 	reg.MustRegister(m.OrphanedBlocks)
 	reg.MustRegister(m.ExpectedBlocks)
+	reg.MustRegister(m.NextEpochExpectedBlocks)
+	reg.MustRegister(m.NextEpochScheduleReady)
+	reg.MustRegister(m.SlotScheduleRefreshStatus)
+	reg.MustRegister(m.VRFKeyMatch)
+	reg.MustRegister(m.VRFKeyRotations)
+	reg.MustRegister(m.OpCertCounter)
+	reg.MustRegister(m.OpCertCounterRegressions)
+	reg.MustRegister(m.OpCertStale)
+	reg.MustRegister(m.KESRemainingPeriods)
+	reg.MustRegister(m.KESExpiryTimestamp)
+	reg.MustRegister(m.IdealSlots)
+	reg.MustRegister(m.PoolSigma)
+	reg.MustRegister(m.MaxPerformance)
+	reg.MustRegister(m.LeaderLuck)
+	reg.MustRegister(m.ReconciliationUnscheduledBlocks)
+	reg.MustRegister(m.ReconciliationUnrecordedBlocks)
+	reg.MustRegister(m.ReconciliationUnrecordedSlots)
+	reg.MustRegister(m.ReconciliationBlockMismatch)
+	reg.MustRegister(m.SlotScheduleCacheHits)
+	reg.MustRegister(m.SlotScheduleCacheMisses)
 	reg.MustRegister(m.LatestSlotProcessedByBlockWatcher)
+	reg.MustRegister(m.BlockWatcherProcessingDuration)
 	reg.MustRegister(m.NextSlotLeader)
+	reg.MustRegister(m.RemainingLeaderSlots)
 	reg.MustRegister(m.HealthStatus)
+	reg.MustRegister(m.HealthState)
 	reg.MustRegister(m.CardanoNodeUp)
 	reg.MustRegister(m.CardanoNodeActive)
+	reg.MustRegister(m.ChainDataProviderUp)
+	reg.MustRegister(m.ChainDataProviderActive)
+	reg.MustRegister(m.ChainDataProviderFailures)
+	reg.MustRegister(m.ChainDataRequests)
+	reg.MustRegister(m.ChainDataRequestDuration)
+	reg.MustRegister(m.ChainDataRequestErrors)
+	reg.MustRegister(m.ChainDataRequestRetries)
 }
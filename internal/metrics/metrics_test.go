@@ -33,7 +33,7 @@ func TestMustRegister(t *testing.T) {
 	metrics.MustRegister(registry)
 
 	// The expected number of metrics to be registered, based on the definitions provided in the Collection struct.
-	expectedMetricsCount := 22
+	expectedMetricsCount := 25
 
 	var totalRegisteredMetrics int
 	size, _ := registry.Gather()
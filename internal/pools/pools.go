@@ -9,6 +9,7 @@ type Pool struct {
 	Key             string `mapstructure:"key"`
 	Exclude         bool   `mapstructure:"exclude"`
 	AllowEmptySlots bool   `mapstructure:"allow-empty-slots"`
+	OpCert          string `mapstructure:"op-cert"`
 }
 
 type PoolStats struct {
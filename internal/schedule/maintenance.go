@@ -0,0 +1,196 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/kilnfi/cardano-validator-watcher/internal/slotleader"
+)
+
+const (
+	DefaultWindowCount  = 3
+	DefaultWindowMargin = 5 * time.Minute
+)
+
+// Window is a period without any leader slot for the pools of an instance.
+type Window struct {
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	DurationSeconds int64     `json:"duration_seconds"`
+}
+
+// Duration returns the duration of the window.
+func (w Window) Duration() time.Duration {
+	return w.End.Sub(w.Start)
+}
+
+// InstanceWindows holds the largest maintenance windows of an instance.
+type InstanceWindows struct {
+	Instance string   `json:"instance"`
+	Pools    []string `json:"pools"`
+	// Until is the end of the known schedule: the next epoch when all the
+	// pools of the instance have its schedule, the current epoch otherwise.
+	Until   time.Time `json:"until,omitzero"`
+	Windows []Window  `json:"windows"`
+	// Error is the reason the windows of the instance are unknown, e.g. a pool
+	// without the schedule of the current epoch. It is empty when Windows is known.
+	Error string `json:"error,omitempty"`
+}
+
+// Unknown reports whether the windows of the instance could not be found.
+func (w InstanceWindows) Unknown() bool {
+	return w.Error != ""
+}
+
+// WindowFinder finds the maintenance windows of the instances hosting the
+// pools, in the current and the next epoch.
+type WindowFinder struct {
+	slotLeader slotleader.SlotLeader
+	timeline   *cardanotime.Timeline
+	location   *time.Location
+	pools      pools.Pools
+	now        func() time.Time
+}
+
+// NewWindowFinder creates a finder of the maintenance windows of the active pools.
+func NewWindowFinder(slotLeader slotleader.SlotLeader, timeline *cardanotime.Timeline, location *time.Location, pools pools.Pools) *WindowFinder {
+	return &WindowFinder{
+		slotLeader: slotLeader,
+		timeline:   timeline,
+		location:   location,
+		pools:      pools,
+		now:        time.Now,
+	}
+}
+
+// Find returns, for each instance, the count largest windows without leader
+// slot from now. The windows keep a margin before and after each leader slot.
+// An instance whose schedule cannot be read is returned as unknown, with the
+// reason in its Error, and does not prevent the others from being returned.
+func (f *WindowFinder) Find(ctx context.Context, count int, margin time.Duration) ([]InstanceWindows, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("invalid count %d. Count must be greater than 0", count)
+	}
+	if margin < 0 {
+		return nil, fmt.Errorf("invalid margin %s. Margin must not be negative", margin)
+	}
+
+	instances := []string{}
+	poolsByInstance := make(map[string][]pools.Pool)
+	for _, pool := range f.pools.GetActivePools() {
+		if _, ok := poolsByInstance[pool.Instance]; !ok {
+			instances = append(instances, pool.Instance)
+		}
+		poolsByInstance[pool.Instance] = append(poolsByInstance[pool.Instance], pool)
+	}
+
+	now := f.now().Truncate(time.Second)
+	result := make([]InstanceWindows, 0, len(instances))
+	for _, instance := range instances {
+		windows, err := f.findInstanceWindows(ctx, instance, poolsByInstance[instance], now, count, margin)
+		if err != nil {
+			windows = InstanceWindows{
+				Instance: instance,
+				Pools:    poolIDs(poolsByInstance[instance]),
+				Windows:  []Window{},
+				Error:    err.Error(),
+			}
+		}
+		result = append(result, windows)
+	}
+	return result, nil
+}
+
+func (f *WindowFinder) findInstanceWindows(
+	ctx context.Context,
+	instance string,
+	instancePools []pools.Pool,
+	now time.Time,
+	count int,
+	margin time.Duration,
+) (InstanceWindows, error) {
+	currentEpoch := f.timeline.EpochOfSlot(f.timeline.TimeToSlot(now))
+	until := f.timeline.EpochEndTime(currentEpoch + 1)
+
+	slots := []int{}
+	for _, pool := range instancePools {
+		current, err := f.slotLeader.GetSlotLeaders(ctx, pool.ID, currentEpoch)
+		if err != nil {
+			return InstanceWindows{}, fmt.Errorf("unable to get the schedule of pool %s in epoch %d: %w", pool.ID, currentEpoch, err)
+		}
+		slots = append(slots, current.Slots...)
+
+		// The schedule of the next epoch is only known once its nonce is frozen
+		next, err := f.slotLeader.GetSlotLeaders(ctx, pool.ID, currentEpoch+1)
+		if errors.Is(err, slotleader.ErrScheduleNotFound) {
+			until = f.timeline.EpochEndTime(currentEpoch)
+			continue
+		}
+		if err != nil {
+			return InstanceWindows{}, fmt.Errorf("unable to get the schedule of pool %s in epoch %d: %w", pool.ID, currentEpoch+1, err)
+		}
+		slots = append(slots, next.Slots...)
+	}
+	slices.Sort(slots)
+	slots = slices.Compact(slots)
+
+	windows := []Window{}
+	start, afterSlot := now, false
+	addWindow := func(end time.Time) {
+		from := start
+		if afterSlot {
+			from = from.Add(margin)
+		}
+		to := end.Add(-margin)
+		if to.After(from) {
+			windows = append(windows, Window{
+				Start:           from.In(f.location),
+				End:             to.In(f.location),
+				DurationSeconds: int64(to.Sub(from).Seconds()),
+			})
+		}
+	}
+
+	for _, slot := range slots {
+		at := f.timeline.SlotToTime(slot)
+		slotEnd := at.Add(f.timeline.SlotLength())
+		if !slotEnd.After(now) {
+			continue
+		}
+		if !at.Before(until) {
+			break
+		}
+		addWindow(at)
+		start, afterSlot = slotEnd, true
+	}
+	addWindow(until)
+
+	sort.SliceStable(windows, func(i, j int) bool {
+		return windows[i].Duration() > windows[j].Duration()
+	})
+	if len(windows) > count {
+		windows = windows[:count]
+	}
+
+	return InstanceWindows{
+		Instance: instance,
+		Pools:    poolIDs(instancePools),
+		Until:    until.In(f.location),
+		Windows:  windows,
+	}, nil
+}
+
+// poolIDs returns the IDs of the pools.
+func poolIDs(instancePools []pools.Pool) []string {
+	ids := make([]string, 0, len(instancePools))
+	for _, pool := range instancePools {
+		ids = append(ids, pool.ID)
+	}
+	return ids
+}
@@ -0,0 +1,171 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/kilnfi/cardano-validator-watcher/internal/slotleader"
+	slotleadermocks "github.com/kilnfi/cardano-validator-watcher/internal/slotleader/mocks"
+)
+
+func setupWindowFinder(t *testing.T, pools pools.Pools) (*WindowFinder, *slotleadermocks.MockSlotLeader) {
+	t.Helper()
+
+	genesis := cardanotime.ShelleyGenesis{
+		SystemStart:      time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+		NetworkMagic:     1,
+		EpochLength:      432000,
+		SlotLength:       1,
+		ActiveSlotsCoeff: 0.05,
+		SecurityParam:    2160,
+	}
+
+	slotLeader := slotleadermocks.NewMockSlotLeader(t)
+	finder := NewWindowFinder(slotLeader, cardanotime.NewTimeline(genesis, 0), time.UTC, pools)
+	// slot 43243200, in the middle of epoch 100
+	finder.now = func() time.Time {
+		return time.Date(2023, 10, 14, 12, 0, 0, 0, time.UTC)
+	}
+	return finder, slotLeader
+}
+
+func TestWindowFinder_Find(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_LargestWindowsPerInstance", func(t *testing.T) {
+		t.Parallel()
+
+		finder, slotLeader := setupWindowFinder(t, pools.Pools{
+			{ID: "pool-0", Instance: "producer-a"},
+			{ID: "pool-1", Instance: "producer-a"},
+			{ID: "pool-2", Instance: "producer-b"},
+			{ID: "pool-3", Instance: "producer-c", Exclude: true},
+		})
+
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-0", 100).
+			Return(slotleader.Schedule{Slots: []int{43200100, 43250400}}, nil)
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-0", 101).
+			Return(slotleader.Schedule{Slots: []int{43635600}}, nil)
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-1", 100).
+			Return(slotleader.Schedule{Slots: []int{43329600}}, nil)
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-1", 101).
+			Return(slotleader.Schedule{Slots: []int{}}, nil)
+		// the slot in progress is not a window and the next epoch is not known yet
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-2", 100).
+			Return(slotleader.Schedule{Slots: []int{43243200}}, nil)
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-2", 101).
+			Return(slotleader.Schedule{}, slotleader.ErrScheduleNotFound)
+
+		windows, err := finder.Find(context.Background(), 2, 10*time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, []InstanceWindows{
+			{
+				Instance: "producer-a",
+				Pools:    []string{"pool-0", "pool-1"},
+				Until:    time.Date(2023, 10, 24, 0, 0, 0, 0, time.UTC),
+				Windows: []Window{
+					{
+						Start:           time.Date(2023, 10, 19, 1, 10, 1, 0, time.UTC),
+						End:             time.Date(2023, 10, 23, 23, 50, 0, 0, time.UTC),
+						DurationSeconds: 427199,
+					},
+					{
+						Start:           time.Date(2023, 10, 15, 12, 10, 1, 0, time.UTC),
+						End:             time.Date(2023, 10, 19, 0, 50, 0, 0, time.UTC),
+						DurationSeconds: 304799,
+					},
+				},
+			},
+			{
+				Instance: "producer-b",
+				Pools:    []string{"pool-2"},
+				Until:    time.Date(2023, 10, 19, 0, 0, 0, 0, time.UTC),
+				Windows: []Window{
+					{
+						Start:           time.Date(2023, 10, 14, 12, 10, 1, 0, time.UTC),
+						End:             time.Date(2023, 10, 18, 23, 50, 0, 0, time.UTC),
+						DurationSeconds: 387599,
+					},
+				},
+			},
+		}, windows)
+	})
+
+	t.Run("GoodPath_WindowStartsNow", func(t *testing.T) {
+		t.Parallel()
+
+		finder, slotLeader := setupWindowFinder(t, pools.Pools{{ID: "pool-0", Instance: "producer-a"}})
+
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-0", 100).
+			Return(slotleader.Schedule{Slots: []int{43250400}}, nil)
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-0", 101).
+			Return(slotleader.Schedule{}, slotleader.ErrScheduleNotFound)
+
+		windows, err := finder.Find(context.Background(), 5, time.Hour)
+		require.NoError(t, err)
+		require.Len(t, windows, 1)
+		require.Equal(t, []Window{
+			{
+				Start:           time.Date(2023, 10, 14, 15, 0, 1, 0, time.UTC),
+				End:             time.Date(2023, 10, 18, 23, 0, 0, 0, time.UTC),
+				DurationSeconds: 374399,
+			},
+			{
+				Start:           time.Date(2023, 10, 14, 12, 0, 0, 0, time.UTC),
+				End:             time.Date(2023, 10, 14, 13, 0, 0, 0, time.UTC),
+				DurationSeconds: 3600,
+			},
+		}, windows[0].Windows)
+	})
+
+	t.Run("SadPath_CurrentScheduleNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		finder, slotLeader := setupWindowFinder(t, pools.Pools{
+			{ID: "pool-0", Instance: "producer-a"},
+			{ID: "pool-1", Instance: "producer-a"},
+			{ID: "pool-2", Instance: "producer-b"},
+		})
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-0", 100).
+			Return(slotleader.Schedule{}, slotleader.ErrScheduleNotFound)
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-2", 100).
+			Return(slotleader.Schedule{Slots: []int{}}, nil)
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-2", 101).
+			Return(slotleader.Schedule{Slots: []int{}}, nil)
+
+		// the instance of the degraded pool is unknown, the other one is still returned
+		windows, err := finder.Find(context.Background(), 3, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, windows, 2)
+
+		require.True(t, windows[0].Unknown())
+		require.Equal(t, "producer-a", windows[0].Instance)
+		require.Equal(t, []string{"pool-0", "pool-1"}, windows[0].Pools)
+		require.Empty(t, windows[0].Windows)
+		require.Contains(t, windows[0].Error, "unable to get the schedule of pool pool-0 in epoch 100")
+
+		require.False(t, windows[1].Unknown())
+		require.Equal(t, "producer-b", windows[1].Instance)
+		require.Equal(t, []Window{
+			{
+				Start:           time.Date(2023, 10, 14, 12, 0, 0, 0, time.UTC),
+				End:             time.Date(2023, 10, 23, 23, 59, 0, 0, time.UTC),
+				DurationSeconds: 820740,
+			},
+		}, windows[1].Windows)
+	})
+
+	t.Run("SadPath_InvalidCount", func(t *testing.T) {
+		t.Parallel()
+
+		finder, _ := setupWindowFinder(t, pools.Pools{{ID: "pool-0", Instance: "producer-a"}})
+		_, err := finder.Find(context.Background(), 0, time.Minute)
+		require.ErrorContains(t, err, "invalid count 0")
+	})
+}
@@ -0,0 +1,233 @@
+package schedule
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/kilnfi/cardano-validator-watcher/internal/slotleader"
+)
+
+// Format is the output format of an exported schedule.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatICS  Format = "ics"
+)
+
+// ParseFormat returns the format matching its name.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case FormatCSV, FormatJSON, FormatICS:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid format %s. Format must be either %s, %s or %s", name, FormatCSV, FormatJSON, FormatICS)
+	}
+}
+
+// Slot is a leader slot of a pool with its wall-clock time.
+type Slot struct {
+	Epoch       int       `json:"epoch"`
+	PoolID      string    `json:"pool_id"`
+	PoolName    string    `json:"pool_name"`
+	Slot        int       `json:"slot"`
+	SlotInEpoch int       `json:"slot_in_epoch"`
+	At          time.Time `json:"at"`
+}
+
+// Exporter exports the leader schedule of the pools stored in the database.
+type Exporter struct {
+	slotLeader slotleader.SlotLeader
+	timeline   *cardanotime.Timeline
+	location   *time.Location
+	now        func() time.Time
+}
+
+// NewExporter creates an exporter converting the slots to wall-clock times in location.
+func NewExporter(slotLeader slotleader.SlotLeader, timeline *cardanotime.Timeline, location *time.Location) *Exporter {
+	return &Exporter{
+		slotLeader: slotLeader,
+		timeline:   timeline,
+		location:   location,
+		now:        time.Now,
+	}
+}
+
+// ResolveEpoch returns the epoch designated by value: "current", "next" or an epoch number.
+func (e *Exporter) ResolveEpoch(value string) (int, error) {
+	current := e.timeline.EpochOfSlot(e.timeline.TimeToSlot(e.now()))
+	switch value {
+	case "current":
+		return current, nil
+	case "next":
+		return current + 1, nil
+	}
+
+	epoch, err := strconv.Atoi(value)
+	if err != nil || epoch < 0 {
+		return 0, fmt.Errorf("invalid epoch %s. Epoch must be either current, next or an epoch number", value)
+	}
+	return epoch, nil
+}
+
+// Slots returns the leader slots of the pools in an epoch, ordered by slot.
+// The pools without the schedule of the epoch are skipped and returned in
+// missing, so they do not prevent the slots of the others from being exported.
+func (e *Exporter) Slots(ctx context.Context, epoch int, selectedPools []pools.Pool) ([]Slot, []pools.Pool, error) {
+	slots := []Slot{}
+	missing := []pools.Pool{}
+	for _, pool := range selectedPools {
+		schedule, err := e.slotLeader.GetSlotLeaders(ctx, pool.ID, epoch)
+		if errors.Is(err, slotleader.ErrScheduleNotFound) {
+			missing = append(missing, pool)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to get the schedule of pool %s in epoch %d: %w", pool.ID, epoch, err)
+		}
+
+		for _, slot := range schedule.Slots {
+			slots = append(slots, Slot{
+				Epoch:       epoch,
+				PoolID:      pool.ID,
+				PoolName:    pool.Name,
+				Slot:        slot,
+				SlotInEpoch: e.timeline.SlotInEpoch(slot),
+				At:          e.timeline.SlotToTime(slot).In(e.location),
+			})
+		}
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Slot < slots[j].Slot
+	})
+	return slots, missing, nil
+}
+
+// Write writes the slots to w in the given format.
+func (e *Exporter) Write(w io.Writer, format Format, slots []Slot) error {
+	switch format {
+	case FormatCSV:
+		return e.writeCSV(w, slots)
+	case FormatJSON:
+		return e.writeJSON(w, slots)
+	case FormatICS:
+		return e.writeICS(w, slots)
+	default:
+		return fmt.Errorf("unsupported format %s", format)
+	}
+}
+
+func (e *Exporter) writeCSV(w io.Writer, slots []Slot) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"epoch", "pool_id", "pool_name", "slot", "slot_in_epoch", "time"}); err != nil {
+		return fmt.Errorf("unable to write csv header: %w", err)
+	}
+	for _, slot := range slots {
+		record := []string{
+			strconv.Itoa(slot.Epoch),
+			slot.PoolID,
+			slot.PoolName,
+			strconv.Itoa(slot.Slot),
+			strconv.Itoa(slot.SlotInEpoch),
+			slot.At.Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("unable to write csv record of slot %d: %w", slot.Slot, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("unable to write csv: %w", err)
+	}
+	return nil
+}
+
+func (e *Exporter) writeJSON(w io.Writer, slots []Slot) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(slots); err != nil {
+		return fmt.Errorf("unable to write json: %w", err)
+	}
+	return nil
+}
+
+// writeICS writes one event per leader slot, lasting one slot. The events are
+// in UTC so calendars display them in the timezone of their users.
+func (e *Exporter) writeICS(w io.Writer, slots []Slot) error {
+	const icsTime = "20060102T150405Z"
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//kilnfi//cardano-validator-watcher//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Cardano leader slots",
+		"X-WR-TIMEZONE:" + e.location.String(),
+	}
+
+	stamp := e.now().UTC().Format(icsTime)
+	for _, slot := range slots {
+		start := slot.At.UTC()
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-%d@cardano-validator-watcher", slot.PoolID, slot.Slot),
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+start.Format(icsTime),
+			"DTEND:"+start.Add(e.timeline.SlotLength()).Format(icsTime),
+			"SUMMARY:"+escapeICSText(fmt.Sprintf("Leader slot of pool %s", slot.PoolName)),
+			"DESCRIPTION:"+escapeICSText(fmt.Sprintf("Pool %s is leader of slot %d (slot %d of epoch %d) at %s",
+				slot.PoolID, slot.Slot, slot.SlotInEpoch, slot.Epoch, slot.At.Format(time.RFC3339))),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldICSLine(line))
+		b.WriteString("\r\n")
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("unable to write ics: %w", err)
+	}
+	return nil
+}
+
+// foldICSLine splits the lines longer than 75 octets, the continuation lines
+// starting with a space (RFC 5545).
+func foldICSLine(line string) string {
+	const maxOctets = 75
+
+	var b strings.Builder
+	octets := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if octets+size > maxOctets {
+			b.WriteString("\r\n ")
+			octets = 1
+		}
+		b.WriteRune(r)
+		octets += size
+	}
+	return b.String()
+}
+
+// escapeICSText escapes the characters reserved in the text values of iCalendar (RFC 5545).
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
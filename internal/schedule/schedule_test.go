@@ -0,0 +1,201 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/kilnfi/cardano-validator-watcher/internal/slotleader"
+	slotleadermocks "github.com/kilnfi/cardano-validator-watcher/internal/slotleader/mocks"
+)
+
+func setupExporter(t *testing.T) (*Exporter, *slotleadermocks.MockSlotLeader) {
+	t.Helper()
+
+	genesis := cardanotime.ShelleyGenesis{
+		SystemStart:      time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+		NetworkMagic:     1,
+		EpochLength:      432000,
+		SlotLength:       1,
+		ActiveSlotsCoeff: 0.05,
+		SecurityParam:    2160,
+	}
+	location, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	slotLeader := slotleadermocks.NewMockSlotLeader(t)
+	exporter := NewExporter(slotLeader, cardanotime.NewTimeline(genesis, 0), location)
+	exporter.now = func() time.Time {
+		return time.Date(2023, 10, 14, 12, 0, 0, 0, time.UTC)
+	}
+	return exporter, slotLeader
+}
+
+func setupSlots(t *testing.T) []Slot {
+	t.Helper()
+
+	location, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	return []Slot{
+		{Epoch: 100, PoolID: "pool-0", PoolName: "pool-0", Slot: 43200100, SlotInEpoch: 100, At: time.Date(2023, 10, 14, 2, 1, 40, 0, location)},
+		{Epoch: 100, PoolID: "pool-1", PoolName: "kiln, pool-1", Slot: 43203600, SlotInEpoch: 3600, At: time.Date(2023, 10, 14, 3, 0, 0, 0, location)},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	format, err := ParseFormat("ICS")
+	require.NoError(t, err)
+	require.Equal(t, FormatICS, format)
+
+	_, err = ParseFormat("xml")
+	require.ErrorContains(t, err, "invalid format xml")
+}
+
+func TestResolveEpoch(t *testing.T) {
+	t.Parallel()
+
+	exporter, _ := setupExporter(t)
+	tests := []struct {
+		value    string
+		expected int
+		err      bool
+	}{
+		{value: "current", expected: 100},
+		{value: "next", expected: 101},
+		{value: "42", expected: 42},
+		{value: "-1", err: true},
+		{value: "previous", err: true},
+	}
+	for _, test := range tests {
+		epoch, err := exporter.ResolveEpoch(test.value)
+		if test.err {
+			require.ErrorContains(t, err, "invalid epoch")
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.expected, epoch)
+	}
+}
+
+func TestSlots(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_SlotsOfThePoolsAreMerged", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, slotLeader := setupExporter(t)
+		selectedPools := []pools.Pool{
+			{ID: "pool-0", Name: "pool-0"},
+			{ID: "pool-1", Name: "kiln, pool-1"},
+		}
+
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-0", 100).
+			Return(slotleader.Schedule{PoolID: "pool-0", Epoch: 100, Slots: []int{43200100}}, nil)
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-1", 100).
+			Return(slotleader.Schedule{PoolID: "pool-1", Epoch: 100, Slots: []int{43203600}}, nil)
+
+		slots, missing, err := exporter.Slots(context.Background(), 100, selectedPools)
+		require.NoError(t, err)
+		require.Empty(t, missing)
+		require.Len(t, slots, 2)
+		for i, expected := range setupSlots(t) {
+			require.Equal(t, expected.PoolID, slots[i].PoolID)
+			require.Equal(t, expected.Slot, slots[i].Slot)
+			require.Equal(t, expected.SlotInEpoch, slots[i].SlotInEpoch)
+			require.True(t, expected.At.Equal(slots[i].At))
+			require.Equal(t, "Europe/Paris", slots[i].At.Location().String())
+		}
+	})
+
+	t.Run("SadPath_ScheduleNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, slotLeader := setupExporter(t)
+		selectedPools := []pools.Pool{
+			{ID: "pool-0", Name: "pool-0"},
+			{ID: "pool-1", Name: "kiln, pool-1"},
+		}
+
+		// the pool without a schedule is skipped, the other one is still exported
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-0", 100).
+			Return(slotleader.Schedule{}, slotleader.ErrScheduleNotFound)
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-1", 100).
+			Return(slotleader.Schedule{PoolID: "pool-1", Epoch: 100, Slots: []int{43203600}}, nil)
+
+		slots, missing, err := exporter.Slots(context.Background(), 100, selectedPools)
+		require.NoError(t, err)
+		require.Equal(t, selectedPools[:1], missing)
+		require.Len(t, slots, 1)
+		require.Equal(t, "pool-1", slots[0].PoolID)
+	})
+
+	t.Run("SadPath_UnableToGetSchedule", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, slotLeader := setupExporter(t)
+		slotLeader.EXPECT().GetSlotLeaders(mock.Anything, "pool-0", 100).
+			Return(slotleader.Schedule{}, errors.New("database is locked"))
+
+		_, _, err := exporter.Slots(context.Background(), 100, []pools.Pool{{ID: "pool-0"}, {ID: "pool-1"}})
+		require.ErrorContains(t, err, "unable to get the schedule of pool pool-0 in epoch 100: database is locked")
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_CSV", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, _ := setupExporter(t)
+		var b bytes.Buffer
+		require.NoError(t, exporter.Write(&b, FormatCSV, setupSlots(t)))
+		require.Equal(t, `epoch,pool_id,pool_name,slot,slot_in_epoch,time
+100,pool-0,pool-0,43200100,100,2023-10-14T02:01:40+02:00
+100,pool-1,"kiln, pool-1",43203600,3600,2023-10-14T03:00:00+02:00
+`, b.String())
+	})
+
+	t.Run("GoodPath_JSON", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, _ := setupExporter(t)
+		var b bytes.Buffer
+		require.NoError(t, exporter.Write(&b, FormatJSON, setupSlots(t)[:1]))
+		require.JSONEq(t, `[{"epoch":100,"pool_id":"pool-0","pool_name":"pool-0","slot":43200100,"slot_in_epoch":100,"at":"2023-10-14T02:01:40+02:00"}]`, b.String())
+	})
+
+	t.Run("GoodPath_ICS", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, _ := setupExporter(t)
+		var b bytes.Buffer
+		require.NoError(t, exporter.Write(&b, FormatICS, setupSlots(t)[1:]))
+		require.Equal(t, "BEGIN:VCALENDAR\r\n"+
+			"VERSION:2.0\r\n"+
+			"PRODID:-//kilnfi//cardano-validator-watcher//EN\r\n"+
+			"CALSCALE:GREGORIAN\r\n"+
+			"METHOD:PUBLISH\r\n"+
+			"X-WR-CALNAME:Cardano leader slots\r\n"+
+			"X-WR-TIMEZONE:Europe/Paris\r\n"+
+			"BEGIN:VEVENT\r\n"+
+			"UID:pool-1-43203600@cardano-validator-watcher\r\n"+
+			"DTSTAMP:20231014T120000Z\r\n"+
+			"DTSTART:20231014T010000Z\r\n"+
+			"DTEND:20231014T010001Z\r\n"+
+			"SUMMARY:Leader slot of pool kiln\\, pool-1\r\n"+
+			"DESCRIPTION:Pool pool-1 is leader of slot 43203600 (slot 3600 of epoch 100)\r\n"+
+			"  at 2023-10-14T03:00:00+02:00\r\n"+
+			"END:VEVENT\r\n"+
+			"END:VCALENDAR\r\n", b.String())
+	})
+}
@@ -1,23 +1,35 @@
 package http
 
 import (
+	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/kilnfi/cardano-validator-watcher/internal/schedule"
 	"github.com/kilnfi/cardano-validator-watcher/internal/watcher"
 )
 
+// MaintenanceWindowFinder finds the windows without leader slot of the instances hosting the pools.
+type MaintenanceWindowFinder interface {
+	Find(ctx context.Context, count int, margin time.Duration) ([]schedule.InstanceWindows, error)
+}
+
 // Handler represents the HTTP handlers for the server
 type Handler struct {
-	logger      *slog.Logger
-	healthStore *watcher.HealthStore
+	logger       *slog.Logger
+	healthStore  *watcher.HealthStore
+	windowFinder MaintenanceWindowFinder
 }
 
 // NewHandler returns a new Handler
-func NewHandler(logger *slog.Logger, healthStore *watcher.HealthStore) *Handler {
+func NewHandler(logger *slog.Logger, healthStore *watcher.HealthStore, windowFinder MaintenanceWindowFinder) *Handler {
 	return &Handler{
-		logger:      logger,
-		healthStore: healthStore,
+		logger:       logger,
+		healthStore:  healthStore,
+		windowFinder: windowFinder,
 	}
 }
 
@@ -40,12 +52,50 @@ func (h *Handler) LiveProbe(w http.ResponseWriter, _ *http.Request) {
 
 // Ready checks the readiness of the service by checking the health of our services
 // If the service is ready, it returns a 200 OK status
-// If the service is not ready, it returns a 500 Internal Server Error status
+// If the service is not ready, it returns a 500 Internal Server Error status with the health state
 func (h *Handler) ReadyProbe(w http.ResponseWriter, _ *http.Request) {
-	if !h.healthStore.GetHealth() {
-		http.Error(w, "Health KO", http.StatusInternalServerError)
+	if state := h.healthStore.GetState(); state != watcher.HealthStateHealthy {
+		http.Error(w, "Health KO: "+string(state), http.StatusInternalServerError)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("Health OK"))
 }
+
+// MaintenanceWindows returns the largest windows without leader slot of each instance
+// in the current and the next epoch.
+// The number of windows and the margin around the leader slots are set with the
+// count and margin query parameters.
+func (h *Handler) MaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	count := schedule.DefaultWindowCount
+	if value := r.URL.Query().Get("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "invalid count: "+value, http.StatusBadRequest)
+			return
+		}
+		count = parsed
+	}
+
+	margin := schedule.DefaultWindowMargin
+	if value := r.URL.Query().Get("margin"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "invalid margin: "+value, http.StatusBadRequest)
+			return
+		}
+		margin = parsed
+	}
+
+	windows, err := h.windowFinder.Find(r.Context(), count, margin)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "unable to find maintenance windows", slog.String("error", err.Error()))
+		http.Error(w, "unable to find maintenance windows", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(windows); err != nil {
+		h.logger.ErrorContext(r.Context(), "unable to encode maintenance windows", slog.String("error", err.Error()))
+	}
+}
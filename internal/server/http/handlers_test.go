@@ -2,11 +2,14 @@ package http
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
+	"github.com/kilnfi/cardano-validator-watcher/internal/schedule"
 	"github.com/kilnfi/cardano-validator-watcher/internal/watcher"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
@@ -108,6 +111,23 @@ func TestReadyProbe(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
+
+	t.Run("SadPath_ReadyProbeReportsUnauthorizedState", func(t *testing.T) {
+		r := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/readyz", nil)
+		w := httptest.NewRecorder()
+
+		healthStore := watcher.NewHealthStore()
+		healthStore.SetState(watcher.HealthStateUnauthorized)
+		server, err := New(
+			nil,
+			healthStore,
+		)
+		require.NoError(t, err)
+		server.router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "unauthorized")
+	})
 }
 
 func TestMetricsHandler(t *testing.T) {
@@ -134,3 +154,108 @@ func TestMetricsHandler(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 }
+
+type fakeWindowFinder struct {
+	count   int
+	margin  time.Duration
+	windows []schedule.InstanceWindows
+	err     error
+}
+
+func (f *fakeWindowFinder) Find(_ context.Context, count int, margin time.Duration) ([]schedule.InstanceWindows, error) {
+	f.count = count
+	f.margin = margin
+	return f.windows, f.err
+}
+
+func TestMaintenanceWindowsHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_WindowsAreReturned", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/maintenance-windows?count=1&margin=15m", nil)
+		w := httptest.NewRecorder()
+
+		start := time.Date(2023, 10, 14, 12, 0, 0, 0, time.UTC)
+		finder := &fakeWindowFinder{
+			windows: []schedule.InstanceWindows{
+				{
+					Instance: "producer-a",
+					Pools:    []string{"pool-0"},
+					Until:    start.Add(48 * time.Hour),
+					Windows:  []schedule.Window{{Start: start, End: start.Add(time.Hour), DurationSeconds: 3600}},
+				},
+			},
+		}
+		server, err := New(nil, watcher.NewHealthStore(), WithMaintenanceWindowFinder(finder))
+		require.NoError(t, err)
+		server.router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, finder.count)
+		assert.Equal(t, 15*time.Minute, finder.margin)
+		assert.JSONEq(t, `[{
+			"instance": "producer-a",
+			"pools": ["pool-0"],
+			"until": "2023-10-16T12:00:00Z",
+			"windows": [{"start": "2023-10-14T12:00:00Z", "end": "2023-10-14T13:00:00Z", "duration_seconds": 3600}]
+		}]`, w.Body.String())
+	})
+
+	t.Run("GoodPath_DefaultParameters", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/maintenance-windows", nil)
+		w := httptest.NewRecorder()
+
+		finder := &fakeWindowFinder{windows: []schedule.InstanceWindows{}}
+		server, err := New(nil, watcher.NewHealthStore(), WithMaintenanceWindowFinder(finder))
+		require.NoError(t, err)
+		server.router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, schedule.DefaultWindowCount, finder.count)
+		assert.Equal(t, schedule.DefaultWindowMargin, finder.margin)
+	})
+
+	t.Run("SadPath_InvalidMargin", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/maintenance-windows?margin=soon", nil)
+		w := httptest.NewRecorder()
+
+		server, err := New(nil, watcher.NewHealthStore(), WithMaintenanceWindowFinder(&fakeWindowFinder{}))
+		require.NoError(t, err)
+		server.router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("SadPath_UnableToFindWindows", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/maintenance-windows", nil)
+		w := httptest.NewRecorder()
+
+		finder := &fakeWindowFinder{err: errors.New("no slot leader schedule found")}
+		server, err := New(nil, watcher.NewHealthStore(), WithMaintenanceWindowFinder(finder))
+		require.NoError(t, err)
+		server.router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("SadPath_EndpointDisabledWithoutFinder", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/maintenance-windows", nil)
+		w := httptest.NewRecorder()
+
+		server, err := New(nil, watcher.NewHealthStore())
+		require.NoError(t, err)
+		server.router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -7,6 +7,7 @@ type options struct {
 	port         int
 	readTimeout  time.Duration
 	writeTimeout time.Duration
+	windowFinder MaintenanceWindowFinder
 }
 
 type ServerOptionsFunc func(*options) error
@@ -38,3 +39,12 @@ func WithWriteTimeout(timeout time.Duration) ServerOptionsFunc {
 		return nil
 	}
 }
+
+// WithMaintenanceWindowFinder exposes the maintenance windows of the instances
+// on /maintenance-windows.
+func WithMaintenanceWindowFinder(finder MaintenanceWindowFinder) ServerOptionsFunc {
+	return func(o *options) error {
+		o.windowFinder = finder
+		return nil
+	}
+}
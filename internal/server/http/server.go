@@ -91,10 +91,13 @@ func (s *Server) Stop(ctx context.Context) error {
 }
 
 func (s *Server) registerRoutes() {
-	handler := NewHandler(s.logger, s.healthStore)
+	handler := NewHandler(s.logger, s.healthStore, s.options.windowFinder)
 
 	s.router.HandleFunc("GET /", handler.Default)
 	s.router.HandleFunc("GET /livez", handler.LiveProbe)
 	s.router.HandleFunc("GET /readyz", handler.ReadyProbe)
 	s.router.Handle("GET /metrics", promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{}))
+	if s.options.windowFinder != nil {
+		s.router.HandleFunc("GET /maintenance-windows", handler.MaintenanceWindows)
+	}
 }
@@ -0,0 +1,70 @@
+package slotleader
+
+import (
+	"slices"
+	"sync"
+)
+
+type scheduleKey struct {
+	poolID string
+	epoch  int
+}
+
+// scheduleCache keeps the schedules of the pools in memory, keyed by pool and
+// epoch, so the block watcher does not hit the database on every slot.
+type scheduleCache struct {
+	mu        sync.RWMutex
+	schedules map[scheduleKey]Schedule
+}
+
+func newScheduleCache() *scheduleCache {
+	return &scheduleCache{
+		schedules: make(map[scheduleKey]Schedule),
+	}
+}
+
+func (c *scheduleCache) get(poolID string, epoch int) (Schedule, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	schedule, ok := c.schedules[scheduleKey{poolID: poolID, epoch: epoch}]
+	return schedule, ok
+}
+
+func (c *scheduleCache) set(schedule Schedule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.schedules[scheduleKey{poolID: schedule.PoolID, epoch: schedule.Epoch}] = schedule
+}
+
+// invalidate drops the schedule of a pool in an epoch.
+func (c *scheduleCache) invalidate(poolID string, epoch int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.schedules, scheduleKey{poolID: poolID, epoch: epoch})
+}
+
+// invalidateBefore drops the schedules of the epochs before epoch.
+func (c *scheduleCache) invalidateBefore(epoch int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.schedules {
+		if key.epoch < epoch {
+			delete(c.schedules, key)
+		}
+	}
+}
+
+// slotsInRange returns the slots of a schedule between startSlot and endSlot
+// included. The slots of a schedule are sorted.
+func slotsInRange(slots []int, startSlot int, endSlot int) []int {
+	start, _ := slices.BinarySearch(slots, startSlot)
+	end, _ := slices.BinarySearch(slots, endSlot+1)
+	if start >= end {
+		return []int{}
+	}
+	return append([]int{}, slots[start:end]...)
+}
@@ -0,0 +1,278 @@
+package slotleader
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
+)
+
+// EncryptionKeySize is the size of the key encrypting the schedules (AES-256).
+const EncryptionKeySize = 32
+
+// ErrEncryptionKeyRequired is returned when the database holds encrypted
+// schedules but no encryption key was configured.
+var ErrEncryptionKeyRequired = errors.New("the database holds encrypted schedules, an encryption key is required")
+
+// ScheduleCipher encrypts the leader slots of the schedules stored in the
+// database with AES-256-GCM. Each schedule is bound to its pool and epoch so
+// that an encrypted schedule cannot be swapped with another one.
+type ScheduleCipher struct {
+	aead cipher.AEAD
+	// hashKey keys the hash of the encrypted schedules, it is derived from the encryption key.
+	hashKey []byte
+}
+
+// encryptedSchedule is a schedule row holding encrypted leader slots.
+type encryptedSchedule struct {
+	ID             int    `db:"id"`
+	Epoch          int    `db:"epoch"`
+	PoolID         string `db:"pool_id"`
+	Hash           string `db:"hash"`
+	EncryptedSlots []byte `db:"encrypted_slots"`
+}
+
+// ParseEncryptionKey decodes a base64 encoded encryption key.
+func ParseEncryptionKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode encryption key: %w", err)
+	}
+	if len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("invalid encryption key size %d, expected %d bytes", len(key), EncryptionKeySize)
+	}
+	return key, nil
+}
+
+// NewScheduleCipher creates a cipher from a 32 bytes key.
+func NewScheduleCipher(key []byte) (*ScheduleCipher, error) {
+	if len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("invalid encryption key size %d, expected %d bytes", len(key), EncryptionKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cipher: %w", err)
+	}
+	hashKey, err := hkdf.Key(sha256.New, key, nil, "cardano-validator-watcher slots hash", sha256.Size)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive hash key: %w", err)
+	}
+	return &ScheduleCipher{aead: aead, hashKey: hashKey}, nil
+}
+
+// Hash returns the HMAC-SHA256 of the leader slots of a pool in an epoch.
+// Unlike a plain digest, it cannot be brute-forced from the few slots of a
+// schedule without the key.
+func (c *ScheduleCipher) Hash(poolID string, epoch int, slotsJSON []byte) string {
+	mac := hmac.New(sha256.New, c.hashKey)
+	mac.Write(scheduleAAD(poolID, epoch))
+	mac.Write(slotsJSON)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Encrypt encrypts the leader slots of a pool in an epoch.
+func (c *ScheduleCipher) Encrypt(poolID string, epoch int, slots []cardano.SlotSchedule) ([]byte, error) {
+	plaintext, err := json.Marshal(slots)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal slots: %w", err)
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("unable to generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, scheduleAAD(poolID, epoch)), nil
+}
+
+// Decrypt decrypts the leader slots of a pool in an epoch.
+func (c *ScheduleCipher) Decrypt(poolID string, epoch int, ciphertext []byte) ([]cardano.SlotSchedule, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("encrypted slots of pool %s in epoch %d are truncated", poolID, epoch)
+	}
+
+	plaintext, err := c.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], scheduleAAD(poolID, epoch))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt slots of pool %s in epoch %d: %w", poolID, epoch, err)
+	}
+
+	slots := []cardano.SlotSchedule{}
+	if err := json.Unmarshal(plaintext, &slots); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal slots of pool %s in epoch %d: %w", poolID, epoch, err)
+	}
+	return slots, nil
+}
+
+func scheduleAAD(poolID string, epoch int) []byte {
+	return fmt.Appendf(nil, "%s:%d", poolID, epoch)
+}
+
+// PrepareStorage aligns the stored schedules with the encryption settings of
+// the service. Without a cipher, it fails if encrypted schedules are stored.
+// With a cipher, the schedules stored in plaintext are encrypted and their
+// leader slots are removed from the database, and the hash of each encrypted
+// schedule is replaced by its keyed hash.
+func (s *Service) PrepareStorage(ctx context.Context) error {
+	if s.cipher == nil {
+		var encrypted int
+		if err := s.db.GetContext(ctx, &encrypted, `SELECT COUNT(*) FROM slots WHERE encrypted_slots IS NOT NULL`); err != nil {
+			return fmt.Errorf("unable to count encrypted schedules: %w", err)
+		}
+		if encrypted > 0 {
+			return ErrEncryptionKeyRequired
+		}
+		return nil
+	}
+
+	plaintext := []Schedule{}
+	if err := s.db.SelectContext(ctx, &plaintext, `SELECT id, epoch, pool_id, slot_qty, hash FROM slots WHERE encrypted_slots IS NULL`); err != nil {
+		return fmt.Errorf("unable to list plaintext schedules: %w", err)
+	}
+
+	for _, schedule := range plaintext {
+		if err := s.encryptStoredSchedule(ctx, schedule); err != nil {
+			return err
+		}
+	}
+
+	rehashed, err := s.rehashEncryptedSchedules(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(plaintext) > 0 || rehashed > 0 {
+		// The deleted slots and the replaced hashes stay in the free pages of the database until it is rebuilt
+		if _, err := s.db.ExecContext(ctx, `VACUUM`); err != nil {
+			return fmt.Errorf("unable to vacuum the database: %w", err)
+		}
+		// In WAL mode, the previous pages stay in the write-ahead log until it is checkpointed and truncated
+		var busy, logFrames, checkpointedFrames int
+		if err := s.db.QueryRowxContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`).Scan(&busy, &logFrames, &checkpointedFrames); err != nil {
+			return fmt.Errorf("unable to checkpoint the write-ahead log: %w", err)
+		}
+		if busy != 0 {
+			return errors.New("unable to checkpoint the write-ahead log: the database is busy")
+		}
+		s.logger.InfoContext(ctx, "🔒 stored schedules encrypted",
+			slog.Int("schedules", len(plaintext)),
+			slog.Int("rehashed", rehashed),
+		)
+	}
+	return nil
+}
+
+func (s *Service) encryptStoredSchedule(ctx context.Context, schedule Schedule) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	leaderSlots := []leaderSlot{}
+	err = tx.SelectContext(ctx, &leaderSlots,
+		`SELECT slot, slot_in_epoch, at FROM leader_slots WHERE pool_id = ? AND epoch = ? ORDER BY slot`,
+		schedule.PoolID, schedule.Epoch,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to get slots for pool %s in epoch %d: %w", schedule.PoolID, schedule.Epoch, err)
+	}
+
+	slots := toSlotSchedules(leaderSlots)
+	encrypted, err := s.cipher.Encrypt(schedule.PoolID, schedule.Epoch, slots)
+	if err != nil {
+		return err
+	}
+	hash, err := s.slotsHash(schedule.PoolID, schedule.Epoch, slots)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE slots SET encrypted_slots = ?, hash = ? WHERE id = ?`, encrypted, hash, schedule.ID)
+	if err != nil {
+		return fmt.Errorf("unable to encrypt slots for pool %s in epoch %d: %w", schedule.PoolID, schedule.Epoch, err)
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM leader_slots WHERE pool_id = ? AND epoch = ?`, schedule.PoolID, schedule.Epoch)
+	if err != nil {
+		return fmt.Errorf("unable to delete plaintext slots for pool %s in epoch %d: %w", schedule.PoolID, schedule.Epoch, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit encrypted slots for pool %s in epoch %d: %w", schedule.PoolID, schedule.Epoch, err)
+	}
+	return nil
+}
+
+// rehashEncryptedSchedules replaces the hash of the encrypted schedules that is
+// not their keyed hash, e.g. the plaintext digest of the schedules encrypted
+// by a previous version of the watcher. It returns the number of replaced hashes.
+func (s *Service) rehashEncryptedSchedules(ctx context.Context) (int, error) {
+	encrypted := []encryptedSchedule{}
+	if err := s.db.SelectContext(ctx, &encrypted, `SELECT id, epoch, pool_id, hash, encrypted_slots FROM slots WHERE encrypted_slots IS NOT NULL`); err != nil {
+		return 0, fmt.Errorf("unable to list encrypted schedules: %w", err)
+	}
+
+	rehashed := 0
+	for _, schedule := range encrypted {
+		slots, err := s.cipher.Decrypt(schedule.PoolID, schedule.Epoch, schedule.EncryptedSlots)
+		if err != nil {
+			return rehashed, err
+		}
+		hash, err := s.slotsHash(schedule.PoolID, schedule.Epoch, slots)
+		if err != nil {
+			return rehashed, err
+		}
+		if hash == schedule.Hash {
+			continue
+		}
+
+		if _, err := s.db.ExecContext(ctx, `UPDATE slots SET hash = ? WHERE id = ?`, hash, schedule.ID); err != nil {
+			return rehashed, fmt.Errorf("unable to replace the hash of the slots for pool %s in epoch %d: %w", schedule.PoolID, schedule.Epoch, err)
+		}
+		rehashed++
+	}
+	return rehashed, nil
+}
+
+// loadEncryptedSlots returns the decrypted leader slots of a pool in an epoch.
+func (s *Service) loadEncryptedSlots(ctx context.Context, PoolID string, epoch int) ([]cardano.SlotSchedule, error) {
+	var encrypted []byte
+	err := s.db.GetContext(ctx, &encrypted, `SELECT encrypted_slots FROM slots WHERE pool_id = ? AND epoch = ?`, PoolID, epoch)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w for pool %s in epoch %d", ErrScheduleNotFound, PoolID, epoch)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get encrypted slots for pool %s: %w", PoolID, err)
+	}
+	return s.cipher.Decrypt(PoolID, epoch, encrypted)
+}
+
+// toSlotSchedules converts rows of the leader_slots table to the slots of a leader log.
+func toSlotSchedules(leaderSlots []leaderSlot) []cardano.SlotSchedule {
+	slots := make([]cardano.SlotSchedule, len(leaderSlots))
+	for i, slot := range leaderSlots {
+		slots[i] = cardano.SlotSchedule{
+			No:          i + 1,
+			Slot:        slot.Slot,
+			SlotInEpoch: slot.SlotInEpoch,
+			At:          slot.At.Time,
+		}
+	}
+	return slots
+}
@@ -0,0 +1,348 @@
+package slotleader
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	encryptedSlotsQuery     = "SELECT encrypted_slots FROM slots WHERE pool_id = ? AND epoch = ?"
+	encryptedSchedulesQuery = "SELECT id, epoch, pool_id, hash, encrypted_slots FROM slots WHERE encrypted_slots IS NOT NULL"
+)
+
+func setupCipher(t *testing.T) *ScheduleCipher {
+	t.Helper()
+
+	cipher, err := NewScheduleCipher(bytes.Repeat([]byte{0x42}, EncryptionKeySize))
+	require.NoError(t, err)
+	return cipher
+}
+
+func TestScheduleCipher(t *testing.T) {
+	t.Parallel()
+
+	slots := []cardano.SlotSchedule{
+		{No: 1, Slot: 1000, SlotInEpoch: 10, At: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{No: 2, Slot: 2000, SlotInEpoch: 1010, At: time.Date(2024, 1, 1, 0, 16, 40, 0, time.UTC)},
+	}
+
+	t.Run("GoodPath_RoundTrip", func(t *testing.T) {
+		t.Parallel()
+
+		cipher := setupCipher(t)
+		encrypted, err := cipher.Encrypt("pool-0", 100, slots)
+		require.NoError(t, err)
+		require.NotContains(t, string(encrypted), "1000")
+
+		decrypted, err := cipher.Decrypt("pool-0", 100, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, slots, decrypted)
+	})
+
+	t.Run("SadPath_ScheduleOfAnotherPool", func(t *testing.T) {
+		t.Parallel()
+
+		cipher := setupCipher(t)
+		encrypted, err := cipher.Encrypt("pool-0", 100, slots)
+		require.NoError(t, err)
+
+		_, err = cipher.Decrypt("pool-1", 100, encrypted)
+		require.Error(t, err)
+		_, err = cipher.Decrypt("pool-0", 101, encrypted)
+		require.Error(t, err)
+	})
+
+	t.Run("SadPath_WrongKey", func(t *testing.T) {
+		t.Parallel()
+
+		encrypted, err := setupCipher(t).Encrypt("pool-0", 100, slots)
+		require.NoError(t, err)
+
+		other, err := NewScheduleCipher(bytes.Repeat([]byte{0x24}, EncryptionKeySize))
+		require.NoError(t, err)
+		_, err = other.Decrypt("pool-0", 100, encrypted)
+		require.Error(t, err)
+	})
+
+	t.Run("SadPath_Truncated", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := setupCipher(t).Decrypt("pool-0", 100, []byte{0x01})
+		require.Error(t, err)
+	})
+
+	t.Run("GoodPath_KeyedHash", func(t *testing.T) {
+		t.Parallel()
+
+		cipher := setupCipher(t)
+		other, err := NewScheduleCipher(bytes.Repeat([]byte{0x24}, EncryptionKeySize))
+		require.NoError(t, err)
+
+		hash := cipher.Hash("pool-0", 100, []byte("[1000]"))
+		require.Equal(t, hash, cipher.Hash("pool-0", 100, []byte("[1000]")))
+		require.NotEqual(t, slotsHash("[1000]"), hash)
+		require.NotEqual(t, hash, other.Hash("pool-0", 100, []byte("[1000]")))
+		require.NotEqual(t, hash, cipher.Hash("pool-1", 100, []byte("[1000]")))
+		require.NotEqual(t, hash, cipher.Hash("pool-0", 101, []byte("[1000]")))
+	})
+}
+
+func TestParseEncryptionKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_ParseEncryptionKey", func(t *testing.T) {
+		t.Parallel()
+
+		key := bytes.Repeat([]byte{0x42}, EncryptionKeySize)
+		parsed, err := ParseEncryptionKey(base64.StdEncoding.EncodeToString(key) + "\n")
+		require.NoError(t, err)
+		require.Equal(t, key, parsed)
+	})
+
+	t.Run("SadPath_InvalidBase64", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseEncryptionKey("not base64!")
+		require.Error(t, err)
+	})
+
+	t.Run("SadPath_InvalidSize", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseEncryptionKey(base64.StdEncoding.EncodeToString([]byte("too short")))
+		require.ErrorContains(t, err, "invalid encryption key size")
+	})
+}
+
+func TestPrepareStorage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_NoEncryptionKey", func(t *testing.T) {
+		t.Parallel()
+
+		db := setupDB(t)
+		slotLeaderService := NewSlotLeaderService(db.db, nil, nil, nil, setupPools(t), setupRegistry(t).metrics, 0)
+
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE encrypted_slots IS NOT NULL").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		require.NoError(t, slotLeaderService.PrepareStorage(context.Background()))
+		require.NoError(t, db.mock.ExpectationsWereMet())
+	})
+
+	t.Run("SadPath_EncryptedSchedulesWithoutKey", func(t *testing.T) {
+		t.Parallel()
+
+		db := setupDB(t)
+		slotLeaderService := NewSlotLeaderService(db.db, nil, nil, nil, setupPools(t), setupRegistry(t).metrics, 0)
+
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE encrypted_slots IS NOT NULL").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		err := slotLeaderService.PrepareStorage(context.Background())
+		require.ErrorIs(t, err, ErrEncryptionKeyRequired)
+	})
+
+	t.Run("GoodPath_EncryptPlaintextSchedules", func(t *testing.T) {
+		t.Parallel()
+
+		db := setupDB(t)
+		cipher := setupCipher(t)
+		slotLeaderService := NewSlotLeaderService(db.db, nil, nil, nil, setupPools(t), setupRegistry(t).metrics, 0, WithScheduleCipher(cipher))
+		at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		db.mock.ExpectQuery("SELECT id, epoch, pool_id, slot_qty, hash FROM slots WHERE encrypted_slots IS NULL").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "hash"}).
+					AddRow(1, 100, "pool-0", 1, "hash"),
+			)
+		db.mock.ExpectBegin()
+		db.mock.ExpectQuery(leaderLogSlotsQuery).
+			WithArgs("pool-0", 100).
+			WillReturnRows(sqlmock.NewRows([]string{"slot", "slot_in_epoch", "at"}).AddRow(1000, 10, at))
+		// the plaintext digest of the slots is replaced by their keyed hash
+		keyedHash := cipher.Hash("pool-0", 100, []byte("[1000]"))
+		require.NotEqual(t, slotsHash("[1000]"), keyedHash)
+		db.mock.ExpectExec("UPDATE slots SET encrypted_slots = ?, hash = ? WHERE id = ?").
+			WithArgs(sqlmock.AnyArg(), keyedHash, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectExec("DELETE FROM leader_slots WHERE pool_id = ? AND epoch = ?").
+			WithArgs("pool-0", 100).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectCommit()
+
+		encrypted, err := cipher.Encrypt("pool-0", 100, []cardano.SlotSchedule{{No: 1, Slot: 1000, SlotInEpoch: 10, At: at}})
+		require.NoError(t, err)
+		db.mock.ExpectQuery(encryptedSchedulesQuery).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "hash", "encrypted_slots"}).
+					AddRow(1, 100, "pool-0", keyedHash, encrypted),
+			)
+		db.mock.ExpectExec("VACUUM").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		db.mock.ExpectQuery("PRAGMA wal_checkpoint(TRUNCATE)").
+			WillReturnRows(sqlmock.NewRows([]string{"busy", "log", "checkpointed"}).AddRow(0, 0, 0))
+
+		require.NoError(t, slotLeaderService.PrepareStorage(context.Background()))
+		require.NoError(t, db.mock.ExpectationsWereMet())
+	})
+
+	t.Run("GoodPath_RehashEncryptedSchedules", func(t *testing.T) {
+		t.Parallel()
+
+		db := setupDB(t)
+		cipher := setupCipher(t)
+		slotLeaderService := NewSlotLeaderService(db.db, nil, nil, nil, setupPools(t), setupRegistry(t).metrics, 0, WithScheduleCipher(cipher))
+
+		slots := []cardano.SlotSchedule{{No: 1, Slot: 1000}, {No: 2, Slot: 2000}}
+		encrypted, err := cipher.Encrypt("pool-0", 100, slots)
+		require.NoError(t, err)
+		keyedHash := cipher.Hash("pool-0", 100, []byte("[1000,2000]"))
+		otherEncrypted, err := cipher.Encrypt("pool-0", 101, slots)
+		require.NoError(t, err)
+
+		db.mock.ExpectQuery("SELECT id, epoch, pool_id, slot_qty, hash FROM slots WHERE encrypted_slots IS NULL").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "hash"}))
+		// the first schedule was encrypted with the plaintext digest of its slots
+		db.mock.ExpectQuery(encryptedSchedulesQuery).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "hash", "encrypted_slots"}).
+					AddRow(1, 100, "pool-0", slotsHash("[1000,2000]"), encrypted).
+					AddRow(2, 101, "pool-0", cipher.Hash("pool-0", 101, []byte("[1000,2000]")), otherEncrypted),
+			)
+		db.mock.ExpectExec("UPDATE slots SET hash = ? WHERE id = ?").
+			WithArgs(keyedHash, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectExec("VACUUM").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		db.mock.ExpectQuery("PRAGMA wal_checkpoint(TRUNCATE)").
+			WillReturnRows(sqlmock.NewRows([]string{"busy", "log", "checkpointed"}).AddRow(0, 0, 0))
+
+		require.NoError(t, slotLeaderService.PrepareStorage(context.Background()))
+		require.NoError(t, db.mock.ExpectationsWereMet())
+	})
+
+	t.Run("SadPath_WriteAheadLogCheckpointBusy", func(t *testing.T) {
+		t.Parallel()
+
+		db := setupDB(t)
+		cipher := setupCipher(t)
+		slotLeaderService := NewSlotLeaderService(db.db, nil, nil, nil, setupPools(t), setupRegistry(t).metrics, 0, WithScheduleCipher(cipher))
+
+		slots := []cardano.SlotSchedule{{No: 1, Slot: 1000}, {No: 2, Slot: 2000}}
+		encrypted, err := cipher.Encrypt("pool-0", 100, slots)
+		require.NoError(t, err)
+
+		db.mock.ExpectQuery("SELECT id, epoch, pool_id, slot_qty, hash FROM slots WHERE encrypted_slots IS NULL").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "hash"}))
+		db.mock.ExpectQuery(encryptedSchedulesQuery).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "hash", "encrypted_slots"}).
+					AddRow(1, 100, "pool-0", slotsHash("[1000,2000]"), encrypted),
+			)
+		db.mock.ExpectExec("UPDATE slots SET hash = ? WHERE id = ?").
+			WithArgs(cipher.Hash("pool-0", 100, []byte("[1000,2000]")), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectExec("VACUUM").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		// a reader kept the write-ahead log from being truncated
+		db.mock.ExpectQuery("PRAGMA wal_checkpoint(TRUNCATE)").
+			WillReturnRows(sqlmock.NewRows([]string{"busy", "log", "checkpointed"}).AddRow(1, 10, 5))
+
+		err = slotLeaderService.PrepareStorage(context.Background())
+		require.ErrorContains(t, err, "the database is busy")
+		require.NoError(t, db.mock.ExpectationsWereMet())
+	})
+
+	t.Run("GoodPath_NothingToPrepare", func(t *testing.T) {
+		t.Parallel()
+
+		db := setupDB(t)
+		cipher := setupCipher(t)
+		slotLeaderService := NewSlotLeaderService(db.db, nil, nil, nil, setupPools(t), setupRegistry(t).metrics, 0, WithScheduleCipher(cipher))
+
+		encrypted, err := cipher.Encrypt("pool-0", 100, []cardano.SlotSchedule{{No: 1, Slot: 1000}})
+		require.NoError(t, err)
+
+		db.mock.ExpectQuery("SELECT id, epoch, pool_id, slot_qty, hash FROM slots WHERE encrypted_slots IS NULL").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "hash"}))
+		db.mock.ExpectQuery(encryptedSchedulesQuery).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "hash", "encrypted_slots"}).
+					AddRow(1, 100, "pool-0", cipher.Hash("pool-0", 100, []byte("[1000]")), encrypted),
+			)
+
+		// the database is not vacuumed when no row was rewritten
+		require.NoError(t, slotLeaderService.PrepareStorage(context.Background()))
+		require.NoError(t, db.mock.ExpectationsWereMet())
+	})
+}
+
+func TestEncryptedSchedule(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_GetLeaderSlotsInRange", func(t *testing.T) {
+		t.Parallel()
+
+		db := setupDB(t)
+		pools := setupPools(t)
+		cipher := setupCipher(t)
+		epoch := 100
+		slotLeaderService := NewSlotLeaderService(db.db, nil, nil, nil, pools, setupRegistry(t).metrics, 0, WithScheduleCipher(cipher))
+
+		encrypted, err := cipher.Encrypt(pools[0].ID, epoch, []cardano.SlotSchedule{
+			{No: 1, Slot: 2000}, {No: 2, Slot: 1000}, {No: 3, Slot: 1500},
+		})
+		require.NoError(t, err)
+
+		db.mock.ExpectQuery(scheduleQuery).
+			WithArgs(pools[0].ID, epoch).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "hash"}).
+					AddRow(1, epoch, pools[0].ID, 3, "hash"),
+			)
+		db.mock.ExpectQuery(encryptedSlotsQuery).
+			WithArgs(pools[0].ID, epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"encrypted_slots"}).AddRow(encrypted))
+
+		slots, err := slotLeaderService.GetLeaderSlotsInRange(context.Background(), pools[0].ID, epoch, 1000, 1999)
+		require.NoError(t, err)
+		require.Equal(t, []int{1000, 1500}, slots)
+		require.NoError(t, db.mock.ExpectationsWereMet())
+	})
+
+	t.Run("GoodPath_PersistEncryptedSlots", func(t *testing.T) {
+		t.Parallel()
+
+		db := setupDB(t)
+		pools := setupPools(t)
+		epoch := 100
+		cipher := setupCipher(t)
+		slotLeaderService := NewSlotLeaderService(db.db, nil, nil, nil, pools, setupRegistry(t).metrics, 0, WithScheduleCipher(cipher))
+
+		// no plaintext digest of the slots is stored next to the encrypted slots
+		keyedHash := cipher.Hash(pools[0].ID, epoch, []byte("[1000,2000]"))
+		require.NotEqual(t, slotsHash("[1000,2000]"), keyedHash)
+
+		db.mock.ExpectBegin()
+		db.mock.ExpectExec("INSERT INTO slots (epoch, pool_id, slot_qty, hash, encrypted_slots) VALUES (?, ?, ?, ?, ?)").
+			WithArgs(epoch, pools[0].ID, 2, keyedHash, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectExec("INSERT INTO leader_logs (epoch, pool_id, epoch_nonce, consensus, epoch_slots, epoch_slots_ideal, max_performance, sigma, active_stake, total_active_stake) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectCommit()
+
+		err := slotLeaderService.persistSlots(context.Background(), pools[0].ID, epoch, cardano.ClientLeaderLogsResponse{
+			AssignedSlots: []cardano.SlotSchedule{{No: 1, Slot: 1000}, {No: 2, Slot: 2000}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.mock.ExpectationsWereMet())
+	})
+}
@@ -1,9 +1,17 @@
 package slotleader
 
 import (
+	"errors"
 	"fmt"
 )
 
+// ErrScheduleNotFound is returned when no schedule was stored for a pool in an epoch.
+var ErrScheduleNotFound = errors.New("no slot leader schedule found")
+
+// ErrVRFKeyMismatch is returned when the VRF signing key of a pool does not
+// match the vrf_key registered on-chain for the pool.
+var ErrVRFKeyMismatch = errors.New("vrf signing key does not match the vrf_key registered on-chain")
+
 type ErrSlotLeaderRefresh struct {
 	PoolID  string
 	Epoch   int
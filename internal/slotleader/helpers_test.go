@@ -1,6 +1,7 @@
 package slotleader
 
 import (
+	"encoding/hex"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -10,8 +11,51 @@ import (
 	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
 	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/blake2b"
 )
 
+const (
+	scheduleQuery       = "SELECT id, epoch, pool_id, slot_qty, hash FROM slots WHERE pool_id = ? AND epoch = ?"
+	scheduleSlotsQuery  = "SELECT slot FROM leader_slots WHERE pool_id = ? AND epoch = ? ORDER BY slot"
+	leaderLogQuery      = "SELECT id, epoch, pool_id, epoch_nonce, consensus, epoch_slots, epoch_slots_ideal, max_performance, sigma, active_stake, total_active_stake FROM leader_logs WHERE pool_id = ? AND epoch = ?"
+	leaderLogSlotsQuery = "SELECT slot, slot_in_epoch, at FROM leader_slots WHERE pool_id = ? AND epoch = ? ORDER BY slot"
+)
+
+var leaderLogColumns = []string{
+	"id", "epoch", "pool_id", "epoch_nonce", "consensus", "epoch_slots", "epoch_slots_ideal",
+	"max_performance", "sigma", "active_stake", "total_active_stake",
+}
+
+// expectSchedule expects the schedule of a pool to be loaded with the given slots.
+func expectSchedule(mock sqlmock.Sqlmock, poolID string, epoch int, slots ...int) {
+	mock.ExpectQuery(scheduleQuery).
+		WithArgs(poolID, epoch).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "hash"}).
+				AddRow(1, epoch, poolID, len(slots), "hash"),
+		)
+
+	rows := sqlmock.NewRows([]string{"slot"})
+	for _, slot := range slots {
+		rows.AddRow(slot)
+	}
+	mock.ExpectQuery(scheduleSlotsQuery).
+		WithArgs(poolID, epoch).
+		WillReturnRows(rows)
+}
+
+// expectRefreshStatus expects the outcome of the refresh of a pool to be persisted.
+func expectRefreshStatus(mock sqlmock.Sqlmock, poolID string, epoch int, status RefreshStatus) {
+	mock.ExpectExec("INSERT OR REPLACE INTO slot_schedule_refresh (pool_id, epoch, status, attempts, last_error, updated_at) VALUES (?, ?, ?, ?, ?, ?)").
+		WithArgs(poolID, epoch, status, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func slotsHash(slots string) string {
+	hash := blake2b.Sum256([]byte(slots))
+	return hex.EncodeToString(hash[:])
+}
+
 type clients struct {
 	bf      *blockfrostmocks.MockClient
 	cardano *cardanomocks.MockCardanoClient
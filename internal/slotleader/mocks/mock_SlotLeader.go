@@ -25,6 +25,68 @@ func (_m *MockSlotLeader) EXPECT() *MockSlotLeader_Expecter {
 	return &MockSlotLeader_Expecter{mock: &_m.Mock}
 }
 
+// GetLeaderSlotsInRange provides a mock function with given fields: ctx, PoolID, epoch, startSlot, endSlot
+func (_m *MockSlotLeader) GetLeaderSlotsInRange(ctx context.Context, PoolID string, epoch int, startSlot int, endSlot int) ([]int, error) {
+	ret := _m.Called(ctx, PoolID, epoch, startSlot, endSlot)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLeaderSlotsInRange")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, int) ([]int, error)); ok {
+		return rf(ctx, PoolID, epoch, startSlot, endSlot)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, int) []int); ok {
+		r0 = rf(ctx, PoolID, epoch, startSlot, endSlot)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int, int) error); ok {
+		r1 = rf(ctx, PoolID, epoch, startSlot, endSlot)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSlotLeader_GetLeaderSlotsInRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLeaderSlotsInRange'
+type MockSlotLeader_GetLeaderSlotsInRange_Call struct {
+	*mock.Call
+}
+
+// GetLeaderSlotsInRange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - PoolID string
+//   - epoch int
+//   - startSlot int
+//   - endSlot int
+func (_e *MockSlotLeader_Expecter) GetLeaderSlotsInRange(ctx interface{}, PoolID interface{}, epoch interface{}, startSlot interface{}, endSlot interface{}) *MockSlotLeader_GetLeaderSlotsInRange_Call {
+	return &MockSlotLeader_GetLeaderSlotsInRange_Call{Call: _e.mock.On("GetLeaderSlotsInRange", ctx, PoolID, epoch, startSlot, endSlot)}
+}
+
+func (_c *MockSlotLeader_GetLeaderSlotsInRange_Call) Run(run func(ctx context.Context, PoolID string, epoch int, startSlot int, endSlot int)) *MockSlotLeader_GetLeaderSlotsInRange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(int), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockSlotLeader_GetLeaderSlotsInRange_Call) Return(_a0 []int, _a1 error) *MockSlotLeader_GetLeaderSlotsInRange_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSlotLeader_GetLeaderSlotsInRange_Call) RunAndReturn(run func(context.Context, string, int, int, int) ([]int, error)) *MockSlotLeader_GetLeaderSlotsInRange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetNextSlotLeader provides a mock function with given fields: ctx, PoolID, height, epoch
 func (_m *MockSlotLeader) GetNextSlotLeader(ctx context.Context, PoolID string, height int, epoch int) (int, error) {
 	ret := _m.Called(ctx, PoolID, height, epoch)
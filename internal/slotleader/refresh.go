@@ -0,0 +1,177 @@
+package slotleader
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	bfAPI "github.com/blockfrost/blockfrost-go"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+)
+
+// RefreshStatus is the outcome of the last refresh of the schedule of a pool.
+type RefreshStatus string
+
+const (
+	RefreshStatusOK     RefreshStatus = "ok"
+	RefreshStatusFailed RefreshStatus = "failed"
+)
+
+// refreshRetry tracks a pool whose schedule of the current epoch failed to refresh.
+type refreshRetry struct {
+	pool        pools.Pool
+	epoch       int
+	attempts    int
+	nextAttempt time.Time
+}
+
+// refreshRetries holds the pools to refresh again, keyed by pool ID.
+type refreshRetries struct {
+	mu      sync.Mutex
+	retries map[string]refreshRetry
+}
+
+func newRefreshRetries() *refreshRetries {
+	return &refreshRetries{
+		retries: make(map[string]refreshRetry),
+	}
+}
+
+// attempts returns the number of failed refreshes of a pool in an epoch.
+func (r *refreshRetries) attempts(poolID string, epoch int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	retry, ok := r.retries[poolID]
+	if !ok || retry.epoch != epoch {
+		return 0
+	}
+	return retry.attempts
+}
+
+func (r *refreshRetries) set(retry refreshRetry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.retries[retry.pool.ID] = retry
+}
+
+func (r *refreshRetries) remove(poolID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.retries, poolID)
+}
+
+// due returns the pools to refresh again at the given time.
+func (r *refreshRetries) due(now time.Time) []refreshRetry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := []refreshRetry{}
+	for _, retry := range r.retries {
+		if !retry.nextAttempt.After(now) {
+			due = append(due, retry)
+		}
+	}
+	return due
+}
+
+// clearBefore drops the retries of the epochs before epoch.
+func (r *refreshRetries) clearBefore(epoch int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for poolID, retry := range r.retries {
+		if retry.epoch < epoch {
+			delete(r.retries, poolID)
+		}
+	}
+}
+
+// RunRefreshRetrier refreshes again the schedule of the pools that failed to
+// refresh in the current epoch, once their backoff elapsed.
+func (s *Service) RunRefreshRetrier(ctx context.Context) error {
+	ticker := time.NewTicker(s.retryMinBackoff)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// The pools are grouped by epoch since the epoch nonce is shared by the refresh
+			poolsByEpoch := make(map[int][]pools.Pool)
+			for _, retry := range s.retries.due(time.Now()) {
+				poolsByEpoch[retry.epoch] = append(poolsByEpoch[retry.epoch], retry.pool)
+			}
+
+			for epoch, retryPools := range poolsByEpoch {
+				if err := s.refresh(ctx, bfAPI.Epoch{Epoch: epoch}, "current", s.concurrency, retryPools); err != nil {
+					s.logger.ErrorContext(ctx, "🚨 refresh-retrier: unable to refresh slot leaders",
+						slog.Int("epoch", epoch),
+						slog.String("error", err.Error()),
+					)
+				}
+			}
+		}
+	}
+}
+
+// recordRefresh persists the outcome of the refresh of a pool for the current
+// epoch. The pools that failed are scheduled to be refreshed again.
+func (s *Service) recordRefresh(ctx context.Context, pool pools.Pool, epoch int, refreshErr error) {
+	status := RefreshStatusOK
+	lastError := ""
+	attempts := s.retries.attempts(pool.ID, epoch) + 1
+	if refreshErr != nil {
+		status = RefreshStatusFailed
+		lastError = refreshErr.Error()
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO slot_schedule_refresh (pool_id, epoch, status, attempts, last_error, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		pool.ID, epoch, status, attempts, lastError, time.Now(),
+	)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "unable to persist the refresh status of the slot leaders",
+			slog.String("pool_id", pool.ID),
+			slog.Int("epoch", epoch),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	gauge := s.metrics.SlotScheduleRefreshStatus.WithLabelValues(pool.Name, pool.ID, pool.Instance)
+	if refreshErr == nil {
+		gauge.Set(1)
+		s.retries.remove(pool.ID)
+		return
+	}
+	gauge.Set(0)
+
+	backoff := s.refreshBackoff(attempts)
+	s.retries.set(refreshRetry{pool: pool, epoch: epoch, attempts: attempts, nextAttempt: time.Now().Add(backoff)})
+	s.logger.ErrorContext(ctx,
+		fmt.Sprintf("🚨 unable to refresh slot leaders for pool %s, the pool is degraded", pool.Name),
+		slog.String("pool_id", pool.ID),
+		slog.Int("epoch", epoch),
+		slog.Int("attempts", attempts),
+		slog.String("retry_in", backoff.String()),
+		slog.String("error", refreshErr.Error()),
+	)
+}
+
+// refreshBackoff returns the delay before the next refresh of a pool that
+// failed the given number of times in a row.
+func (s *Service) refreshBackoff(attempts int) time.Duration {
+	backoff := s.retryMinBackoff
+	for range min(attempts-1, 32) {
+		backoff *= 2
+		if backoff >= s.retryMaxBackoff {
+			return s.retryMaxBackoff
+		}
+	}
+	return backoff
+}
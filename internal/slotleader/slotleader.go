@@ -3,62 +3,102 @@ package slotleader
 import (
 	"context"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
+	"sync"
 	"time"
 
 	bfAPI "github.com/blockfrost/blockfrost-go"
 	"github.com/jmoiron/sqlx"
 	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
 	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
 	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
 	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"golang.org/x/crypto/blake2b"
 	"golang.org/x/sync/errgroup"
 )
 
 var _ SlotLeader = (*Service)(nil)
 
+// Bounds of the exponential backoff between two attempts to compute a schedule.
+const (
+	defaultRetryMinBackoff = time.Minute
+	defaultRetryMaxBackoff = 30 * time.Minute
+)
+
+// ServiceOptionsFunc configures optional settings of the service.
+type ServiceOptionsFunc func(*Service)
+
+// WithScheduleCipher encrypts the leader slots stored in the database.
+func WithScheduleCipher(cipher *ScheduleCipher) ServiceOptionsFunc {
+	return func(s *Service) {
+		s.cipher = cipher
+	}
+}
+
 func NewSlotLeaderService(
 	db *sqlx.DB,
 	cardanocli cardano.CardanoClient,
 	blockfrost blockfrost.Client,
+	timeline *cardanotime.Timeline,
 	pools pools.Pools,
 	metrics *metrics.Collection,
 	concurrency int,
+	opts ...ServiceOptionsFunc,
 ) *Service {
 	logger := slog.With(
 		slog.String("component", "slot-leader-service"),
 	)
 
-	return &Service{
+	service := &Service{
 		db:          db,
 		logger:      logger,
 		pools:       pools,
 		cardano:     cardanocli,
 		blockfrost:  blockfrost,
+		timeline:    timeline,
 		metrics:     metrics,
 		concurrency: concurrency,
+		cache:       newScheduleCache(),
+		retries:     newRefreshRetries(),
+		vrfKeys:     newVRFKeyChecks(),
+
+		retryMinBackoff: defaultRetryMinBackoff,
+		retryMaxBackoff: defaultRetryMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(service)
 	}
+	return service
 }
 
+// RefreshCurrent refreshes the schedule of every active pool for the current
+// epoch. A pool failing to refresh does not prevent the others from being
+// refreshed: it is retried with an exponential backoff by RunRefreshRetrier
+// and the errors of all the failed pools are returned joined.
 func (s *Service) RefreshCurrent(ctx context.Context, epoch bfAPI.Epoch) error {
-	return s.refresh(ctx, epoch, "current", s.concurrency)
+	// The schedules and the retries of the previous epochs are no longer needed once the epoch changed
+	s.cache.invalidateBefore(epoch.Epoch)
+	s.retries.clearBefore(epoch.Epoch)
+	return s.refresh(ctx, epoch, "current", s.concurrency, s.pools.GetActivePools())
 }
 
 func (s *Service) RefreshNext(ctx context.Context, epoch bfAPI.Epoch, concurrency int) error {
-	return s.refresh(ctx, epoch, "next", concurrency)
+	return s.refresh(ctx, epoch, "next", concurrency, s.pools.GetActivePools())
 }
 
-//nolint:wrapcheck
-func (s *Service) refresh(ctx context.Context, epoch bfAPI.Epoch, ledgerSet string, concurrency int) error {
+func (s *Service) refresh(ctx context.Context, epoch bfAPI.Epoch, ledgerSet string, concurrency int, activePools []pools.Pool) error {
 	eg := errgroup.Group{}
 	if concurrency > 0 {
 		eg.SetLimit(concurrency)
 	}
 
-	activePools := s.pools.GetActivePools()
 	if concurrency > 0 {
 		s.logger.InfoContext(ctx, "🔄 refreshing slot leaders",
 			slog.Int("pools", len(activePools)),
@@ -77,197 +117,469 @@ func (s *Service) refresh(ctx context.Context, epoch bfAPI.Epoch, ledgerSet stri
 	if ledgerSet != "next" {
 		epochParams, err := s.blockfrost.GetEpochParameters(ctx, epoch.Epoch)
 		if err != nil {
-			return fmt.Errorf("slotLeader: unable to get epoch parameters: %w", err)
+			err = fmt.Errorf("slotLeader: unable to get epoch parameters: %w", err)
+			for _, pool := range activePools {
+				s.recordRefresh(ctx, pool, epoch.Epoch, err)
+			}
+			return err
 		}
 		epochNonce = epochParams.Nonce
 	}
 
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
 	for _, pool := range activePools {
-		eg.Go(func(pool pools.Pool) func() error {
-			return func() error {
-				refreshed, err := s.isRefresh(ctx, pool.ID, epoch.Epoch)
-				if err != nil {
-					return fmt.Errorf("unable to check if slots are already refreshed for %s: %w", pool.Name, err)
-				}
-
-				if !refreshed {
-					s.logger.InfoContext(ctx,
-						fmt.Sprintf("⏰ refreshing slots for pool: %s", pool.Name),
-						slog.String("pool_id", pool.ID),
-					)
-					var response cardano.ClientLeaderLogsResponse
-					if ledgerSet == "next" {
-						response, err = s.cardano.LeaderLogsNextEpoch(ctx, pool)
-					} else {
-						response, err = s.cardano.LeaderLogs(ctx, ledgerSet, epochNonce, pool)
-					}
-					if err != nil {
-						return &ErrSlotLeaderRefresh{PoolID: pool.ID, Epoch: epoch.Epoch, Message: err.Error()}
-					}
-					if err := s.persistSlots(ctx, pool.ID, epoch.Epoch, response); err != nil {
-						return fmt.Errorf("unable to persist slots for pool %s: %w", pool.Name, err)
-					}
-					s.logger.InfoContext(ctx,
-						fmt.Sprintf("✅ slots persisted for pool: %s", pool.Name),
-						slog.String("pool_id", pool.ID),
-						slog.Int("epoch", epoch.Epoch),
-						slog.Int("slot_count", len(response.AssignedSlots)),
-					)
-				} else {
-					s.logger.InfoContext(ctx,
-						fmt.Sprintf("💠 slots already refreshed for pool: %s", pool.Name),
-						slog.String("pool_id", pool.ID),
-					)
-				}
-
-				schedule, err := s.GetSlotLeaders(ctx, pool.ID, epoch.Epoch)
-				if err != nil {
-					return fmt.Errorf("unable to get slot leaders for pool %s: %w", pool.Name, err)
-				}
-				s.metrics.ExpectedBlocks.WithLabelValues(pool.Name, pool.ID, pool.Instance, strconv.Itoa(epoch.Epoch)).Set(float64(schedule.Quantity))
-				return nil
+		eg.Go(func() error {
+			err := s.refreshPool(ctx, pool, epoch.Epoch, ledgerSet, epochNonce)
+			if ledgerSet != "next" {
+				s.recordRefresh(ctx, pool, epoch.Epoch, err)
+			}
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
 			}
-		}(pool))
+			return nil
+		})
 	}
-	return eg.Wait()
+	_ = eg.Wait()
+	return errors.Join(errs...)
 }
 
-func (s *Service) RunNextEpochScheduler(ctx context.Context) error {
-	ticker := time.NewTicker(30 * time.Minute)
-	defer ticker.Stop()
+// refreshPool computes and stores the schedule of a pool when it was not
+// stored yet, then updates the metrics of the schedule.
+func (s *Service) refreshPool(ctx context.Context, pool pools.Pool, epoch int, ledgerSet string, epochNonce string) error {
+	// The schedule is reloaded from the database after the refresh
+	s.cache.invalidate(pool.ID, epoch)
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			epoch, err := s.blockfrost.GetLatestEpoch(ctx)
-			if err != nil {
-				s.logger.ErrorContext(ctx, "🚨 next-epoch-scheduler: unable to get latest epoch",
-					slog.String("error", err.Error()),
-				)
-				continue
-			}
+	refreshed, err := s.isRefresh(ctx, pool.ID, epoch)
+	if err != nil {
+		return fmt.Errorf("unable to check if slots are already refreshed for %s: %w", pool.Name, err)
+	}
+
+	if !refreshed {
+		// A schedule computed with the wrong VRF key would be bogus
+		if err := s.vrfKeys.err(pool.ID); err != nil {
+			return err
+		}
+
+		s.logger.InfoContext(ctx,
+			fmt.Sprintf("⏰ refreshing slots for pool: %s", pool.Name),
+			slog.String("pool_id", pool.ID),
+		)
+		var response cardano.ClientLeaderLogsResponse
+		if ledgerSet == "next" {
+			response, err = s.cardano.LeaderLogsNextEpoch(ctx, epoch, pool)
+		} else {
+			response, err = s.cardano.LeaderLogs(ctx, epoch, ledgerSet, epochNonce, pool)
+		}
+		if err != nil {
+			return &ErrSlotLeaderRefresh{PoolID: pool.ID, Epoch: epoch, Message: err.Error()}
+		}
+		if err := s.persistSlots(ctx, pool.ID, epoch, response); err != nil {
+			return fmt.Errorf("unable to persist slots for pool %s: %w", pool.Name, err)
+		}
+		s.logger.InfoContext(ctx,
+			fmt.Sprintf("✅ slots persisted for pool: %s", pool.Name),
+			slog.String("pool_id", pool.ID),
+			slog.Int("epoch", epoch),
+			slog.Int("slot_count", len(response.AssignedSlots)),
+		)
+	} else {
+		s.logger.InfoContext(ctx,
+			fmt.Sprintf("💠 slots already refreshed for pool: %s", pool.Name),
+			slog.String("pool_id", pool.ID),
+		)
+	}
+
+	schedule, err := s.GetSlotLeaders(ctx, pool.ID, epoch)
+	if err != nil {
+		return fmt.Errorf("unable to get slot leaders for pool %s: %w", pool.Name, err)
+	}
+	labels := []string{pool.Name, pool.ID, pool.Instance, strconv.Itoa(epoch)}
+	if ledgerSet == "next" {
+		s.metrics.NextEpochExpectedBlocks.WithLabelValues(labels...).Set(float64(schedule.Quantity))
+		s.metrics.NextEpochScheduleReady.WithLabelValues(labels...).Set(1)
+	} else {
+		s.metrics.ExpectedBlocks.WithLabelValues(labels...).Set(float64(schedule.Quantity))
+	}
+
+	leaderLog, err := s.GetLeaderLog(ctx, pool.ID, epoch)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// Schedules computed before the leader logs were stored have no details
+	case err != nil:
+		return fmt.Errorf("unable to get leader logs for pool %s: %w", pool.Name, err)
+	default:
+		s.updateLeaderLogMetrics(pool, leaderLog)
+	}
+	return nil
+}
 
-			timeUntilEnd := time.Until(time.Unix(int64(epoch.EndTime), 0).UTC())
-			s.logger.DebugContext(ctx, "🔍 next-epoch-scheduler: checking epoch end time",
-				slog.Int("current_epoch", epoch.Epoch),
-				slog.String("time_until_end", timeUntilEnd.Round(time.Minute).String()),
+// RunNextEpochScheduler computes the leader schedule of the next epoch as soon
+// as its nonce is fixed, at the start of the randomness stabilisation window of
+// the current epoch. Failed refreshes are retried with an exponential backoff
+// until the end of the epoch.
+func (s *Service) RunNextEpochScheduler(ctx context.Context) error {
+	lastEpoch := -1
+	for {
+		epoch, err := s.blockfrost.GetLatestEpoch(ctx)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "🚨 next-epoch-scheduler: unable to get latest epoch",
+				slog.String("error", err.Error()),
 			)
+			if !sleep(ctx, s.retryMinBackoff) {
+				return nil
+			}
+			continue
+		}
 
-			if timeUntilEnd > 24*time.Hour {
-				continue
+		// The provider may still report the previous epoch right after the epoch boundary
+		if epoch.Epoch <= lastEpoch {
+			if !sleep(ctx, s.retryMinBackoff) {
+				return nil
 			}
+			continue
+		}
+		lastEpoch = epoch.Epoch
+
+		nextEpoch := bfAPI.Epoch{Epoch: epoch.Epoch + 1}
+		s.resetNextEpochMetrics(nextEpoch.Epoch)
+
+		freezeTime := s.timeline.NextEpochNonceFreezeTime(epoch.Epoch)
+		s.logger.InfoContext(ctx, "📅 next-epoch-scheduler: next epoch slot schedule planned",
+			slog.Int("next_epoch", nextEpoch.Epoch),
+			slog.Time("nonce_freeze_time", freezeTime),
+			slog.String("nonce_freeze_in", time.Until(freezeTime).Round(time.Minute).String()),
+		)
+		if !sleep(ctx, time.Until(freezeTime)) {
+			return nil
+		}
+
+		s.refreshNextWithRetry(ctx, nextEpoch)
 
-			nextEpoch := bfAPI.Epoch{Epoch: epoch.Epoch + 1}
-			s.logger.InfoContext(ctx, "⏩ pre-computing next epoch slot schedule",
+		if !sleep(ctx, time.Until(s.timeline.EpochEndTime(epoch.Epoch))) {
+			return nil
+		}
+	}
+}
+
+// refreshNextWithRetry refreshes the schedule of the next epoch until it
+// succeeds or the next epoch starts. Pools already refreshed are not computed
+// again, so only the failed pools are retried.
+func (s *Service) refreshNextWithRetry(ctx context.Context, nextEpoch bfAPI.Epoch) {
+	backoff := s.retryMinBackoff
+	deadline := s.timeline.EpochStartTime(nextEpoch.Epoch)
+	for attempt := 1; ; attempt++ {
+		s.logger.InfoContext(ctx, "⏩ pre-computing next epoch slot schedule",
+			slog.Int("next_epoch", nextEpoch.Epoch),
+			slog.Int("attempt", attempt),
+			slog.String("next_epoch_in", time.Until(deadline).Round(time.Minute).String()),
+		)
+		err := s.RefreshNext(ctx, nextEpoch, s.concurrency)
+		if err == nil {
+			s.logger.InfoContext(ctx, "✅ next epoch slot schedule pre-computed successfully",
 				slog.Int("next_epoch", nextEpoch.Epoch),
-				slog.String("next_epoch_in", timeUntilEnd.Round(time.Minute).String()),
 			)
-			if err := s.RefreshNext(ctx, nextEpoch, s.concurrency); err != nil {
-				s.logger.ErrorContext(ctx, "🚨 next-epoch-scheduler: unable to pre-compute next epoch",
-					slog.Int("next_epoch", nextEpoch.Epoch),
-					slog.String("error", err.Error()),
-				)
-				continue
-			}
-			s.logger.InfoContext(ctx, "✅ next epoch slot schedule pre-computed successfully",
+			return
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			s.logger.ErrorContext(ctx, "🚨 next-epoch-scheduler: unable to pre-compute next epoch before it starts",
 				slog.Int("next_epoch", nextEpoch.Epoch),
+				slog.String("error", err.Error()),
 			)
+			return
+		}
+		s.logger.ErrorContext(ctx, "🚨 next-epoch-scheduler: unable to pre-compute next epoch, retrying",
+			slog.Int("next_epoch", nextEpoch.Epoch),
+			slog.String("retry_in", backoff.String()),
+			slog.String("error", err.Error()),
+		)
+		if !sleep(ctx, backoff) {
+			return
 		}
+		backoff = min(2*backoff, s.retryMaxBackoff)
+	}
+}
+
+// resetNextEpochMetrics marks the schedule of the next epoch as pending for
+// every active pool.
+func (s *Service) resetNextEpochMetrics(nextEpoch int) {
+	s.metrics.NextEpochScheduleReady.Reset()
+	s.metrics.NextEpochExpectedBlocks.Reset()
+	for _, pool := range s.pools.GetActivePools() {
+		s.metrics.NextEpochScheduleReady.WithLabelValues(pool.Name, pool.ID, pool.Instance, strconv.Itoa(nextEpoch)).Set(0)
+	}
+}
+
+// sleep waits for the given duration. It returns false when the context is
+// canceled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
 	}
 }
 
 func (s *Service) GetSlotLeaders(ctx context.Context, PoolID string, epoch int) (Schedule, error) {
-	schedule := []Schedule{}
-	err := s.db.SelectContext(ctx, &schedule, `SELECT * FROM slots WHERE pool_id = ? AND epoch = ?`, PoolID, epoch)
+	schedule, err := s.schedule(ctx, PoolID, epoch)
 	if err != nil {
-		return Schedule{}, fmt.Errorf("GetSlotLeaders: unable to get slot leaders for pool %s: %w", PoolID, err)
+		return Schedule{}, fmt.Errorf("GetSlotLeaders: %w", err)
 	}
 
-	if len(schedule) == 0 {
-		return Schedule{}, fmt.Errorf("GetSlotLeaders: no slot leaders found for pool %s in epoch %d", PoolID, epoch)
-	}
+	// The slots are shared with the cache
+	schedule.Slots = slices.Clone(schedule.Slots)
+	return schedule, nil
+}
 
-	return schedule[0], nil
+// GetLeaderSlotsInRange returns the slots assigned to a pool in an epoch
+// between startSlot and endSlot included. It returns ErrScheduleNotFound when
+// no schedule was stored for the pool in this epoch.
+func (s *Service) GetLeaderSlotsInRange(ctx context.Context, PoolID string, epoch int, startSlot int, endSlot int) ([]int, error) {
+	schedule, err := s.schedule(ctx, PoolID, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("GetLeaderSlotsInRange: %w", err)
+	}
+	return slotsInRange(schedule.Slots, startSlot, endSlot), nil
 }
 
+// GetNextSlotLeader returns the first slot assigned to a pool after height in
+// an epoch, or 0 when the pool has no slot left in this epoch.
 func (s *Service) GetNextSlotLeader(ctx context.Context, PoolID string, height int, epoch int) (int, error) {
-	schedule, err := s.GetSlotLeaders(ctx, PoolID, epoch)
+	schedule, err := s.schedule(ctx, PoolID, epoch)
 	if err != nil {
-		return 0, fmt.Errorf("GetNextSlotLeader: unable to get slot leaders for pool %s: %w", PoolID, err)
+		return 0, fmt.Errorf("GetNextSlotLeader: %w", err)
+	}
+
+	var nextSlot int
+	if i, _ := slices.BinarySearch(schedule.Slots, height+1); i < len(schedule.Slots) {
+		nextSlot = schedule.Slots[i]
 	}
 
-	lastSlot := schedule.Slots[len(schedule.Slots)-1]
 	s.logger.DebugContext(ctx,
-		fmt.Sprintf("last slot for pool %s is %d", PoolID, lastSlot),
+		fmt.Sprintf("next slot for pool %s is %d", PoolID, nextSlot),
 		slog.String("pool_id", PoolID),
-		slog.Int("last_slot", lastSlot),
+		slog.Int("next_slot", nextSlot),
 		slog.Int("height", height),
 	)
-	for _, slot := range schedule.Slots {
-		if slot > height {
-			return slot, nil
-		}
-	}
-
-	return 0, nil
+	return nextSlot, nil
 }
 
 func (s *Service) isRefresh(ctx context.Context, PoolID string, epoch int) (bool, error) {
-	schedule := []Schedule{}
-	err := s.db.SelectContext(ctx, &schedule, `SELECT * FROM slots WHERE pool_id = ? AND epoch = ?`, PoolID, epoch)
+	var count int
+	err := s.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?`, PoolID, epoch)
 	if err != nil {
 		return false, fmt.Errorf("isRefresh: unable to check if slots are already refreshed for pool %s: %w", PoolID, err)
 	}
 
-	return len(schedule) > 0, nil
+	return count > 0, nil
 }
 
 func (s *Service) IsSlotLeader(ctx context.Context, PoolID string, slot int, epoch int) (bool, error) {
-	schedule := []Schedule{}
-	err := s.db.SelectContext(ctx, &schedule, `SELECT slots FROM slots WHERE pool_id = ? AND epoch = ?`, PoolID, epoch)
+	schedule, err := s.schedule(ctx, PoolID, epoch)
+	if errors.Is(err, ErrScheduleNotFound) {
+		return false, nil
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return false, nil
-		}
 		return false, fmt.Errorf("IsSlotLeader: unable to check if slot %d is a leader for pool %s: %w", slot, PoolID, err)
 	}
-	for _, sslot := range schedule[0].Slots {
-		if sslot == slot {
-			return true, nil
-		}
-	}
-	return false, nil
+
+	_, found := slices.BinarySearch(schedule.Slots, slot)
+	return found, nil
 }
 
 func (s *Service) IsSlotsEmpty(ctx context.Context, PoolID string, epoch int) (bool, error) {
-	schedule := []Schedule{}
-	err := s.db.SelectContext(ctx, &schedule, `SELECT slots, slot_qty FROM slots WHERE pool_id = ? AND epoch = ?`, PoolID, epoch)
+	schedule, err := s.schedule(ctx, PoolID, epoch)
 	if err != nil {
 		return false, fmt.Errorf("IsSlotsEmpty: unable to check if slots are empty for pool %s: %w", PoolID, err)
 	}
 
-	return len(schedule[0].Slots) == 0 || schedule[0].Quantity == 0, nil
+	return schedule.Quantity == 0, nil
 }
 
-func (s *Service) persistSlots(ctx context.Context, poolID string, epoch int, response cardano.ClientLeaderLogsResponse) error {
-	assignedSlots := make([]int, len(response.AssignedSlots))
-	for i, slot := range response.AssignedSlots {
+// schedule returns the schedule of a pool in an epoch from the cache, loading
+// it from the database on the first lookup.
+func (s *Service) schedule(ctx context.Context, PoolID string, epoch int) (Schedule, error) {
+	if schedule, ok := s.cache.get(PoolID, epoch); ok {
+		s.metrics.SlotScheduleCacheHits.Inc()
+		return schedule, nil
+	}
+	s.metrics.SlotScheduleCacheMisses.Inc()
+
+	schedule, err := s.loadSchedule(ctx, PoolID, epoch)
+	if err != nil {
+		return Schedule{}, err
+	}
+	s.cache.set(schedule)
+	return schedule, nil
+}
+
+// loadSchedule loads the schedule of a pool in an epoch from the database. It
+// returns ErrScheduleNotFound when no schedule was stored.
+func (s *Service) loadSchedule(ctx context.Context, PoolID string, epoch int) (Schedule, error) {
+	schedule := Schedule{}
+	err := s.db.GetContext(ctx, &schedule, `SELECT id, epoch, pool_id, slot_qty, hash FROM slots WHERE pool_id = ? AND epoch = ?`, PoolID, epoch)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Schedule{}, fmt.Errorf("%w for pool %s in epoch %d", ErrScheduleNotFound, PoolID, epoch)
+	}
+	if err != nil {
+		return Schedule{}, fmt.Errorf("unable to get slot leaders for pool %s: %w", PoolID, err)
+	}
+
+	schedule.Slots = []int{}
+	if s.cipher != nil {
+		slots, err := s.loadEncryptedSlots(ctx, PoolID, epoch)
+		if err != nil {
+			return Schedule{}, err
+		}
+		for _, slot := range slots {
+			schedule.Slots = append(schedule.Slots, slot.Slot)
+		}
+		slices.Sort(schedule.Slots)
+		return schedule, nil
+	}
+
+	err = s.db.SelectContext(ctx, &schedule.Slots, `SELECT slot FROM leader_slots WHERE pool_id = ? AND epoch = ? ORDER BY slot`, PoolID, epoch)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("unable to get slots for pool %s: %w", PoolID, err)
+	}
+
+	return schedule, nil
+}
+
+// GetLeaderLog returns the complete leader logs of a pool for an epoch. It
+// returns sql.ErrNoRows when they were not stored.
+func (s *Service) GetLeaderLog(ctx context.Context, PoolID string, epoch int) (LeaderLog, error) {
+	leaderLog := LeaderLog{}
+	err := s.db.GetContext(ctx, &leaderLog,
+		`SELECT id, epoch, pool_id, epoch_nonce, consensus, epoch_slots, epoch_slots_ideal, max_performance, sigma, active_stake, total_active_stake FROM leader_logs WHERE pool_id = ? AND epoch = ?`,
+		PoolID, epoch,
+	)
+	if err != nil {
+		return LeaderLog{}, fmt.Errorf("GetLeaderLog: unable to get leader logs for pool %s in epoch %d: %w", PoolID, epoch, err)
+	}
+
+	if s.cipher != nil {
+		leaderLog.AssignedSlots, err = s.loadEncryptedSlots(ctx, PoolID, epoch)
+		if err != nil {
+			return LeaderLog{}, fmt.Errorf("GetLeaderLog: %w", err)
+		}
+		return leaderLog, nil
+	}
+
+	leaderSlots := []leaderSlot{}
+	err = s.db.SelectContext(ctx, &leaderSlots,
+		`SELECT slot, slot_in_epoch, at FROM leader_slots WHERE pool_id = ? AND epoch = ? ORDER BY slot`,
+		PoolID, epoch,
+	)
+	if err != nil {
+		return LeaderLog{}, fmt.Errorf("GetLeaderLog: unable to get assigned slots for pool %s in epoch %d: %w", PoolID, epoch, err)
+	}
+
+	leaderLog.AssignedSlots = toSlotSchedules(leaderSlots)
+	return leaderLog, nil
+}
+
+func (s *Service) updateLeaderLogMetrics(pool pools.Pool, leaderLog LeaderLog) {
+	labels := []string{pool.Name, pool.ID, pool.Instance, strconv.Itoa(leaderLog.Epoch)}
+	s.metrics.IdealSlots.WithLabelValues(labels...).Set(leaderLog.EpochSlotsIdeal)
+	s.metrics.PoolSigma.WithLabelValues(labels...).Set(leaderLog.Sigma)
+	s.metrics.MaxPerformance.WithLabelValues(labels...).Set(leaderLog.MaxPerformance)
+	s.metrics.LeaderLuck.WithLabelValues(labels...).Set(leaderLog.Luck())
+}
+
+// slotsHash returns the hash of the slots of a schedule: the blake2b-256
+// digest of its slots, or their keyed hash with a cipher since a plain digest
+// would give away an encrypted schedule.
+func (s *Service) slotsHash(poolID string, epoch int, slots []cardano.SlotSchedule) (string, error) {
+	assignedSlots := make([]int, len(slots))
+	for i, slot := range slots {
 		assignedSlots[i] = slot.Slot
 	}
 
 	slotsJSON, err := json.Marshal(assignedSlots)
 	if err != nil {
-		return fmt.Errorf("unable to marshal slots: %w", err)
+		return "", fmt.Errorf("unable to marshal slots: %w", err)
+	}
+	if s.cipher != nil {
+		return s.cipher.Hash(poolID, epoch, slotsJSON), nil
+	}
+	hash := blake2b.Sum256(slotsJSON)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// persistSlots stores the schedule of a pool, one row per assigned slot,
+// along with the complete leader logs and the hash of the slots. With a
+// cipher, the assigned slots are stored encrypted in the schedule row instead.
+func (s *Service) persistSlots(ctx context.Context, poolID string, epoch int, response cardano.ClientLeaderLogsResponse) error {
+	hash, err := s.slotsHash(poolID, epoch, response.AssignedSlots)
+	if err != nil {
+		return err
 	}
 
-	_, err = s.db.ExecContext(ctx,
-		`INSERT INTO slots (epoch, pool_id, slot_qty, slots, hash) VALUES (?, ?, ?, ?, ?)`,
-		epoch, poolID, len(assignedSlots), string(slotsJSON), "",
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if s.cipher != nil {
+		// The leader slots are only stored encrypted, without any leader_slots row
+		encrypted, err := s.cipher.Encrypt(poolID, epoch, response.AssignedSlots)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO slots (epoch, pool_id, slot_qty, hash, encrypted_slots) VALUES (?, ?, ?, ?, ?)`,
+			epoch, poolID, len(response.AssignedSlots), hash, encrypted,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to persist slots for pool %s epoch %d: %w", poolID, epoch, err)
+		}
+	} else {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO slots (epoch, pool_id, slot_qty, hash) VALUES (?, ?, ?, ?)`,
+			epoch, poolID, len(response.AssignedSlots), hash,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to persist slots for pool %s epoch %d: %w", poolID, epoch, err)
+		}
+
+		for _, slot := range response.AssignedSlots {
+			at := sql.NullTime{Time: slot.At, Valid: !slot.At.IsZero()}
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO leader_slots (epoch, pool_id, slot, slot_in_epoch, at) VALUES (?, ?, ?, ?, ?)`,
+				epoch, poolID, slot.Slot, slot.SlotInEpoch, at,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to persist slot %d for pool %s epoch %d: %w", slot.Slot, poolID, epoch, err)
+			}
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO leader_logs (epoch, pool_id, epoch_nonce, consensus, epoch_slots, epoch_slots_ideal, max_performance, sigma, active_stake, total_active_stake) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		epoch, poolID, response.EpochNonce, response.Consensus, len(response.AssignedSlots), response.EpochSlotsIdeal,
+		response.MaxPerformance, response.Sigma, response.ActiveStake, response.TotalActiveStake,
 	)
 	if err != nil {
-		return fmt.Errorf("unable to persist slots for pool %s epoch %d: %w", poolID, epoch, err)
+		return fmt.Errorf("unable to persist leader logs for pool %s epoch %d: %w", poolID, epoch, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit slots for pool %s epoch %d: %w", poolID, epoch, err)
 	}
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/blockfrost/blockfrost-go"
@@ -15,6 +16,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
 )
 
 func TestRefresh(t *testing.T) {
@@ -33,15 +36,35 @@ func TestRefresh(t *testing.T) {
 		# HELP cardano_validator_watcher_expected_blocks number of expected blocks in the current epoch
 		# TYPE cardano_validator_watcher_expected_blocks gauge
 		cardano_validator_watcher_expected_blocks{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 2
+		# HELP cardano_validator_watcher_ideal_slots number of leader slots expected from the stake of the pool in the epoch
+		# TYPE cardano_validator_watcher_ideal_slots gauge
+		cardano_validator_watcher_ideal_slots{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1.6
+		# HELP cardano_validator_watcher_pool_sigma share of the active stake delegated to the pool in the epoch
+		# TYPE cardano_validator_watcher_pool_sigma gauge
+		cardano_validator_watcher_pool_sigma{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 0.0001
+		# HELP cardano_validator_watcher_max_performance assigned leader slots over ideal slots in the epoch, in percent
+		# TYPE cardano_validator_watcher_max_performance gauge
+		cardano_validator_watcher_max_performance{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 125
+		# HELP cardano_validator_watcher_leader_luck ratio of assigned leader slots to ideal slots in the epoch, 1 meaning as many slots as expected
+		# TYPE cardano_validator_watcher_leader_luck gauge
+		cardano_validator_watcher_leader_luck{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1.25
+		# HELP cardano_validator_watcher_slot_schedule_refresh_status Status of the last refresh of the leader schedule of the current epoch: 1 = ok, 0 = failed
+		# TYPE cardano_validator_watcher_slot_schedule_refresh_status gauge
+		cardano_validator_watcher_slot_schedule_refresh_status{pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1
 		`
 		registry.metricsUnderTest = []string{
 			"cardano_validator_watcher_expected_blocks",
+			"cardano_validator_watcher_ideal_slots",
+			"cardano_validator_watcher_pool_sigma",
+			"cardano_validator_watcher_max_performance",
+			"cardano_validator_watcher_leader_luck",
+			"cardano_validator_watcher_slot_schedule_refresh_status",
 		}
 
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
@@ -57,39 +80,64 @@ func TestRefresh(t *testing.T) {
 				nil,
 			)
 
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
 			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}),
-			)
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
-		clients.cardano.EXPECT().LeaderLogs(mock.Anything, "current", "nonce", pools[0]).Return(
+		at := time.Date(2024, 7, 1, 21, 44, 51, 0, time.UTC)
+		clients.cardano.EXPECT().LeaderLogs(mock.Anything, epoch, "current", "nonce", pools[0]).Return(
 			cardano.ClientLeaderLogsResponse{
-				Status: "ok",
+				Status:           "ok",
+				Epoch:            epoch,
+				EpochNonce:       "nonce",
+				Consensus:        "praos",
+				EpochSlots:       2,
+				EpochSlotsIdeal:  1.6,
+				MaxPerformance:   125,
+				Sigma:            0.0001,
+				ActiveStake:      2000,
+				TotalActiveStake: 20000000,
 				AssignedSlots: []cardano.SlotSchedule{
-					{Slot: 1000},
-					{Slot: 2000},
+					{No: 1, Slot: 1000, SlotInEpoch: 10, At: at},
+					{No: 2, Slot: 2000, SlotInEpoch: 1010, At: at.Add(1000 * time.Second)},
 				},
 			},
 			nil,
 		)
 
-		db.mock.ExpectExec("INSERT INTO slots (epoch, pool_id, slot_qty, slots, hash) VALUES (?, ?, ?, ?, ?)").
-			WithArgs(epoch, pools[0].ID, 2, "[1000,2000]", "").
+		db.mock.ExpectBegin()
+		db.mock.ExpectExec("INSERT INTO slots (epoch, pool_id, slot_qty, hash) VALUES (?, ?, ?, ?)").
+			WithArgs(epoch, pools[0].ID, 2, slotsHash("[1000,2000]")).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectExec("INSERT INTO leader_slots (epoch, pool_id, slot, slot_in_epoch, at) VALUES (?, ?, ?, ?, ?)").
+			WithArgs(epoch, pools[0].ID, 1000, 10, sql.NullTime{Time: at, Valid: true}).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectExec("INSERT INTO leader_slots (epoch, pool_id, slot, slot_in_epoch, at) VALUES (?, ?, ?, ?, ?)").
+			WithArgs(epoch, pools[0].ID, 2000, 1010, sql.NullTime{Time: at.Add(1000 * time.Second), Valid: true}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectExec("INSERT INTO leader_logs (epoch, pool_id, epoch_nonce, consensus, epoch_slots, epoch_slots_ideal, max_performance, sigma, active_stake, total_active_stake) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pools[0].ID, "nonce", "praos", 2, 1.6, 125.0, 0.0001, 2000, 20000000).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectCommit()
+
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000, 2000)
 
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery(leaderLogQuery).
 			WithArgs(pools[0].ID, epoch).
 			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}).AddRow(
-					1,
-					epoch,
-					pools[0].ID,
-					2,
-					"[1000, 2000]",
-					"hash",
+				sqlmock.NewRows(leaderLogColumns).AddRow(
+					1, epoch, pools[0].ID, "nonce", "praos", 2, 1.6, 125.0, 0.0001, 2000, 20000000,
 				),
 			)
+		db.mock.ExpectQuery(leaderLogSlotsQuery).
+			WithArgs(pools[0].ID, epoch).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"slot", "slot_in_epoch", "at"}).
+					AddRow(1000, 10, at).
+					AddRow(2000, 1010, at.Add(1000*time.Second)),
+			)
+
+		expectRefreshStatus(db.mock, pools[0].ID, epoch, RefreshStatusOK)
 
 		err := slotLeaderService.RefreshCurrent(context.Background(), blockfrost.Epoch{Epoch: epoch})
 		require.NoError(t, err)
@@ -120,7 +168,7 @@ func TestRefresh(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
@@ -136,31 +184,18 @@ func TestRefresh(t *testing.T) {
 				nil,
 			)
 
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
 			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}).AddRow(
-					1,
-					epoch,
-					pools[0].ID,
-					2,
-					"[1000, 2000]",
-					"hash",
-				),
-			)
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000, 2000)
 
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		// schedules computed before the leader logs were stored have no details
+		db.mock.ExpectQuery(leaderLogQuery).
 			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}).AddRow(
-					1,
-					epoch,
-					pools[0].ID,
-					2,
-					"[1000, 2000]",
-					"hash",
-				),
-			)
+			WillReturnError(sql.ErrNoRows)
+
+		expectRefreshStatus(db.mock, pools[0].ID, epoch, RefreshStatusOK)
 
 		err := slotLeaderService.RefreshCurrent(context.Background(), blockfrost.Epoch{Epoch: epoch})
 		require.NoError(t, err)
@@ -187,7 +222,7 @@ func TestRefresh(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
@@ -203,8 +238,11 @@ func TestRefresh(t *testing.T) {
 				nil,
 			)
 
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
-			WithArgs(pools[0].ID, epoch).WillReturnError(errors.New("timeout"))
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
+			WithArgs(pools[0].ID, epoch).
+			WillReturnError(errors.New("timeout"))
+
+		expectRefreshStatus(db.mock, pools[0].ID, epoch, RefreshStatusFailed)
 
 		err := slotLeaderService.RefreshCurrent(context.Background(), blockfrost.Epoch{Epoch: epoch})
 		require.ErrorContains(t, err, "unable to check if slots are already refreshed for pool")
@@ -231,7 +269,7 @@ func TestRefresh(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
@@ -247,23 +285,16 @@ func TestRefresh(t *testing.T) {
 				nil,
 			)
 
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
 			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}).AddRow(
-					1,
-					epoch,
-					pools[0].ID,
-					2,
-					"[1000, 2000]",
-					"hash",
-				),
-			)
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery(scheduleQuery).
 			WithArgs(pools[0].ID, epoch).
 			WillReturnError(errors.New("timeout"))
 
+		expectRefreshStatus(db.mock, pools[0].ID, epoch, RefreshStatusFailed)
+
 		err := slotLeaderService.RefreshCurrent(context.Background(), blockfrost.Epoch{Epoch: epoch})
 		require.ErrorContains(t, err, "unable to get slot leaders for pool")
 
@@ -289,7 +320,7 @@ func TestRefresh(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
@@ -302,6 +333,8 @@ func TestRefresh(t *testing.T) {
 				errors.New("timeout"),
 			)
 
+		expectRefreshStatus(db.mock, pools[0].ID, epoch, RefreshStatusFailed)
+
 		err := slotLeaderService.RefreshCurrent(context.Background(), blockfrost.Epoch{Epoch: epoch})
 		require.ErrorContains(t, err, "unable to get epoch parameters")
 
@@ -319,15 +352,20 @@ func TestRefresh(t *testing.T) {
 		registry := setupRegistry(t)
 		epoch := 100
 
-		registry.metricsExpectedOutput = ``
+		registry.metricsExpectedOutput = `
+		# HELP cardano_validator_watcher_slot_schedule_refresh_status Status of the last refresh of the leader schedule of the current epoch: 1 = ok, 0 = failed
+		# TYPE cardano_validator_watcher_slot_schedule_refresh_status gauge
+		cardano_validator_watcher_slot_schedule_refresh_status{pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 0
+		`
 		registry.metricsUnderTest = []string{
 			"cardano_validator_watcher_expected_blocks",
+			"cardano_validator_watcher_slot_schedule_refresh_status",
 		}
 
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
@@ -343,13 +381,13 @@ func TestRefresh(t *testing.T) {
 				nil,
 			)
 
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
 			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}),
-			)
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		clients.cardano.EXPECT().LeaderLogs(mock.Anything, epoch, "current", "nonce", pools[0]).Return(cardano.ClientLeaderLogsResponse{}, errors.New("cardano timeout"))
 
-		clients.cardano.EXPECT().LeaderLogs(mock.Anything, "current", "nonce", pools[0]).Return(cardano.ClientLeaderLogsResponse{}, errors.New("cardano timeout"))
+		expectRefreshStatus(db.mock, pools[0].ID, epoch, RefreshStatusFailed)
 
 		err := slotLeaderService.RefreshCurrent(context.Background(), blockfrost.Epoch{Epoch: epoch})
 		require.ErrorContains(t, err, "unable to refresh slot leaders for pool")
@@ -357,9 +395,163 @@ func TestRefresh(t *testing.T) {
 		b := bytes.NewBufferString(registry.metricsExpectedOutput)
 		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
 		require.NoError(t, err)
+		require.NoError(t, db.mock.ExpectationsWereMet())
+	})
+	t.Run("GoodPath_Refresh_FailingPoolDoesNotBlockOtherPools", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		db := setupDB(t)
+		registry := setupRegistry(t)
+		epoch := 100
+		pools := pools.Pools{
+			{ID: "pool-a", Instance: "pool-a", Key: "key", Name: "pool-a"},
+			{ID: "pool-b", Instance: "pool-b", Key: "key", Name: "pool-b"},
+		}
+
+		registry.metricsExpectedOutput = `
+		# HELP cardano_validator_watcher_expected_blocks number of expected blocks in the current epoch
+		# TYPE cardano_validator_watcher_expected_blocks gauge
+		cardano_validator_watcher_expected_blocks{epoch="100", pool_id="pool-b", pool_instance="pool-b", pool_name="pool-b"} 2
+		# HELP cardano_validator_watcher_slot_schedule_refresh_status Status of the last refresh of the leader schedule of the current epoch: 1 = ok, 0 = failed
+		# TYPE cardano_validator_watcher_slot_schedule_refresh_status gauge
+		cardano_validator_watcher_slot_schedule_refresh_status{pool_id="pool-a", pool_instance="pool-a", pool_name="pool-a"} 0
+		cardano_validator_watcher_slot_schedule_refresh_status{pool_id="pool-b", pool_instance="pool-b", pool_name="pool-b"} 1
+		`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_expected_blocks",
+			"cardano_validator_watcher_slot_schedule_refresh_status",
+		}
+
+		// the pools are refreshed one after the other
+		slotLeaderService := NewSlotLeaderService(
+			db.db,
+			clients.cardano,
+			clients.bf, nil, pools,
+			registry.metrics,
+			1,
+		)
+
+		// setup mocks
+		clients.bf.EXPECT().
+			GetEpochParameters(mock.Anything, epoch).
+			Return(blockfrost.EpochParameters{Epoch: epoch, Nonce: "nonce"}, nil)
+
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
+			WithArgs(pools[0].ID, epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		clients.cardano.EXPECT().LeaderLogs(mock.Anything, epoch, "current", "nonce", pools[0]).
+			Return(cardano.ClientLeaderLogsResponse{}, errors.New("invalid vrf key"))
+		expectRefreshStatus(db.mock, pools[0].ID, epoch, RefreshStatusFailed)
+
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
+			WithArgs(pools[1].ID, epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		expectSchedule(db.mock, pools[1].ID, epoch, 1000, 2000)
+		db.mock.ExpectQuery(leaderLogQuery).
+			WithArgs(pools[1].ID, epoch).
+			WillReturnError(sql.ErrNoRows)
+		expectRefreshStatus(db.mock, pools[1].ID, epoch, RefreshStatusOK)
+
+		err := slotLeaderService.RefreshCurrent(context.Background(), blockfrost.Epoch{Epoch: epoch})
+		var refreshErr *ErrSlotLeaderRefresh
+		require.ErrorAs(t, err, &refreshErr)
+		require.Equal(t, pools[0].ID, refreshErr.PoolID)
+		require.NoError(t, db.mock.ExpectationsWereMet())
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+}
+
+func TestRunRefreshRetrier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_RetriesFailedPool", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		db := setupDB(t)
+		registry := setupRegistry(t)
+		epoch := 100
+
+		slotLeaderService := NewSlotLeaderService(
+			db.db,
+			clients.cardano,
+			clients.bf, nil, pools,
+			registry.metrics,
+			0,
+		)
+		slotLeaderService.retryMinBackoff = 10 * time.Millisecond
+
+		// setup mocks
+		clients.bf.EXPECT().
+			GetEpochParameters(mock.Anything, epoch).
+			Return(blockfrost.EpochParameters{Epoch: epoch, Nonce: "nonce"}, nil)
+
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
+			WithArgs(pools[0].ID, epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		clients.cardano.EXPECT().LeaderLogs(mock.Anything, epoch, "current", "nonce", pools[0]).
+			Return(cardano.ClientLeaderLogsResponse{}, errors.New("cncli crashed")).Once()
+		expectRefreshStatus(db.mock, pools[0].ID, epoch, RefreshStatusFailed)
+
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
+			WithArgs(pools[0].ID, epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		clients.cardano.EXPECT().LeaderLogs(mock.Anything, epoch, "current", "nonce", pools[0]).
+			Return(cardano.ClientLeaderLogsResponse{AssignedSlots: []cardano.SlotSchedule{{Slot: 1000}}}, nil).Once()
+		db.mock.ExpectBegin()
+		db.mock.ExpectExec("INSERT INTO slots (epoch, pool_id, slot_qty, hash) VALUES (?, ?, ?, ?)").
+			WithArgs(epoch, pools[0].ID, 1, slotsHash("[1000]")).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectExec("INSERT INTO leader_slots (epoch, pool_id, slot, slot_in_epoch, at) VALUES (?, ?, ?, ?, ?)").
+			WithArgs(epoch, pools[0].ID, 1000, 0, sql.NullTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectExec("INSERT INTO leader_logs (epoch, pool_id, epoch_nonce, consensus, epoch_slots, epoch_slots_ideal, max_performance, sigma, active_stake, total_active_stake) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pools[0].ID, "", "", 1, 0.0, 0.0, 0.0, 0, 0).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectCommit()
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000)
+		db.mock.ExpectQuery(leaderLogQuery).
+			WithArgs(pools[0].ID, epoch).
+			WillReturnError(sql.ErrNoRows)
+		expectRefreshStatus(db.mock, pools[0].ID, epoch, RefreshStatusOK)
+
+		err := slotLeaderService.RefreshCurrent(context.Background(), blockfrost.Epoch{Epoch: epoch})
+		require.ErrorContains(t, err, "cncli crashed")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- slotLeaderService.RunRefreshRetrier(ctx)
+		}()
+
+		require.Eventually(t, func() bool {
+			status := registry.metrics.SlotScheduleRefreshStatus.WithLabelValues(pools[0].Name, pools[0].ID, pools[0].Instance)
+			return testutil.ToFloat64(status) == 1
+		}, 3*time.Second, 10*time.Millisecond)
+
+		cancel()
+		require.NoError(t, <-done)
+		require.NoError(t, db.mock.ExpectationsWereMet())
 	})
 }
 
+func TestRefreshBackoff(t *testing.T) {
+	t.Parallel()
+
+	slotLeaderService := NewSlotLeaderService(nil, nil, nil, nil, nil, nil, 0)
+
+	require.Equal(t, time.Minute, slotLeaderService.refreshBackoff(1))
+	require.Equal(t, 2*time.Minute, slotLeaderService.refreshBackoff(2))
+	require.Equal(t, 16*time.Minute, slotLeaderService.refreshBackoff(5))
+	require.Equal(t, 30*time.Minute, slotLeaderService.refreshBackoff(6))
+	require.Equal(t, 30*time.Minute, slotLeaderService.refreshBackoff(100))
+}
+
 func TestRefreshNext(t *testing.T) {
 	t.Parallel()
 
@@ -373,29 +565,32 @@ func TestRefreshNext(t *testing.T) {
 		nextEpoch := 101
 
 		registry.metricsExpectedOutput = `
-		# HELP cardano_validator_watcher_expected_blocks number of expected blocks in the current epoch
-		# TYPE cardano_validator_watcher_expected_blocks gauge
-		cardano_validator_watcher_expected_blocks{epoch="101", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 2
+		# HELP cardano_validator_watcher_next_epoch_expected_blocks number of expected blocks in the next epoch
+		# TYPE cardano_validator_watcher_next_epoch_expected_blocks gauge
+		cardano_validator_watcher_next_epoch_expected_blocks{epoch="101", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 2
+		# HELP cardano_validator_watcher_next_epoch_schedule_ready Leader schedule of the next epoch computed: 1 = ready, 0 = pending
+		# TYPE cardano_validator_watcher_next_epoch_schedule_ready gauge
+		cardano_validator_watcher_next_epoch_schedule_ready{epoch="101", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1
 		`
 		registry.metricsUnderTest = []string{
 			"cardano_validator_watcher_expected_blocks",
+			"cardano_validator_watcher_next_epoch_expected_blocks",
+			"cardano_validator_watcher_next_epoch_schedule_ready",
 		}
 
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
 			WithArgs(pools[0].ID, nextEpoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}),
-			)
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
-		clients.cardano.EXPECT().LeaderLogsNextEpoch(mock.Anything, pools[0]).Return(
+		clients.cardano.EXPECT().LeaderLogsNextEpoch(mock.Anything, nextEpoch, pools[0]).Return(
 			cardano.ClientLeaderLogsResponse{
 				Status: "ok",
 				AssignedSlots: []cardano.SlotSchedule{
@@ -406,17 +601,36 @@ func TestRefreshNext(t *testing.T) {
 			nil,
 		)
 
-		db.mock.ExpectExec("INSERT INTO slots (epoch, pool_id, slot_qty, slots, hash) VALUES (?, ?, ?, ?, ?)").
-			WithArgs(nextEpoch, pools[0].ID, 2, "[1000,2000]", "").
+		db.mock.ExpectBegin()
+		db.mock.ExpectExec("INSERT INTO slots (epoch, pool_id, slot_qty, hash) VALUES (?, ?, ?, ?)").
+			WithArgs(nextEpoch, pools[0].ID, 2, slotsHash("[1000,2000]")).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		for _, slot := range []int{1000, 2000} {
+			db.mock.ExpectExec("INSERT INTO leader_slots (epoch, pool_id, slot, slot_in_epoch, at) VALUES (?, ?, ?, ?, ?)").
+				WithArgs(nextEpoch, pools[0].ID, slot, 0, sql.NullTime{}).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+		db.mock.ExpectExec("INSERT INTO leader_logs (epoch, pool_id, epoch_nonce, consensus, epoch_slots, epoch_slots_ideal, max_performance, sigma, active_stake, total_active_stake) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(nextEpoch, pools[0].ID, "", "", 2, 0.0, 0.0, 0.0, 0, 0).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectCommit()
+
+		expectSchedule(db.mock, pools[0].ID, nextEpoch, 1000, 2000)
 
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery(leaderLogQuery).
 			WithArgs(pools[0].ID, nextEpoch).
 			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}).AddRow(
-					1, nextEpoch, pools[0].ID, 2, "[1000, 2000]", "hash",
+				sqlmock.NewRows(leaderLogColumns).AddRow(
+					1, nextEpoch, pools[0].ID, "", "", 2, 0.0, 0.0, 0.0, 0, 0,
 				),
 			)
+		db.mock.ExpectQuery(leaderLogSlotsQuery).
+			WithArgs(pools[0].ID, nextEpoch).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"slot", "slot_in_epoch", "at"}).
+					AddRow(1000, 0, nil).
+					AddRow(2000, 0, nil),
+			)
 
 		err := slotLeaderService.RefreshNext(context.Background(), blockfrost.Epoch{Epoch: nextEpoch}, 0)
 		require.NoError(t, err)
@@ -438,18 +652,16 @@ func TestRefreshNext(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
 			WithArgs(pools[0].ID, nextEpoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}),
-			)
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
-		clients.cardano.EXPECT().LeaderLogsNextEpoch(mock.Anything, pools[0]).Return(
+		clients.cardano.EXPECT().LeaderLogsNextEpoch(mock.Anything, nextEpoch, pools[0]).Return(
 			cardano.ClientLeaderLogsResponse{}, errors.New("cardano-cli timeout"),
 		)
 
@@ -458,6 +670,86 @@ func TestRefreshNext(t *testing.T) {
 	})
 }
 
+func TestRunNextEpochScheduler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_RetriesUntilTheNextEpochIsComputed", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		db := setupDB(t)
+		registry := setupRegistry(t)
+
+		// Epochs of 100 slots with a stability window of 8 slots: the nonce of
+		// the next epoch is already fixed and the epoch ends in 5 seconds.
+		timeline := cardanotime.NewTimeline(cardanotime.ShelleyGenesis{
+			SystemStart:      time.Now().Add(-95 * time.Second),
+			EpochLength:      100,
+			SlotLength:       1,
+			ActiveSlotsCoeff: 0.5,
+			SecurityParam:    1,
+		}, 0)
+
+		slotLeaderService := NewSlotLeaderService(
+			db.db,
+			clients.cardano,
+			clients.bf, timeline, pools,
+			registry.metrics,
+			0,
+		)
+		slotLeaderService.retryMinBackoff = 10 * time.Millisecond
+
+		// setup mocks
+		clients.bf.EXPECT().GetLatestEpoch(mock.Anything).Return(blockfrost.Epoch{Epoch: 0}, nil)
+
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
+			WithArgs(pools[0].ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		clients.cardano.EXPECT().LeaderLogsNextEpoch(mock.Anything, 1, pools[0]).
+			Return(cardano.ClientLeaderLogsResponse{}, errors.New("cardano-cli timeout")).Once()
+
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
+			WithArgs(pools[0].ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		clients.cardano.EXPECT().LeaderLogsNextEpoch(mock.Anything, 1, pools[0]).
+			Return(cardano.ClientLeaderLogsResponse{AssignedSlots: []cardano.SlotSchedule{{Slot: 120}, {Slot: 150}}}, nil).Once()
+		db.mock.ExpectBegin()
+		db.mock.ExpectExec("INSERT INTO slots (epoch, pool_id, slot_qty, hash) VALUES (?, ?, ?, ?)").
+			WithArgs(1, pools[0].ID, 2, slotsHash("[120,150]")).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		for _, slot := range []int{120, 150} {
+			db.mock.ExpectExec("INSERT INTO leader_slots (epoch, pool_id, slot, slot_in_epoch, at) VALUES (?, ?, ?, ?, ?)").
+				WithArgs(1, pools[0].ID, slot, 0, sql.NullTime{}).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+		db.mock.ExpectExec("INSERT INTO leader_logs (epoch, pool_id, epoch_nonce, consensus, epoch_slots, epoch_slots_ideal, max_performance, sigma, active_stake, total_active_stake) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(1, pools[0].ID, "", "", 2, 0.0, 0.0, 0.0, 0, 0).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		db.mock.ExpectCommit()
+		expectSchedule(db.mock, pools[0].ID, 1, 120, 150)
+		db.mock.ExpectQuery(leaderLogQuery).
+			WithArgs(pools[0].ID, 1).
+			WillReturnError(sql.ErrNoRows)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- slotLeaderService.RunNextEpochScheduler(ctx)
+		}()
+
+		require.Eventually(t, func() bool {
+			ready := registry.metrics.NextEpochScheduleReady.WithLabelValues(pools[0].Name, pools[0].ID, pools[0].Instance, "1")
+			return testutil.ToFloat64(ready) == 1
+		}, 3*time.Second, 10*time.Millisecond)
+		require.InDelta(t, 2.0, testutil.ToFloat64(registry.metrics.NextEpochExpectedBlocks.WithLabelValues(pools[0].Name, pools[0].ID, pools[0].Instance, "1")), 0.0001)
+
+		cancel()
+		require.NoError(t, <-done)
+		require.NoError(t, db.mock.ExpectationsWereMet())
+	})
+}
+
 func TestGetNextSlotLeader(t *testing.T) {
 	t.Parallel()
 
@@ -474,24 +766,13 @@ func TestGetNextSlotLeader(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
-			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}).AddRow(
-					1,
-					epoch,
-					pools[0].ID,
-					2,
-					"[1000, 2000]",
-					"hash",
-				),
-			)
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000, 2000)
 
 		slot, err := slotLeaderService.GetNextSlotLeader(context.Background(), pools[0].ID, height, epoch)
 		require.NoError(t, err)
@@ -511,13 +792,13 @@ func TestGetNextSlotLeader(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery(scheduleQuery).
 			WithArgs(pools[0].ID, epoch).
 			WillReturnError(errors.New("timeout"))
 
@@ -539,20 +820,18 @@ func TestGetNextSlotLeader(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery(scheduleQuery).
 			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}),
-			)
+			WillReturnRows(sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "hash"}))
 
 		slot, err := slotLeaderService.GetNextSlotLeader(context.Background(), pools[0].ID, height, epoch)
-		require.ErrorContains(t, err, "no slot leaders found for pool")
+		require.ErrorIs(t, err, ErrScheduleNotFound)
 		require.Equal(t, 0, slot)
 	})
 
@@ -569,24 +848,13 @@ func TestGetNextSlotLeader(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
-			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}).AddRow(
-					1,
-					epoch,
-					pools[0].ID,
-					2,
-					"[1000, 2000]",
-					"hash",
-				),
-			)
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000, 2000)
 
 		slot, err := slotLeaderService.GetNextSlotLeader(context.Background(), pools[0].ID, height, epoch)
 		require.NoError(t, err)
@@ -609,24 +877,15 @@ func TestIsRefresh(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
 			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "slots", "hash"}).AddRow(
-					1,
-					epoch,
-					pools[0].ID,
-					2,
-					"[1000, 2000]",
-					"hash",
-				),
-			)
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 		isRefresh, err := slotLeaderService.isRefresh(context.Background(), pools[0].ID, epoch)
 		require.NoError(t, err)
 		require.True(t, isRefresh)
@@ -644,13 +903,13 @@ func TestIsRefresh(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT * FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
 			WithArgs(pools[0].ID, epoch).
 			WillReturnError(errors.New("timeout"))
 
@@ -676,19 +935,14 @@ func TestIsSlotLeader(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT slots FROM slots WHERE pool_id = ? AND epoch = ?").
-			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"slots"}).AddRow(
-					"[1000, 2000]",
-				),
-			)
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000, 2000)
+
 		isLeader, err := slotLeaderService.IsSlotLeader(context.Background(), pools[0].ID, height, epoch)
 		require.NoError(t, err)
 		require.True(t, isLeader)
@@ -707,19 +961,14 @@ func TestIsSlotLeader(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT slots FROM slots WHERE pool_id = ? AND epoch = ?").
-			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"slots"}).AddRow(
-					"[1000, 2000]",
-				),
-			)
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000, 2000)
+
 		isLeader, err := slotLeaderService.IsSlotLeader(context.Background(), pools[0].ID, height, epoch)
 		require.NoError(t, err)
 		require.False(t, isLeader)
@@ -738,13 +987,13 @@ func TestIsSlotLeader(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT slots FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery(scheduleQuery).
 			WithArgs(pools[0].ID, epoch).
 			WillReturnError(errors.New("timeout"))
 
@@ -752,8 +1001,12 @@ func TestIsSlotLeader(t *testing.T) {
 		require.ErrorContains(t, err, fmt.Sprintf("unable to check if slot %d is a leader for pool %s", height, pools[0].ID))
 		require.False(t, isLeader)
 	})
+}
 
-	t.Run("SadPath_IsSlotLeader_NoSlotLeadersFound", func(t *testing.T) {
+func TestIsSlotEmpty(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_IsSlotEmpty_Empty", func(t *testing.T) {
 		t.Parallel()
 
 		clients := setupClients(t)
@@ -761,31 +1014,26 @@ func TestIsSlotLeader(t *testing.T) {
 		db := setupDB(t)
 		registry := setupRegistry(t)
 		epoch := 100
-		height := 100
 
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT slots FROM slots WHERE pool_id = ? AND epoch = ?").
-			WithArgs(pools[0].ID, epoch).
-			WillReturnError(sql.ErrNoRows)
-		isLeader, err := slotLeaderService.IsSlotLeader(context.Background(), pools[0].ID, height, epoch)
+		expectSchedule(db.mock, pools[0].ID, epoch)
+
+		isEmpty, err := slotLeaderService.IsSlotsEmpty(context.Background(), pools[0].ID, epoch)
 		require.NoError(t, err)
-		require.False(t, isLeader)
+		require.True(t, isEmpty)
 	})
-}
-
-func TestIsSlotEmpty(t *testing.T) {
-	t.Parallel()
 
-	t.Run("GoodPath_IsSlotEmpty_Empty", func(t *testing.T) {
+	t.Run("GoodPath_IsSlotEmpty_NotEmpty", func(t *testing.T) {
 		t.Parallel()
+
 		clients := setupClients(t)
 		pools := setupPools(t)
 		db := setupDB(t)
@@ -795,28 +1043,22 @@ func TestIsSlotEmpty(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT slots, slot_qty FROM slots WHERE pool_id = ? AND epoch = ?").
-			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"slots", "slot_qty"}).AddRow(
-					"[]",
-					0,
-				),
-			)
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000, 2000)
 
 		isEmpty, err := slotLeaderService.IsSlotsEmpty(context.Background(), pools[0].ID, epoch)
 		require.NoError(t, err)
-		require.True(t, isEmpty)
+		require.False(t, isEmpty)
 	})
 
-	t.Run("GoodPath_IsSlotEmpty_NotEmpty", func(t *testing.T) {
+	t.Run("SadPath_IsSlotEmpty_NoSchedule", func(t *testing.T) {
 		t.Parallel()
+
 		clients := setupClients(t)
 		pools := setupPools(t)
 		db := setupDB(t)
@@ -826,28 +1068,24 @@ func TestIsSlotEmpty(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT slots, slot_qty FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery(scheduleQuery).
 			WithArgs(pools[0].ID, epoch).
-			WillReturnRows(
-				sqlmock.NewRows([]string{"slots", "slot_qty"}).AddRow(
-					"[1000, 2000]",
-					2,
-				),
-			)
+			WillReturnRows(sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "hash"}))
 
 		isEmpty, err := slotLeaderService.IsSlotsEmpty(context.Background(), pools[0].ID, epoch)
-		require.NoError(t, err)
+		require.ErrorIs(t, err, ErrScheduleNotFound)
 		require.False(t, isEmpty)
 	})
 
 	t.Run("SadPath_IsSlotEmpty_UnableToGetSlotLeaders", func(t *testing.T) {
 		t.Parallel()
+
 		clients := setupClients(t)
 		pools := setupPools(t)
 		db := setupDB(t)
@@ -857,13 +1095,13 @@ func TestIsSlotEmpty(t *testing.T) {
 		slotLeaderService := NewSlotLeaderService(
 			db.db,
 			clients.cardano,
-			clients.bf, pools,
+			clients.bf, nil, pools,
 			registry.metrics,
 			0,
 		)
 
 		// setup mocks
-		db.mock.ExpectQuery("SELECT slots, slot_qty FROM slots WHERE pool_id = ? AND epoch = ?").
+		db.mock.ExpectQuery(scheduleQuery).
 			WithArgs(pools[0].ID, epoch).
 			WillReturnError(errors.New("timeout"))
 
@@ -872,3 +1110,211 @@ func TestIsSlotEmpty(t *testing.T) {
 		require.False(t, isEmpty)
 	})
 }
+
+func TestGetLeaderSlotsInRange(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_GetLeaderSlotsInRange", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		db := setupDB(t)
+		registry := setupRegistry(t)
+		epoch := 100
+
+		slotLeaderService := NewSlotLeaderService(
+			db.db,
+			clients.cardano,
+			clients.bf, nil, pools,
+			registry.metrics,
+			0,
+		)
+
+		// setup mocks
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000, 1500, 2000)
+
+		slots, err := slotLeaderService.GetLeaderSlotsInRange(context.Background(), pools[0].ID, epoch, 1000, 1999)
+		require.NoError(t, err)
+		require.Equal(t, []int{1000, 1500}, slots)
+	})
+
+	t.Run("GoodPath_GetLeaderSlotsInRange_NoSlotInRange", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		db := setupDB(t)
+		registry := setupRegistry(t)
+		epoch := 100
+
+		slotLeaderService := NewSlotLeaderService(
+			db.db,
+			clients.cardano,
+			clients.bf, nil, pools,
+			registry.metrics,
+			0,
+		)
+
+		// setup mocks
+		// the schedule exists but has no slot in the range
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000, 2000)
+
+		slots, err := slotLeaderService.GetLeaderSlotsInRange(context.Background(), pools[0].ID, epoch, 2001, 3000)
+		require.NoError(t, err)
+		require.Empty(t, slots)
+	})
+
+	t.Run("SadPath_GetLeaderSlotsInRange_NoSchedule", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		db := setupDB(t)
+		registry := setupRegistry(t)
+		epoch := 100
+
+		slotLeaderService := NewSlotLeaderService(
+			db.db,
+			clients.cardano,
+			clients.bf, nil, pools,
+			registry.metrics,
+			0,
+		)
+
+		// setup mocks
+		db.mock.ExpectQuery(scheduleQuery).
+			WithArgs(pools[0].ID, epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "epoch", "pool_id", "slot_qty", "hash"}))
+
+		slots, err := slotLeaderService.GetLeaderSlotsInRange(context.Background(), pools[0].ID, epoch, 1000, 1999)
+		require.ErrorIs(t, err, ErrScheduleNotFound)
+		require.Nil(t, slots)
+	})
+
+	t.Run("SadPath_GetLeaderSlotsInRange_UnableToGetSlots", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		db := setupDB(t)
+		registry := setupRegistry(t)
+		epoch := 100
+
+		slotLeaderService := NewSlotLeaderService(
+			db.db,
+			clients.cardano,
+			clients.bf, nil, pools,
+			registry.metrics,
+			0,
+		)
+
+		// setup mocks
+		db.mock.ExpectQuery(scheduleQuery).
+			WithArgs(pools[0].ID, epoch).
+			WillReturnError(errors.New("timeout"))
+
+		slots, err := slotLeaderService.GetLeaderSlotsInRange(context.Background(), pools[0].ID, epoch, 1000, 1999)
+		require.ErrorContains(t, err, "unable to get slot leaders for pool")
+		require.Nil(t, slots)
+	})
+}
+
+func TestScheduleCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_LookupsAreServedFromCache", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		db := setupDB(t)
+		registry := setupRegistry(t)
+		epoch := 100
+
+		slotLeaderService := NewSlotLeaderService(
+			db.db,
+			clients.cardano,
+			clients.bf, nil, pools,
+			registry.metrics,
+			0,
+		)
+
+		// setup mocks
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000, 2000)
+
+		slots, err := slotLeaderService.GetLeaderSlotsInRange(context.Background(), pools[0].ID, epoch, 0, 1500)
+		require.NoError(t, err)
+		require.Equal(t, []int{1000}, slots)
+
+		isLeader, err := slotLeaderService.IsSlotLeader(context.Background(), pools[0].ID, 2000, epoch)
+		require.NoError(t, err)
+		require.True(t, isLeader)
+
+		nextSlot, err := slotLeaderService.GetNextSlotLeader(context.Background(), pools[0].ID, 1000, epoch)
+		require.NoError(t, err)
+		require.Equal(t, 2000, nextSlot)
+
+		isEmpty, err := slotLeaderService.IsSlotsEmpty(context.Background(), pools[0].ID, epoch)
+		require.NoError(t, err)
+		require.False(t, isEmpty)
+
+		require.NoError(t, db.mock.ExpectationsWereMet())
+		require.InDelta(t, 1.0, testutil.ToFloat64(registry.metrics.SlotScheduleCacheMisses), 0.0001)
+		require.InDelta(t, 3.0, testutil.ToFloat64(registry.metrics.SlotScheduleCacheHits), 0.0001)
+	})
+
+	t.Run("GoodPath_RefreshCurrentInvalidatesPreviousEpochs", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		db := setupDB(t)
+		registry := setupRegistry(t)
+		epoch := 100
+
+		slotLeaderService := NewSlotLeaderService(
+			db.db,
+			clients.cardano,
+			clients.bf, nil, pools,
+			registry.metrics,
+			0,
+		)
+
+		// setup mocks
+		expectSchedule(db.mock, pools[0].ID, epoch-1, 500)
+		_, err := slotLeaderService.GetSlotLeaders(context.Background(), pools[0].ID, epoch-1)
+		require.NoError(t, err)
+
+		clients.bf.EXPECT().
+			GetEpochParameters(mock.Anything, epoch).
+			Return(blockfrost.EpochParameters{Epoch: epoch, Nonce: "nonce"}, nil)
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
+			WithArgs(pools[0].ID, epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		expectSchedule(db.mock, pools[0].ID, epoch, 1000, 2000)
+		db.mock.ExpectQuery(leaderLogQuery).
+			WithArgs(pools[0].ID, epoch).
+			WillReturnError(sql.ErrNoRows)
+
+		expectRefreshStatus(db.mock, pools[0].ID, epoch, RefreshStatusOK)
+
+		err = slotLeaderService.RefreshCurrent(context.Background(), blockfrost.Epoch{Epoch: epoch})
+		require.NoError(t, err)
+
+		// the schedule of the new epoch is cached by the refresh
+		slots, err := slotLeaderService.GetLeaderSlotsInRange(context.Background(), pools[0].ID, epoch, 0, 5000)
+		require.NoError(t, err)
+		require.Equal(t, []int{1000, 2000}, slots)
+
+		// the schedule of the previous epoch is loaded again
+		expectSchedule(db.mock, pools[0].ID, epoch-1, 500)
+		slots, err = slotLeaderService.GetLeaderSlotsInRange(context.Background(), pools[0].ID, epoch-1, 0, 5000)
+		require.NoError(t, err)
+		require.Equal(t, []int{500}, slots)
+
+		require.NoError(t, db.mock.ExpectationsWereMet())
+		require.InDelta(t, 3.0, testutil.ToFloat64(registry.metrics.SlotScheduleCacheMisses), 0.0001)
+		require.InDelta(t, 1.0, testutil.ToFloat64(registry.metrics.SlotScheduleCacheHits), 0.0001)
+	})
+}
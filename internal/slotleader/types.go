@@ -2,14 +2,15 @@ package slotleader
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
+	"database/sql"
 	"log/slog"
+	"time"
 
 	bfAPI "github.com/blockfrost/blockfrost-go"
 	"github.com/jmoiron/sqlx"
 	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
 	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
 	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
 	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
 )
@@ -20,6 +21,7 @@ type SlotLeader interface {
 	IsSlotsEmpty(ctx context.Context, PoolID string, epoch int) (bool, error)
 	GetSlotLeaders(ctx context.Context, PoolID string, epoch int) (Schedule, error)
 	GetNextSlotLeader(ctx context.Context, PoolID string, height int, epoch int) (int, error)
+	GetLeaderSlotsInRange(ctx context.Context, PoolID string, epoch int, startSlot int, endSlot int) ([]int, error)
 }
 
 type Service struct {
@@ -28,31 +30,58 @@ type Service struct {
 	pools       pools.Pools
 	cardano     cardano.CardanoClient
 	blockfrost  blockfrost.Client
+	timeline    *cardanotime.Timeline
 	metrics     *metrics.Collection
 	concurrency int
+	cache       *scheduleCache
+	retries     *refreshRetries
+	vrfKeys     *vrfKeyChecks
+	cipher      *ScheduleCipher
+
+	retryMinBackoff time.Duration
+	retryMaxBackoff time.Duration
 }
 
-type slots []int
+// Schedule holds the slots assigned to a pool in an epoch. The slots are
+// stored one row per slot in the leader_slots table.
 type Schedule struct {
 	ID       int    `db:"id"`
 	Epoch    int    `db:"epoch"`
 	PoolID   string `db:"pool_id"`
 	Quantity int    `db:"slot_qty"`
-	Slots    slots  `db:"slots"`
+	Slots    []int  `db:"-"`
 	Hash     string `db:"hash"`
 }
 
-// Scan implements the sql.Scanner interface to convert
-// the text representation of the slots to a slice of integers
-//
-//nolint:wrapcheck
-func (s *slots) Scan(value interface{}) error {
-	switch v := value.(type) {
-	case []byte:
-		return json.Unmarshal(v, s)
-	case string:
-		return json.Unmarshal([]byte(v), s)
-	default:
-		return errors.New("type assertion to []byte or string failed")
+// LeaderLog holds the complete leader logs computed for a pool in an epoch,
+// including the wall-clock time of each assigned slot.
+type LeaderLog struct {
+	ID               int                    `db:"id"`
+	Epoch            int                    `db:"epoch"`
+	PoolID           string                 `db:"pool_id"`
+	EpochNonce       string                 `db:"epoch_nonce"`
+	Consensus        string                 `db:"consensus"`
+	EpochSlots       int                    `db:"epoch_slots"`
+	EpochSlotsIdeal  float64                `db:"epoch_slots_ideal"`
+	MaxPerformance   float64                `db:"max_performance"`
+	Sigma            float64                `db:"sigma"`
+	ActiveStake      int                    `db:"active_stake"`
+	TotalActiveStake int                    `db:"total_active_stake"`
+	AssignedSlots    []cardano.SlotSchedule `db:"-"`
+}
+
+// Luck returns the ratio of assigned slots to the slots expected from the
+// stake of the pool, 1 meaning the pool got as many slots as expected.
+func (l LeaderLog) Luck() float64 {
+	if l.EpochSlotsIdeal == 0 {
+		return 0
 	}
+	return float64(l.EpochSlots) / l.EpochSlotsIdeal
+}
+
+// leaderSlot is a row of the leader_slots table.
+type leaderSlot struct {
+	Slot        int          `db:"slot"`
+	SlotInEpoch int          `db:"slot_in_epoch"`
+	At          sql.NullTime `db:"at"`
 }
@@ -0,0 +1,147 @@
+package slotleader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/praos"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+)
+
+// DefaultVRFKeyCheckInterval is the default interval between two verifications
+// of the VRF keys of the pools.
+const DefaultVRFKeyCheckInterval = time.Hour
+
+// vrfKeyChecks holds the outcome of the last verification of the VRF key of
+// each pool, keyed by pool ID.
+type vrfKeyChecks struct {
+	mu sync.Mutex
+	// onChain is the last vrf_key seen on-chain for each pool
+	onChain map[string]string
+	// errs holds the pools whose VRF key failed the verification
+	errs map[string]error
+}
+
+func newVRFKeyChecks() *vrfKeyChecks {
+	return &vrfKeyChecks{
+		onChain: make(map[string]string),
+		errs:    make(map[string]error),
+	}
+}
+
+// err returns the error of the last verification of the VRF key of a pool.
+// It returns nil when the key was not verified yet.
+func (c *vrfKeyChecks) err(poolID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.errs[poolID]
+}
+
+func (c *vrfKeyChecks) set(poolID string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		delete(c.errs, poolID)
+		return
+	}
+	c.errs[poolID] = err
+}
+
+// observe records the vrf_key registered on-chain for a pool. It returns the
+// previous one when it changed.
+func (c *vrfKeyChecks) observe(poolID string, vrfKey string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous, ok := c.onChain[poolID]
+	c.onChain[poolID] = vrfKey
+	return previous, ok && previous != vrfKey
+}
+
+// VerifyVRFKeys checks that the VRF signing key of each active pool matches
+// the vrf_key registered on-chain for the pool. The pools whose key does not
+// match fail to refresh their schedule until their key is fixed.
+func (s *Service) VerifyVRFKeys(ctx context.Context) error {
+	var errs []error
+	for _, pool := range s.pools.GetActivePools() {
+		if err := s.verifyVRFKey(ctx, pool); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) verifyVRFKey(ctx context.Context, pool pools.Pool) error {
+	poolInfo, err := s.blockfrost.GetPoolInfo(ctx, pool.ID)
+	if err != nil {
+		// The outcome of the previous verification is kept while the provider is unavailable
+		return fmt.Errorf("unable to fetch pool info for %s: %w", pool.ID, err)
+	}
+	onChainKey := strings.ToLower(poolInfo.VrfKey)
+
+	gauge := s.metrics.VRFKeyMatch.WithLabelValues(pool.Name, pool.ID, pool.Instance)
+	if previous, rotated := s.vrfKeys.observe(pool.ID, onChainKey); rotated {
+		s.metrics.VRFKeyRotations.WithLabelValues(pool.Name, pool.ID, pool.Instance).Inc()
+		s.logger.WarnContext(ctx,
+			fmt.Sprintf("🔑 vrf key of pool %s rotated on-chain", pool.Name),
+			slog.String("pool_id", pool.ID),
+			slog.String("previous_vrf_key", previous),
+			slog.String("vrf_key", onChainKey),
+		)
+	}
+
+	key, err := praos.ReadVRFSigningKey(pool.Key)
+	if err != nil {
+		err = fmt.Errorf("unable to verify vrf key of pool %s: %w", pool.Name, err)
+		s.vrfKeys.set(pool.ID, err)
+		gauge.Set(0)
+		return err
+	}
+
+	if key.KeyHash() != onChainKey {
+		err = fmt.Errorf("%w: pool %s signs with %s (%s) but %s is registered on-chain",
+			ErrVRFKeyMismatch, pool.Name, key.KeyHash(), pool.Key, onChainKey,
+		)
+		s.vrfKeys.set(pool.ID, err)
+		gauge.Set(0)
+		s.logger.ErrorContext(ctx,
+			fmt.Sprintf("🚨 vrf key of pool %s does not match the one registered on-chain", pool.Name),
+			slog.String("pool_id", pool.ID),
+			slog.String("key", pool.Key),
+			slog.String("key_hash", key.KeyHash()),
+			slog.String("vrf_key", onChainKey),
+		)
+		return err
+	}
+
+	s.vrfKeys.set(pool.ID, nil)
+	gauge.Set(1)
+	return nil
+}
+
+// RunVRFKeyVerifier verifies the VRF keys of the pools at the given interval,
+// to detect the keys rotated on-chain.
+func (s *Service) RunVRFKeyVerifier(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.VerifyVRFKeys(ctx); err != nil {
+				s.logger.ErrorContext(ctx, "🚨 vrf-key-verifier: unable to verify vrf keys",
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package slotleader
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// vrfSigningKey is the secret key of example 10 of draft-irtf-cfrg-vrf-03.
+const vrfSigningKey = `{
+    "type": "VrfSigningKey_PraosVRF",
+    "description": "VRF Signing Key",
+    "cborHex": "58409d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+}`
+
+// vrfKeyHash is the vrf_key registered on-chain for vrfSigningKey.
+const vrfKeyHash = "7849ac3049680be1ef762efe0d36e01733c3464eb0c7c558138acf24bb263bd3"
+
+const otherVRFKeyHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+func writeVRFSigningKey(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "pool-0.vrf.skey")
+	require.NoError(t, os.WriteFile(path, []byte(vrfSigningKey), 0o600))
+	return path
+}
+
+func TestVerifyVRFKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_KeyMatchesOnChainRegistration", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		pools[0].Key = writeVRFSigningKey(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+		# HELP cardano_validator_watcher_vrf_key_match Whether the VRF signing key of the pool matches the vrf_key registered on-chain: 1 = match, 0 = mismatch
+		# TYPE cardano_validator_watcher_vrf_key_match gauge
+		cardano_validator_watcher_vrf_key_match{pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1
+		`
+		registry.metricsUnderTest = []string{"cardano_validator_watcher_vrf_key_match"}
+
+		slotLeaderService := NewSlotLeaderService(nil, clients.cardano, clients.bf, nil, pools, registry.metrics, 0)
+
+		// the on-chain vrf_key may be upper case
+		clients.bf.EXPECT().GetPoolInfo(mock.Anything, pools[0].ID).Return(blockfrost.Pool{VrfKey: "7849AC3049680BE1EF762EFE0D36E01733C3464EB0C7C558138ACF24BB263BD3"}, nil)
+
+		require.NoError(t, slotLeaderService.VerifyVRFKeys(context.Background()))
+		require.NoError(t, slotLeaderService.vrfKeys.err(pools[0].ID))
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		require.NoError(t, testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...))
+	})
+
+	t.Run("SadPath_KeyMismatchFailsTheRefreshOfThePool", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		pools[0].Key = writeVRFSigningKey(t)
+		db := setupDB(t)
+		registry := setupRegistry(t)
+		epoch := 100
+
+		registry.metricsExpectedOutput = `
+		# HELP cardano_validator_watcher_vrf_key_match Whether the VRF signing key of the pool matches the vrf_key registered on-chain: 1 = match, 0 = mismatch
+		# TYPE cardano_validator_watcher_vrf_key_match gauge
+		cardano_validator_watcher_vrf_key_match{pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 0
+		# HELP cardano_validator_watcher_slot_schedule_refresh_status Status of the last refresh of the leader schedule of the current epoch: 1 = ok, 0 = failed
+		# TYPE cardano_validator_watcher_slot_schedule_refresh_status gauge
+		cardano_validator_watcher_slot_schedule_refresh_status{pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 0
+		`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_vrf_key_match",
+			"cardano_validator_watcher_slot_schedule_refresh_status",
+		}
+
+		slotLeaderService := NewSlotLeaderService(db.db, clients.cardano, clients.bf, nil, pools, registry.metrics, 0)
+
+		clients.bf.EXPECT().GetPoolInfo(mock.Anything, pools[0].ID).Return(blockfrost.Pool{VrfKey: otherVRFKeyHash}, nil)
+
+		err := slotLeaderService.VerifyVRFKeys(context.Background())
+		require.ErrorIs(t, err, ErrVRFKeyMismatch)
+		require.ErrorContains(t, err, vrfKeyHash)
+		require.ErrorContains(t, err, otherVRFKeyHash)
+
+		// the leader logs of the pool are never computed
+		clients.bf.EXPECT().
+			GetEpochParameters(mock.Anything, epoch).
+			Return(blockfrost.EpochParameters{Epoch: epoch, Nonce: "nonce"}, nil)
+		db.mock.ExpectQuery("SELECT COUNT(*) FROM slots WHERE pool_id = ? AND epoch = ?").
+			WithArgs(pools[0].ID, epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		expectRefreshStatus(db.mock, pools[0].ID, epoch, RefreshStatusFailed)
+
+		err = slotLeaderService.RefreshCurrent(context.Background(), blockfrost.Epoch{Epoch: epoch})
+		require.ErrorIs(t, err, ErrVRFKeyMismatch)
+		require.NoError(t, db.mock.ExpectationsWereMet())
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		require.NoError(t, testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...))
+	})
+
+	t.Run("SadPath_UnreadableKey", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		pools[0].Key = filepath.Join(t.TempDir(), "missing.vrf.skey")
+		registry := setupRegistry(t)
+
+		slotLeaderService := NewSlotLeaderService(nil, clients.cardano, clients.bf, nil, pools, registry.metrics, 0)
+
+		clients.bf.EXPECT().GetPoolInfo(mock.Anything, pools[0].ID).Return(blockfrost.Pool{VrfKey: vrfKeyHash}, nil)
+
+		err := slotLeaderService.VerifyVRFKeys(context.Background())
+		require.ErrorContains(t, err, "unable to read vrf signing key")
+		require.Error(t, slotLeaderService.vrfKeys.err(pools[0].ID))
+	})
+
+	t.Run("GoodPath_DetectOnChainRotation", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		pools := setupPools(t)
+		pools[0].Key = writeVRFSigningKey(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+		# HELP cardano_validator_watcher_vrf_key_match Whether the VRF signing key of the pool matches the vrf_key registered on-chain: 1 = match, 0 = mismatch
+		# TYPE cardano_validator_watcher_vrf_key_match gauge
+		cardano_validator_watcher_vrf_key_match{pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1
+		# HELP cardano_validator_watcher_vrf_key_rotations_total Number of changes of the vrf_key registered on-chain for the pool
+		# TYPE cardano_validator_watcher_vrf_key_rotations_total counter
+		cardano_validator_watcher_vrf_key_rotations_total{pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1
+		`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_vrf_key_match",
+			"cardano_validator_watcher_vrf_key_rotations_total",
+		}
+
+		slotLeaderService := NewSlotLeaderService(nil, clients.cardano, clients.bf, nil, pools, registry.metrics, 0)
+
+		// the pool is registered with an old key, then rotates to the local one
+		clients.bf.EXPECT().GetPoolInfo(mock.Anything, pools[0].ID).Return(blockfrost.Pool{VrfKey: otherVRFKeyHash}, nil).Once()
+		clients.bf.EXPECT().GetPoolInfo(mock.Anything, pools[0].ID).Return(blockfrost.Pool{VrfKey: vrfKeyHash}, nil).Twice()
+
+		require.ErrorIs(t, slotLeaderService.VerifyVRFKeys(context.Background()), ErrVRFKeyMismatch)
+		require.NoError(t, slotLeaderService.VerifyVRFKeys(context.Background()))
+		require.NoError(t, slotLeaderService.VerifyVRFKeys(context.Background()))
+		require.NoError(t, slotLeaderService.vrfKeys.err(pools[0].ID))
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		require.NoError(t, testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...))
+	})
+}
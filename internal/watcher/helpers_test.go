@@ -8,6 +8,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	blockfrostmocks "github.com/kilnfi/cardano-validator-watcher/internal/blockfrost/mocks"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
 	cardanomocks "github.com/kilnfi/cardano-validator-watcher/internal/cardano/mocks"
 	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
 	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
@@ -57,6 +58,20 @@ func setupClients(t *testing.T) *clients {
 	}
 }
 
+func setupTimeline(t *testing.T) *cardanotime.Timeline {
+	t.Helper()
+
+	genesis := cardanotime.ShelleyGenesis{
+		SystemStart:      time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+		NetworkMagic:     1,
+		EpochLength:      432000,
+		SlotLength:       1,
+		ActiveSlotsCoeff: 0.05,
+		SecurityParam:    2160,
+	}
+	return cardanotime.NewTimeline(genesis, 0)
+}
+
 func setupDB(t *testing.T) *dbMockClient {
 	t.Helper()
 
@@ -107,3 +122,17 @@ func setupContextWithTimeout(t *testing.T, d time.Duration) context.Context {
 	}()
 	return ctx
 }
+
+// leaderSlotsInRange returns a GetLeaderSlotsInRange implementation that
+// filters the given schedule on the requested range of slots.
+func leaderSlotsInRange(slots ...int) func(context.Context, string, int, int, int) ([]int, error) {
+	return func(_ context.Context, _ string, _ int, startSlot int, endSlot int) ([]int, error) {
+		inRange := []int{}
+		for _, slot := range slots {
+			if slot >= startSlot && slot <= endSlot {
+				inRange = append(inRange, slot)
+			}
+		}
+		return inRange, nil
+	}
+}
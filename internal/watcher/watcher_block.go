@@ -5,25 +5,38 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strconv"
-	"strings"
 	"time"
 
 	bf "github.com/blockfrost/blockfrost-go"
 	"github.com/jmoiron/sqlx"
 	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
 	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
 	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
 	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
 	"github.com/kilnfi/cardano-validator-watcher/internal/slotleader"
 )
 
-// Maximum number of slots per epoch.
-const maxSlotPerEpoch = 432000
+// Units in which the confirmation depth of the block watcher can be expressed.
+const (
+	ConfirmationUnitSlots  = "slots"
+	ConfirmationUnitBlocks = "blocks"
+)
 
 // BlockWatcherOptions represents the options for the block watcher.
 type BlockWatcherOptions struct {
 	RefreshInterval time.Duration
+	// ConfirmationDepth is the distance to the tip a leader slot must reach before it is finalized.
+	ConfirmationDepth int
+	// ConfirmationUnit is the unit of ConfirmationDepth, either slots or blocks.
+	ConfirmationUnit string
+	// RecheckWindow is the number of slots behind the tip in which validated blocks are checked for rollbacks.
+	RecheckWindow int
+	// SchedulePrivacy replaces the next slot leader of the pools by the number of
+	// leader slots remaining in the epoch, in the logs and the metrics.
+	SchedulePrivacy bool
 }
 
 // BlockWatcher represents a watcher for Cardano blocks.
@@ -31,15 +44,23 @@ type BlockWatcher struct {
 	logger *slog.Logger
 
 	state             *BlockWatcherState
+	outcomes          *BlockOutcomeStore
+	backlogs          *BlockBacklogStore
+	poolBacklogs      map[string]BlockBacklog
 	cardano           cardano.CardanoClient
 	blockfrost        blockfrost.Client
 	slotLeaderService slotleader.SlotLeader
+	timeline          *cardanotime.Timeline
 	metrics           *metrics.Collection
 	pools             pools.Pools
 	poolStats         pools.PoolStats
 	db                *sqlx.DB
 	healthStore       *HealthStore
 	opts              BlockWatcherOptions
+
+	// pendingReconciliations are the reconciliations of closed epochs that failed, retried at each pass.
+	// They are restored from the database on start.
+	pendingReconciliations []pendingReconciliation
 }
 
 var _ Watcher = (*BlockWatcher)(nil)
@@ -49,6 +70,7 @@ func NewBlockWatcher(
 	cardano cardano.CardanoClient,
 	blockfrost blockfrost.Client,
 	slotLeader slotleader.SlotLeader,
+	timeline *cardanotime.Timeline,
 	pools pools.Pools,
 	metrics *metrics.Collection,
 	db *sqlx.DB,
@@ -65,10 +87,14 @@ func NewBlockWatcher(
 		cardano:           cardano,
 		blockfrost:        blockfrost,
 		slotLeaderService: slotLeader,
+		timeline:          timeline,
 		metrics:           metrics,
 		pools:             pools,
 		poolStats:         pools.GetPoolStats(),
 		state:             state,
+		outcomes:          NewBlockOutcomeStore(db),
+		backlogs:          NewBlockBacklogStore(db),
+		poolBacklogs:      make(map[string]BlockBacklog),
 		db:                db,
 		healthStore:       healthStore,
 		opts:              opts,
@@ -86,6 +112,11 @@ func (w *BlockWatcher) Start(ctx context.Context) error {
 	// Initialize metrics
 	w.initMetrics()
 
+	// Restore the block counters of the current epoch from the recorded outcomes
+	if err := w.restoreMetrics(ctx); err != nil {
+		return fmt.Errorf("failed to restore block metrics: %w", err)
+	}
+
 	// Ensure that each pool has leader slots assigned
 	if err := w.ensurePoolHasLeaderSlots(ctx); err != nil {
 		var noSlotsFound *ErrNoSlotsAssignedToPool
@@ -110,10 +141,6 @@ func (w *BlockWatcher) Start(ctx context.Context) error {
 		if currentHealthStatus {
 			err := w.start(ctx)
 			if err != nil {
-				var slotLeaderRefreshError *slotleader.ErrSlotLeaderRefresh
-				if errors.As(err, &slotLeaderRefreshError) {
-					return err
-				}
 				w.logger.ErrorContext(ctx, "watcher started but failed with the following error", slog.String("error", err.Error()))
 			}
 		}
@@ -158,6 +185,21 @@ func (w *BlockWatcher) initState(ctx context.Context) error {
 	}
 	w.logger.InfoContext(ctx, "State loaded and reconciled successfully", slog.Int("epoch", epoch.Epoch))
 
+	// Restore the slots skipped by the pools degraded before the restart
+	backlogs, err := w.backlogs.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load the backlogs: %w", err)
+	}
+	for _, backlog := range backlogs {
+		w.poolBacklogs[backlog.PoolID] = backlog
+	}
+	w.dropBacklogs(ctx, w.state.Epoch-1)
+
+	// Restore the reconciliations of the closed epochs that failed before the restart
+	if err := w.loadPendingReconciliations(ctx); err != nil {
+		return fmt.Errorf("failed to load the pending reconciliations: %w", err)
+	}
+
 	return nil
 }
 
@@ -181,6 +223,16 @@ func (w *BlockWatcher) start(ctx context.Context) error {
 		return err
 	}
 
+	// Revisit the recently validated blocks to detect the ones that have been rolled back
+	if err := w.recheckValidatedBlocks(ctx, block); err != nil {
+		return fmt.Errorf("failed to re-check validated blocks: %w", err)
+	}
+
+	// Retry the reconciliations of the closed epochs that failed or whose blocks have been reclassified
+	if err := w.retryReconciliations(ctx); err != nil {
+		w.logger.ErrorContext(ctx, "failed to reconcile the closed epochs, retrying at the next pass", slog.String("error", err.Error()))
+	}
+
 	// We have detected a new epoch and we want to update the state to the new epoch
 	// and refresh the slot leader schedule for each pools
 	if epochTransition {
@@ -213,9 +265,11 @@ func (w *BlockWatcher) ensurePoolHasLeaderSlots(ctx context.Context) error {
 }
 
 // handleEpochtransition handles the epoch transition.
-// It updates the state to the new epoch and refreshes the slot leader schedule for each pool.
+// It updates the state to the new epoch, refreshes the slot leader schedule for each pool
+// and reconciles the closed epoch with the blocks forged on-chain.
 // It returns an error if the epoch transition fails.
 func (w *BlockWatcher) handleEpochtransition(ctx context.Context) error {
+	closedEpoch := w.state.Epoch
 	epoch, err := w.blockfrost.GetLatestEpoch(ctx)
 	if err != nil {
 		return fmt.Errorf("handleEpochTransition: failed to get latest epoch: %w", err)
@@ -225,10 +279,26 @@ func (w *BlockWatcher) handleEpochtransition(ctx context.Context) error {
 		return fmt.Errorf("handleEpochTransition: failed to save state after epoch transition: %w", err)
 	}
 
+	// The pools still degraded at the end of the closed epoch never processed their skipped slots
+	w.dropBacklogs(ctx, closedEpoch)
+
 	// Update the slot leader schedule for each pool
 	w.logger.InfoContext(ctx, "🔄 Refreshing slot leader schedule for the new epoch", slog.Int("epoch", epoch.Epoch))
+	// The pools that failed to refresh are degraded and retried in the background,
+	// the other pools keep being monitored.
 	if err := w.slotLeaderService.RefreshCurrent(ctx, epoch); err != nil {
-		return fmt.Errorf("handleEpochTransition: failed to refresh slot leader schedule for the new epoch: %w", err)
+		w.logger.ErrorContext(ctx, "handleEpochTransition: failed to refresh slot leader schedule for the new epoch",
+			slog.Int("epoch", epoch.Epoch),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	// Compare the closed epoch with the blocks forged on-chain
+	if err := w.reconcileEpoch(ctx, closedEpoch); err != nil {
+		w.logger.ErrorContext(ctx, "handleEpochTransition: failed to reconcile the closed epoch",
+			slog.Int("epoch", closedEpoch),
+			slog.String("error", err.Error()),
+		)
 	}
 
 	// Ensure that each pool has leader slots assigned
@@ -252,8 +322,14 @@ func (w *BlockWatcher) startWatcherAndDetectEpochTransition(ctx context.Context,
 	var startSlot, endSlot int
 	var epochTransition bool
 
+	// Only the slots that are deep enough behind the tip are finalized
+	confirmedSlot, err := w.getConfirmedSlot(ctx, block)
+	if err != nil {
+		return false, fmt.Errorf("startWatcherAndDetectEpochTransition: %w", err)
+	}
+
 	startSlot = w.state.Slot + 1
-	endSlot = block.Slot
+	endSlot = confirmedSlot
 
 	// Log the start and end slots for debugging purposes
 	w.logger.DebugContext(ctx, "start slot", slog.Int("slot", startSlot))
@@ -266,17 +342,22 @@ func (w *BlockWatcher) startWatcherAndDetectEpochTransition(ctx context.Context,
 	epochTransition = block.Epoch > w.state.Epoch
 	if epochTransition {
 		w.logger.InfoContext(ctx, "🚀 A new epoch has started.", slog.Int("epoch", block.Epoch))
-		// Each epoch has a maximum of 432000 slots. However, due to reasons like empty or missed slots,
-		// an epoch might end with fewer slots. To ensure no slot is missed, we process all slots from the previous epoch.
-		//
-		// The last slot of the current epoch is calculated by adding the number of remaining slots in the previous epoch
-		// to the slot number of the last received block in the previous epoch.
-		lastBlockInPreviousEpoch, err := w.blockfrost.GetLastBlockFromPreviousEpoch(ctx, w.state.Epoch)
-		if err != nil {
-			return epochTransition, fmt.Errorf("startWatcherAndDetectEpochTransition: failed to get last block from previous epoch: %w", err)
+		// The previous epoch might end with empty or missed slots. To ensure no slot is missed,
+		// we process all slots up to the last slot of the previous epoch derived from the genesis.
+		lastSlotInPreviousEpoch := w.timeline.LastSlotOfEpoch(w.state.Epoch)
+
+		// The end of the previous epoch is not confirmed yet, the transition is
+		// delayed until all of its slots are deep enough to be finalized.
+		if confirmedSlot < lastSlotInPreviousEpoch {
+			w.logger.InfoContext(ctx, "⏳ Waiting for the end of the previous epoch to be confirmed",
+				slog.Int("epoch", w.state.Epoch),
+				slog.Int("confirmed_slot", confirmedSlot),
+				slog.Int("last_slot", lastSlotInPreviousEpoch),
+			)
+			epochTransition = false
+		} else {
+			endSlot = lastSlotInPreviousEpoch
 		}
-		remainingSlots := maxSlotPerEpoch - lastBlockInPreviousEpoch.EpochSlot
-		endSlot = lastBlockInPreviousEpoch.Slot + remainingSlots
 	}
 
 	if startSlot <= endSlot {
@@ -299,66 +380,150 @@ func (w *BlockWatcher) startWatcherAndDetectEpochTransition(ctx context.Context,
 	return epochTransition, nil
 }
 
-// processSlots processes the slots and checks if the pool is a leader.
-func (w *BlockWatcher) processSlots(ctx context.Context, epoch int, startSlot int, endSlot int) (int, error) {
-	for slot := startSlot; slot <= endSlot; slot++ {
-		w.logger.InfoContext(ctx,
-			fmt.Sprintf(
-				"🔍 Processing slot %d - 🔑 Monitoring %d pools (%d active, %d excluded)",
-				slot,
-				w.poolStats.Total,
-				w.poolStats.Active,
-				w.poolStats.Excluded,
-			),
-			slog.Int("epoch", epoch),
-		)
+// getConfirmedSlot returns the most recent slot that is at least ConfirmationDepth slots or blocks behind the tip.
+func (w *BlockWatcher) getConfirmedSlot(ctx context.Context, tip bf.Block) (int, error) {
+	if w.opts.ConfirmationDepth <= 0 {
+		return tip.Slot, nil
+	}
 
-		for _, pool := range w.pools.GetActivePools() {
-			err := w.processPoolSlot(ctx, epoch, pool, slot)
-			if err != nil {
-				return slot, err
-			}
-		}
+	if w.opts.ConfirmationUnit == ConfirmationUnitSlots {
+		return tip.Slot - w.opts.ConfirmationDepth, nil
 	}
-	return endSlot, nil
+
+	block, err := w.blockfrost.GetBlockByHeight(ctx, tip.Height-w.opts.ConfirmationDepth)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get confirmed block at height %d: %w", tip.Height-w.opts.ConfirmationDepth, err)
+	}
+	return block.Slot, nil
+}
+
+// leaderSlot is a slot in which a monitored pool is elected as leader.
+type leaderSlot struct {
+	pool pools.Pool
+	slot int
 }
 
-// processSlot processes the slot for the given pool.
-// It returns an error if the slot processing fails.
-func (w *BlockWatcher) processPoolSlot(ctx context.Context, epoch int, pool pools.Pool, slot int) error {
-	// check if pool has slots assigned.
-	empty, err := w.slotLeaderService.IsSlotsEmpty(ctx, pool.ID, epoch)
+// processSlots processes the leader slots of the active pools that fall inside [startSlot, endSlot].
+// The leader slots of each pool in the range are loaded once per pass, so slots where no monitored
+// pool is leader are skipped without any lookup. It returns the latest slot that has been fully processed.
+func (w *BlockWatcher) processSlots(ctx context.Context, epoch int, startSlot int, endSlot int) (int, error) {
+	start := time.Now()
+	defer func() {
+		w.metrics.BlockWatcherProcessingDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	leaderSlots, recovered, err := w.getLeaderSlotsInRange(ctx, epoch, startSlot, endSlot)
 	if err != nil {
-		return fmt.Errorf("processPoolSlot: failed to check if pool has leader slots: %w", err)
+		return startSlot - 1, err
 	}
-	if empty && pool.AllowEmptySlots {
+
+	w.logger.InfoContext(ctx,
+		fmt.Sprintf(
+			"🔍 Processing slots %d to %d - %d leader slots found - 🔑 Monitoring %d pools (%d active, %d excluded)",
+			startSlot,
+			endSlot,
+			len(leaderSlots),
+			w.poolStats.Total,
+			w.poolStats.Active,
+			w.poolStats.Excluded,
+		),
+		slog.Int("epoch", epoch),
+		slog.Int("slots", endSlot-startSlot+1),
+	)
+
+	for _, leaderSlot := range leaderSlots {
+		if err := w.processLeaderSlot(ctx, epoch, leaderSlot.pool, leaderSlot.slot); err != nil {
+			// The slot is not marked as processed so it is retried on the next pass,
+			// the slots of a backlog are behind the state which is not moved back.
+			return max(leaderSlot.slot-1, startSlot-1), err
+		}
+	}
+
+	// The skipped slots of the recovered pools have all been processed
+	for _, pool := range recovered {
+		if err := w.backlogs.Delete(ctx, pool.ID); err != nil {
+			return endSlot, fmt.Errorf("processSlots: %w", err)
+		}
+		delete(w.poolBacklogs, pool.ID)
 		w.logger.InfoContext(ctx,
-			fmt.Sprintf("🦄 pool %s has no leader slots assigned but allow empty slots is enabled", pool.Name),
-			slog.Int("epoch", epoch),
+			fmt.Sprintf("✅ skipped slots of pool %s processed", pool.Name),
 			slog.String("pool_id", pool.ID),
+			slog.Int("epoch", epoch),
 		)
-		return nil
 	}
+	return endSlot, nil
+}
 
-	// check if we are leader on the slot and handle it.
-	isLeader, err := w.slotLeaderService.IsSlotLeader(ctx, pool.ID, slot, epoch)
-	if err != nil {
-		return fmt.Errorf("processPoolSlot: failed to check if pool is leader for slot: %w", err)
-	}
-	if isLeader {
-		if err := w.processLeaderSlot(ctx, epoch, pool, slot); err != nil {
-			return err
+// getLeaderSlotsInRange returns the leader slots of the active pools inside [startSlot, endSlot], ordered by slot.
+// A pool without schedule is skipped and its backlog starts at startSlot. Once its schedule is known, its leader
+// slots are returned from the start of its backlog and the pool is returned as recovered.
+func (w *BlockWatcher) getLeaderSlotsInRange(ctx context.Context, epoch int, startSlot int, endSlot int) ([]leaderSlot, []pools.Pool, error) {
+	leaderSlots := []leaderSlot{}
+	recovered := []pools.Pool{}
+	for _, pool := range w.pools.GetActivePools() {
+		fromSlot := startSlot
+		backlog, inBacklog := w.poolBacklogs[pool.ID]
+		if inBacklog && backlog.Epoch == epoch {
+			fromSlot = min(backlog.FirstSlot, startSlot)
+		}
+
+		slots, err := w.slotLeaderService.GetLeaderSlotsInRange(ctx, pool.ID, epoch, fromSlot, endSlot)
+		if errors.Is(err, slotleader.ErrScheduleNotFound) {
+			// The schedule of a degraded pool is not known until its refresh succeeds
+			w.logger.WarnContext(ctx,
+				fmt.Sprintf("⚠️ no slot leader schedule for pool %s, its slots are processed once it is refreshed", pool.Name),
+				slog.String("pool_id", pool.ID),
+				slog.Int("epoch", epoch),
+				slog.Int("first_skipped_slot", fromSlot),
+			)
+			if inBacklog && backlog.Epoch == epoch {
+				continue
+			}
+			backlog = BlockBacklog{PoolID: pool.ID, Epoch: epoch, FirstSlot: startSlot}
+			if err := w.backlogs.Save(ctx, backlog); err != nil {
+				return nil, nil, fmt.Errorf("processSlots: %w", err)
+			}
+			w.poolBacklogs[pool.ID] = backlog
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("processSlots: failed to get leader slots for pool %s: %w", pool.ID, err)
+		}
+		if inBacklog && backlog.Epoch == epoch {
+			recovered = append(recovered, pool)
+		}
+
+		for _, slot := range slots {
+			leaderSlots = append(leaderSlots, leaderSlot{pool: pool, slot: slot})
 		}
-		return nil
 	}
 
-	// We are not leader for the slot
-	w.logger.InfoContext(ctx,
-		fmt.Sprintf("😴 pool %s is not a leader for slot %d", pool.Name, slot),
-		slog.Int("epoch", epoch),
-		slog.String("pool_id", pool.ID),
-	)
-	return nil
+	sort.SliceStable(leaderSlots, func(i, j int) bool {
+		return leaderSlots[i].slot < leaderSlots[j].slot
+	})
+	return leaderSlots, recovered, nil
+}
+
+// dropBacklogs gives up the backlogs of the pools up to the given epoch. Their
+// epoch is closed so the skipped slots are left to the epoch reconciliation.
+func (w *BlockWatcher) dropBacklogs(ctx context.Context, epoch int) {
+	for _, pool := range w.pools.GetActivePools() {
+		backlog, ok := w.poolBacklogs[pool.ID]
+		if !ok || backlog.Epoch > epoch {
+			continue
+		}
+
+		w.logger.ErrorContext(ctx,
+			fmt.Sprintf("🚨 slots of pool %s from slot %d to the end of epoch %d were not processed, its schedule was never refreshed", pool.Name, backlog.FirstSlot, backlog.Epoch),
+			slog.String("pool_id", pool.ID),
+			slog.Int("epoch", backlog.Epoch),
+		)
+		if err := w.backlogs.Delete(ctx, pool.ID); err != nil {
+			w.logger.ErrorContext(ctx, "failed to delete the backlog", slog.String("pool_id", pool.ID), slog.String("error", err.Error()))
+			continue
+		}
+		delete(w.poolBacklogs, pool.ID)
+	}
 }
 
 // handleSlotLeader handles the slot leader and check the state of the slot.
@@ -374,14 +539,38 @@ func (w *BlockWatcher) processLeaderSlot(ctx context.Context, epoch int, pool po
 	)
 
 	block, err := w.blockfrost.GetBlockBySlot(ctx, slot)
+	if err != nil && !errors.Is(err, blockfrost.ErrNotFound) {
+		return fmt.Errorf("processLeaderSlot: failed to fetch block on slot %d: %w", slot, err)
+	}
+
+	outcome := BlockOutcomeOrphaned
 	switch {
 	case err != nil:
-		if strings.Contains(strings.ToLower(err.Error()), "not found") {
-			w.logMissedBlock(ctx, pool, slot, epoch)
-		} else {
-			return fmt.Errorf("processLeaderSlot: failed to fetch block on slot %d: %w", slot, err)
-		}
+		outcome = BlockOutcomeMissed
+		block = bf.Block{}
 	case block.SlotLeader == pool.ID:
+		outcome = BlockOutcomeValidated
+	}
+
+	// A slot is processed again for every pool after a failed pass, the pools
+	// whose outcome is already recorded are not counted twice.
+	created, err := w.createOutcome(ctx, pool, slot, epoch, outcome, block)
+	if err != nil {
+		return err
+	}
+	if !created {
+		w.logger.DebugContext(ctx,
+			fmt.Sprintf("outcome of pool %s for slot %d already recorded", pool.Name, slot),
+			slog.Int("epoch", epoch),
+			slog.String("pool_id", pool.ID),
+		)
+		return nil
+	}
+
+	switch outcome {
+	case BlockOutcomeMissed:
+		w.logMissedBlock(ctx, pool, slot, epoch)
+	case BlockOutcomeValidated:
 		w.logValidatedBlock(ctx, pool, slot, epoch, block)
 	default:
 		w.logOrphanedBlock(ctx, pool, slot, epoch, block)
@@ -389,6 +578,102 @@ func (w *BlockWatcher) processLeaderSlot(ctx context.Context, epoch int, pool po
 	return nil
 }
 
+// recheckValidatedBlocks revisits the validated blocks recorded within the re-check window
+// and reclassifies them as orphaned when the block is no longer on-chain.
+// The block counters are rebuilt when at least one block has been rolled back,
+// and the closed epochs with a reclassified block are reconciled again.
+func (w *BlockWatcher) recheckValidatedBlocks(ctx context.Context, tip bf.Block) error {
+	if w.opts.RecheckWindow <= 0 {
+		return nil
+	}
+
+	records, err := w.outcomes.ListByOutcomeSince(ctx, BlockOutcomeValidated, tip.Slot-w.opts.RecheckWindow)
+	if err != nil {
+		return err
+	}
+
+	activePools := make(map[string]pools.Pool)
+	for _, pool := range w.pools.GetActivePools() {
+		activePools[pool.ID] = pool
+	}
+
+	rolledBack := 0
+	for _, record := range records {
+		pool, ok := activePools[record.PoolID]
+		if !ok {
+			continue
+		}
+
+		block, err := w.blockfrost.GetBlockBySlot(ctx, record.Slot)
+		if err != nil && !errors.Is(err, blockfrost.ErrNotFound) {
+			return fmt.Errorf("failed to fetch block on slot %d: %w", record.Slot, err)
+		}
+
+		switch {
+		case err == nil && block.Hash == record.BlockHash:
+			continue
+		case err == nil && block.SlotLeader == pool.ID:
+			// The pool forged the slot again after a fork switch, the block is still ours.
+			if err := w.saveOutcome(ctx, pool, record.Slot, record.Epoch, BlockOutcomeValidated, block); err != nil {
+				return err
+			}
+			w.queueReconciliation(record.Epoch, pool)
+		default:
+			if err := w.saveOutcome(ctx, pool, record.Slot, record.Epoch, BlockOutcomeOrphaned, block); err != nil {
+				return err
+			}
+			w.queueReconciliation(record.Epoch, pool)
+			w.logger.WarnContext(ctx,
+				fmt.Sprintf("🔙 Block of pool %s for slot %d has been rolled back", pool.Name, record.Slot),
+				slog.Int("epoch", record.Epoch),
+				slog.String("pool_id", pool.ID),
+				slog.String("block_hash", record.BlockHash),
+			)
+			rolledBack++
+		}
+	}
+
+	if rolledBack > 0 {
+		w.initMetrics()
+		if err := w.restoreMetrics(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newOutcomeRecord builds the record of the outcome of a leader slot.
+func newOutcomeRecord(pool pools.Pool, slot, epoch int, outcome BlockOutcome, block bf.Block) BlockOutcomeRecord {
+	return BlockOutcomeRecord{
+		Epoch:       epoch,
+		PoolID:      pool.ID,
+		Slot:        slot,
+		Outcome:     outcome,
+		BlockHash:   block.Hash,
+		BlockHeight: block.Height,
+		SlotLeader:  block.SlotLeader,
+		RecordedAt:  time.Now(),
+	}
+}
+
+// createOutcome persists the first outcome of a leader slot and reports whether it was recorded.
+// The outcome is saved before the metrics are updated so a failure leaves the slot unprocessed.
+func (w *BlockWatcher) createOutcome(ctx context.Context, pool pools.Pool, slot, epoch int, outcome BlockOutcome, block bf.Block) (bool, error) {
+	created, err := w.outcomes.Create(ctx, newOutcomeRecord(pool, slot, epoch, outcome, block))
+	if err != nil {
+		return false, fmt.Errorf("processLeaderSlot: failed to save %s outcome for slot %d: %w", outcome, slot, err)
+	}
+	return created, nil
+}
+
+// saveOutcome persists the outcome of a leader slot, overwriting the previous one.
+func (w *BlockWatcher) saveOutcome(ctx context.Context, pool pools.Pool, slot, epoch int, outcome BlockOutcome, block bf.Block) error {
+	if err := w.outcomes.Save(ctx, newOutcomeRecord(pool, slot, epoch, outcome, block)); err != nil {
+		return fmt.Errorf("recheckValidatedBlocks: failed to save %s outcome for slot %d: %w", outcome, slot, err)
+	}
+	return nil
+}
+
 // logMissedBlock records a missed block in metrics and logs the occurrence.
 func (w *BlockWatcher) logMissedBlock(ctx context.Context, pool pools.Pool, slot, epoch int) {
 	w.logger.InfoContext(ctx, fmt.Sprintf("❌ Pool %s missed block for slot %d", pool.Name, slot),
@@ -428,12 +713,19 @@ func (w *BlockWatcher) logOrphanedBlock(ctx context.Context, pool pools.Pool, sl
 // fetchAndLogNextSlotLeaders fetches and displays the next slot leaders for each pool.
 // and expose the next slot leader as a metric.
 func (w *BlockWatcher) fetchAndLogNextSlotLeaders(ctx context.Context, block bf.Block) error {
+	if w.opts.SchedulePrivacy {
+		return w.fetchAndLogRemainingLeaderSlots(ctx, block)
+	}
+
 	for _, pool := range w.pools.GetActivePools() {
 		if pool.AllowEmptySlots {
 			continue
 		}
 		var remainingSlots int
 		nextSlot, err := w.slotLeaderService.GetNextSlotLeader(ctx, pool.ID, block.Slot, w.state.Epoch)
+		if errors.Is(err, slotleader.ErrScheduleNotFound) {
+			continue
+		}
 		if err != nil {
 			return fmt.Errorf("failed to get next slot leader: %w", err)
 		}
@@ -456,6 +748,32 @@ func (w *BlockWatcher) fetchAndLogNextSlotLeaders(ctx context.Context, block bf.
 	return nil
 }
 
+// fetchAndLogRemainingLeaderSlots displays the number of leader slots remaining
+// in the epoch for each pool and exposes it as a metric. Unlike the next slot
+// leader, it does not tell when the pools are about to forge a block.
+func (w *BlockWatcher) fetchAndLogRemainingLeaderSlots(ctx context.Context, block bf.Block) error {
+	for _, pool := range w.pools.GetActivePools() {
+		if pool.AllowEmptySlots {
+			continue
+		}
+		slots, err := w.slotLeaderService.GetLeaderSlotsInRange(ctx, pool.ID, w.state.Epoch, block.Slot+1, w.timeline.LastSlotOfEpoch(w.state.Epoch))
+		if errors.Is(err, slotleader.ErrScheduleNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get remaining leader slots: %w", err)
+		}
+
+		w.logger.InfoContext(ctx,
+			fmt.Sprintf("🕰  Pool %s has %d leader slots remaining in the epoch", pool.Name, len(slots)),
+			slog.String("pool_id", pool.ID),
+			slog.Int("epoch", w.state.Epoch),
+		)
+		w.metrics.RemainingLeaderSlots.WithLabelValues(pool.Name, pool.ID, pool.Instance, strconv.Itoa(w.state.Epoch)).Set(float64(len(slots)))
+	}
+	return nil
+}
+
 // initMetrics initializes the metrics for the block watcher.
 func (w *BlockWatcher) initMetrics() {
 	w.metrics.MissedBlocks.Reset()
@@ -469,3 +787,37 @@ func (w *BlockWatcher) initMetrics() {
 		w.metrics.OrphanedBlocks.WithLabelValues(pool.Name, pool.ID, pool.Instance, strconv.Itoa(w.state.Epoch)).Add(0)
 	}
 }
+
+// restoreMetrics rebuilds the block counters of the current epoch from the outcomes
+// recorded in the database, so they survive a restart of the watcher.
+func (w *BlockWatcher) restoreMetrics(ctx context.Context) error {
+	counts, err := w.outcomes.CountByEpoch(ctx, w.state.Epoch)
+	if err != nil {
+		return err
+	}
+
+	activePools := make(map[string]pools.Pool)
+	for _, pool := range w.pools.GetActivePools() {
+		activePools[pool.ID] = pool
+	}
+
+	for _, count := range counts {
+		pool, ok := activePools[count.PoolID]
+		if !ok {
+			continue
+		}
+
+		labels := []string{pool.Name, pool.ID, pool.Instance, strconv.Itoa(w.state.Epoch)}
+		switch count.Outcome {
+		case BlockOutcomeMissed:
+			w.metrics.MissedBlocks.WithLabelValues(labels...).Add(float64(count.Count))
+		case BlockOutcomeValidated:
+			w.metrics.ValidatedBlocks.WithLabelValues(labels...).Add(float64(count.Count))
+		case BlockOutcomeOrphaned:
+			w.metrics.OrphanedBlocks.WithLabelValues(labels...).Add(float64(count.Count))
+		}
+	}
+
+	w.logger.InfoContext(ctx, "block metrics restored from recorded outcomes", slog.Int("epoch", w.state.Epoch))
+	return nil
+}
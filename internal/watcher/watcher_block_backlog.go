@@ -0,0 +1,67 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BlockBacklog is the first slot skipped by the block watcher for a pool whose
+// schedule was not known. The leader slots of the pool from this slot onwards
+// are processed once its schedule is refreshed.
+type BlockBacklog struct {
+	PoolID    string `db:"pool_id"`
+	Epoch     int    `db:"epoch"`
+	FirstSlot int    `db:"first_slot"`
+}
+
+// BlockBacklogStore persists the backlogs of the degraded pools so the slots
+// they skipped are not lost across a restart of the watcher.
+type BlockBacklogStore struct {
+	db *sqlx.DB
+}
+
+func NewBlockBacklogStore(db *sqlx.DB) *BlockBacklogStore {
+	return &BlockBacklogStore{
+		db: db,
+	}
+}
+
+// Save records the backlog of a pool, replacing its previous one.
+func (s *BlockBacklogStore) Save(ctx context.Context, backlog BlockBacklog) error {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := "INSERT OR REPLACE INTO block_watcher_backlog (pool_id, epoch, first_slot) VALUES (?, ?, ?)"
+	if _, err := s.db.ExecContext(cctx, query, backlog.PoolID, backlog.Epoch, backlog.FirstSlot); err != nil {
+		return fmt.Errorf("failed to execute SQL query while saving the backlog of pool %s: %w", backlog.PoolID, err)
+	}
+	return nil
+}
+
+// Delete removes the backlog of a pool.
+func (s *BlockBacklogStore) Delete(ctx context.Context, poolID string) error {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := "DELETE FROM block_watcher_backlog WHERE pool_id = ?"
+	if _, err := s.db.ExecContext(cctx, query, poolID); err != nil {
+		return fmt.Errorf("failed to execute SQL query while deleting the backlog of pool %s: %w", poolID, err)
+	}
+	return nil
+}
+
+// List returns the backlogs of all the pools.
+func (s *BlockBacklogStore) List(ctx context.Context) ([]BlockBacklog, error) {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	backlogs := []BlockBacklog{}
+	query := "SELECT pool_id, epoch, first_slot FROM block_watcher_backlog"
+	if err := s.db.SelectContext(cctx, &backlogs, query); err != nil {
+		return nil, fmt.Errorf("failed to execute SQL query while listing the block watcher backlogs: %w", err)
+	}
+	return backlogs, nil
+}
@@ -0,0 +1,140 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BlockOutcome is the final classification of a leader slot.
+type BlockOutcome string
+
+const (
+	BlockOutcomeMissed    BlockOutcome = "missed"
+	BlockOutcomeValidated BlockOutcome = "validated"
+	BlockOutcomeOrphaned  BlockOutcome = "orphaned"
+)
+
+// BlockOutcomeRecord represents the outcome of a leader slot for a pool.
+type BlockOutcomeRecord struct {
+	Epoch       int          `db:"epoch"`
+	PoolID      string       `db:"pool_id"`
+	Slot        int          `db:"slot"`
+	Outcome     BlockOutcome `db:"outcome"`
+	BlockHash   string       `db:"block_hash"`
+	BlockHeight int          `db:"block_height"`
+	SlotLeader  string       `db:"slot_leader"`
+	RecordedAt  time.Time    `db:"recorded_at"`
+}
+
+// BlockOutcomeCount is the number of leader slots of a pool with a given outcome.
+type BlockOutcomeCount struct {
+	PoolID  string       `db:"pool_id"`
+	Outcome BlockOutcome `db:"outcome"`
+	Count   int          `db:"count"`
+}
+
+// BlockOutcomeStore persists the outcome of each leader slot so the block
+// counters can be rebuilt after a restart.
+type BlockOutcomeStore struct {
+	db *sqlx.DB
+}
+
+func NewBlockOutcomeStore(db *sqlx.DB) *BlockOutcomeStore {
+	return &BlockOutcomeStore{
+		db: db,
+	}
+}
+
+// Save records the outcome of a leader slot. A slot that is processed again
+// overwrites its previous outcome.
+func (s *BlockOutcomeStore) Save(ctx context.Context, record BlockOutcomeRecord) error {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := "INSERT OR REPLACE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := s.db.ExecContext(cctx, query,
+		record.Epoch,
+		record.PoolID,
+		record.Slot,
+		record.Outcome,
+		record.BlockHash,
+		record.BlockHeight,
+		record.SlotLeader,
+		record.RecordedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute SQL query while saving block outcome for pool %s at slot %d: %w", record.PoolID, record.Slot, err)
+	}
+	return nil
+}
+
+// Create records the outcome of a leader slot unless one is already recorded for
+// the pool and the slot. It reports whether the outcome has been recorded, so that
+// a slot processed again after a failed pass is not counted twice.
+func (s *BlockOutcomeStore) Create(ctx context.Context, record BlockOutcomeRecord) (bool, error) {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := "INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	result, err := s.db.ExecContext(cctx, query,
+		record.Epoch,
+		record.PoolID,
+		record.Slot,
+		record.Outcome,
+		record.BlockHash,
+		record.BlockHeight,
+		record.SlotLeader,
+		record.RecordedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute SQL query while creating block outcome for pool %s at slot %d: %w", record.PoolID, record.Slot, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get the rows affected while creating block outcome for pool %s at slot %d: %w", record.PoolID, record.Slot, err)
+	}
+	return rows > 0, nil
+}
+
+// CountByEpoch returns the number of outcomes recorded for each pool in the given epoch.
+func (s *BlockOutcomeStore) CountByEpoch(ctx context.Context, epoch int) ([]BlockOutcomeCount, error) {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	counts := []BlockOutcomeCount{}
+	query := "SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome"
+	if err := s.db.SelectContext(cctx, &counts, query, epoch); err != nil {
+		return nil, fmt.Errorf("failed to execute SQL query while counting block outcomes for epoch %d: %w", epoch, err)
+	}
+	return counts, nil
+}
+
+// ListByEpochAndPool returns the outcomes recorded for a pool in the given epoch, ordered by slot.
+func (s *BlockOutcomeStore) ListByEpochAndPool(ctx context.Context, epoch int, poolID string) ([]BlockOutcomeRecord, error) {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	records := []BlockOutcomeRecord{}
+	query := "SELECT epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at FROM block_outcomes WHERE epoch = ? AND pool_id = ? ORDER BY slot"
+	if err := s.db.SelectContext(cctx, &records, query, epoch, poolID); err != nil {
+		return nil, fmt.Errorf("failed to execute SQL query while listing block outcomes of pool %s in epoch %d: %w", poolID, epoch, err)
+	}
+	return records, nil
+}
+
+// ListByOutcomeSince returns the outcomes of the given kind recorded for slots greater than or equal to fromSlot, ordered by slot.
+func (s *BlockOutcomeStore) ListByOutcomeSince(ctx context.Context, outcome BlockOutcome, fromSlot int) ([]BlockOutcomeRecord, error) {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	records := []BlockOutcomeRecord{}
+	query := "SELECT epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at FROM block_outcomes WHERE outcome = ? AND slot >= ? ORDER BY slot"
+	if err := s.db.SelectContext(cctx, &records, query, outcome, fromSlot); err != nil {
+		return nil, fmt.Errorf("failed to execute SQL query while listing %s block outcomes since slot %d: %w", outcome, fromSlot, err)
+	}
+	return records, nil
+}
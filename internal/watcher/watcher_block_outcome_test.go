@@ -0,0 +1,158 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockOutcomeStore_Save(t *testing.T) {
+	t.Parallel()
+	t.Run("GoodPath_OutcomeIsSaved", func(t *testing.T) {
+		t.Parallel()
+		mockDBClient := setupDB(t)
+		store := NewBlockOutcomeStore(mockDBClient.db)
+		record := BlockOutcomeRecord{
+			Epoch:       100,
+			PoolID:      "pool-0",
+			Slot:        1000,
+			Outcome:     BlockOutcomeOrphaned,
+			BlockHash:   "hash",
+			BlockHeight: 10,
+			SlotLeader:  "pool-1",
+			RecordedAt:  time.Now(),
+		}
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(100, "pool-0", 1000, BlockOutcomeOrphaned, "hash", 10, "pool-1", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := store.Save(context.Background(), record)
+		require.NoError(t, err)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+	})
+
+	t.Run("SadPath_UnableToSaveOutcome", func(t *testing.T) {
+		t.Parallel()
+		mockDBClient := setupDB(t)
+		store := NewBlockOutcomeStore(mockDBClient.db)
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WillReturnError(errors.New("database is locked"))
+
+		err := store.Save(context.Background(), BlockOutcomeRecord{PoolID: "pool-0", Slot: 1000})
+		require.ErrorContains(t, err, "database is locked")
+	})
+}
+
+func TestBlockOutcomeStore_Create(t *testing.T) {
+	t.Parallel()
+	t.Run("GoodPath_OutcomeIsCreated", func(t *testing.T) {
+		t.Parallel()
+		mockDBClient := setupDB(t)
+		store := NewBlockOutcomeStore(mockDBClient.db)
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(100, "pool-0", 1000, BlockOutcomeMissed, "", 0, "", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		created, err := store.Create(context.Background(), BlockOutcomeRecord{Epoch: 100, PoolID: "pool-0", Slot: 1000, Outcome: BlockOutcomeMissed, RecordedAt: time.Now()})
+		require.NoError(t, err)
+		require.True(t, created)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+	})
+
+	t.Run("GoodPath_OutcomeAlreadyRecorded", func(t *testing.T) {
+		t.Parallel()
+		mockDBClient := setupDB(t)
+		store := NewBlockOutcomeStore(mockDBClient.db)
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(100, "pool-0", 1000, BlockOutcomeMissed, "", 0, "", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		created, err := store.Create(context.Background(), BlockOutcomeRecord{Epoch: 100, PoolID: "pool-0", Slot: 1000, Outcome: BlockOutcomeMissed, RecordedAt: time.Now()})
+		require.NoError(t, err)
+		require.False(t, created)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+	})
+}
+
+func TestBlockOutcomeStore_CountByEpoch(t *testing.T) {
+	t.Parallel()
+	t.Run("GoodPath_OutcomesAreCounted", func(t *testing.T) {
+		t.Parallel()
+		mockDBClient := setupDB(t)
+		store := NewBlockOutcomeStore(mockDBClient.db)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(100).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"pool_id", "outcome", "count"}).
+					AddRow("pool-0", BlockOutcomeMissed, 1).
+					AddRow("pool-0", BlockOutcomeValidated, 5),
+			)
+
+		counts, err := store.CountByEpoch(context.Background(), 100)
+		require.NoError(t, err)
+		require.Equal(t, []BlockOutcomeCount{
+			{PoolID: "pool-0", Outcome: BlockOutcomeMissed, Count: 1},
+			{PoolID: "pool-0", Outcome: BlockOutcomeValidated, Count: 5},
+		}, counts)
+	})
+}
+
+func TestBlockOutcomeStore_ListByOutcomeSince(t *testing.T) {
+	t.Parallel()
+	t.Run("GoodPath_OutcomesAreListed", func(t *testing.T) {
+		t.Parallel()
+		mockDBClient := setupDB(t)
+		store := NewBlockOutcomeStore(mockDBClient.db)
+		recordedAt := time.Now()
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at FROM block_outcomes WHERE outcome = ? AND slot >= ? ORDER BY slot").
+			WithArgs(BlockOutcomeValidated, 1000).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "pool_id", "slot", "outcome", "block_hash", "block_height", "slot_leader", "recorded_at"}).
+					AddRow(100, "pool-0", 1200, BlockOutcomeValidated, "hash", 10, "pool-0", recordedAt),
+			)
+
+		records, err := store.ListByOutcomeSince(context.Background(), BlockOutcomeValidated, 1000)
+		require.NoError(t, err)
+		require.Equal(t, []BlockOutcomeRecord{
+			{
+				Epoch:       100,
+				PoolID:      "pool-0",
+				Slot:        1200,
+				Outcome:     BlockOutcomeValidated,
+				BlockHash:   "hash",
+				BlockHeight: 10,
+				SlotLeader:  "pool-0",
+				RecordedAt:  recordedAt,
+			},
+		}, records)
+	})
+
+	t.Run("SadPath_UnableToListOutcomes", func(t *testing.T) {
+		t.Parallel()
+		mockDBClient := setupDB(t)
+		store := NewBlockOutcomeStore(mockDBClient.db)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at FROM block_outcomes WHERE outcome = ? AND slot >= ? ORDER BY slot").
+			WillReturnError(errors.New("database is locked"))
+
+		_, err := store.ListByOutcomeSince(context.Background(), BlockOutcomeValidated, 1000)
+		require.ErrorContains(t, err, "database is locked")
+	})
+}
@@ -0,0 +1,287 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/kilnfi/cardano-validator-watcher/internal/slotleader"
+)
+
+// EpochReconciliation compares, for a pool and a closed epoch, the computed
+// schedule and the recorded outcomes with the blocks forged on-chain.
+type EpochReconciliation struct {
+	Epoch           int
+	PoolID          string
+	ScheduledSlots  int
+	OnChainBlocks   int
+	ValidatedBlocks int
+	// UnscheduledBlocks are the hashes of the blocks forged on-chain by the pool in slots missing from its schedule.
+	UnscheduledBlocks []string
+	// UnrecordedBlocks are the hashes of the blocks forged on-chain by the pool in its leader slots that were not
+	// recorded as validated, or in any slot when its schedule is not known.
+	UnrecordedBlocks []string
+	// UnrecordedSlots are the leader slots of the schedule without a recorded outcome.
+	UnrecordedSlots []int
+}
+
+// BlockMismatch returns the difference between the blocks forged on-chain and
+// the validated blocks recorded by the block watcher.
+func (r EpochReconciliation) BlockMismatch() int {
+	return r.OnChainBlocks - r.ValidatedBlocks
+}
+
+// Consistent reports whether the schedule and the recorded outcomes match the chain.
+func (r EpochReconciliation) Consistent() bool {
+	return len(r.UnscheduledBlocks) == 0 && len(r.UnrecordedBlocks) == 0 && len(r.UnrecordedSlots) == 0 && r.BlockMismatch() == 0
+}
+
+// pendingReconciliation is the reconciliation of a pool in a closed epoch that failed.
+type pendingReconciliation struct {
+	epoch int
+	pool  pools.Pool
+}
+
+// loadPendingReconciliations restores the reconciliations that were pending before a restart:
+// the closed epochs of the active pools with a schedule or recorded outcomes but no reconciliation.
+func (w *BlockWatcher) loadPendingReconciliations(ctx context.Context) error {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows := []struct {
+		Epoch  int    `db:"epoch"`
+		PoolID string `db:"pool_id"`
+	}{}
+	query := "SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id"
+	if err := w.db.SelectContext(cctx, &rows, query, w.state.Epoch, w.state.Epoch); err != nil {
+		return fmt.Errorf("failed to execute SQL query while listing the pending reconciliations: %w", err)
+	}
+
+	activePools := make(map[string]pools.Pool)
+	for _, pool := range w.pools.GetActivePools() {
+		activePools[pool.ID] = pool
+	}
+	for _, row := range rows {
+		if pool, ok := activePools[row.PoolID]; ok {
+			w.pendingReconciliations = append(w.pendingReconciliations, pendingReconciliation{epoch: row.Epoch, pool: pool})
+		}
+	}
+	return nil
+}
+
+// reconcileEpoch reconciles the closed epoch for each active pool.
+// A pool that cannot be reconciled does not prevent the reconciliation of the others,
+// it is kept pending and retried by retryReconciliations.
+func (w *BlockWatcher) reconcileEpoch(ctx context.Context, epoch int) error {
+	w.metrics.ReconciliationUnscheduledBlocks.Reset()
+	w.metrics.ReconciliationUnrecordedBlocks.Reset()
+	w.metrics.ReconciliationUnrecordedSlots.Reset()
+	w.metrics.ReconciliationBlockMismatch.Reset()
+
+	var errs []error
+	for _, pool := range w.pools.GetActivePools() {
+		if err := w.reconcile(ctx, epoch, pool); err != nil {
+			w.pendingReconciliations = append(w.pendingReconciliations, pendingReconciliation{epoch: epoch, pool: pool})
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// retryReconciliations reconciles again the pools whose reconciliation failed.
+// The ones that fail again are kept pending for the next pass.
+func (w *BlockWatcher) retryReconciliations(ctx context.Context) error {
+	pending := w.pendingReconciliations
+	w.pendingReconciliations = nil
+
+	var errs []error
+	for _, p := range pending {
+		if err := w.reconcile(ctx, p.epoch, p.pool); err != nil {
+			w.pendingReconciliations = append(w.pendingReconciliations, p)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// queueReconciliation queues the reconciliation of a pool in a closed epoch, to be run
+// by retryReconciliations. The epochs that are not closed yet are reconciled at the epoch transition.
+func (w *BlockWatcher) queueReconciliation(epoch int, pool pools.Pool) {
+	if epoch >= w.state.Epoch {
+		return
+	}
+	p := pendingReconciliation{epoch: epoch, pool: pool}
+	if slices.Contains(w.pendingReconciliations, p) {
+		return
+	}
+	w.pendingReconciliations = append(w.pendingReconciliations, p)
+}
+
+// reconcile reconciles the closed epoch for a pool, then persists and reports the result.
+// The result is reported only once persisted, so a failed save retried later does not alert twice.
+func (w *BlockWatcher) reconcile(ctx context.Context, epoch int, pool pools.Pool) error {
+	reconciliation, err := w.reconcilePool(ctx, epoch, pool)
+	if err != nil {
+		return err
+	}
+
+	if err := w.saveReconciliation(ctx, reconciliation); err != nil {
+		return err
+	}
+	w.reportReconciliation(ctx, pool, reconciliation)
+	return nil
+}
+
+// reconcilePool compares the blocks forged on-chain by a pool in the closed epoch
+// with its schedule and the outcomes recorded by the block watcher.
+func (w *BlockWatcher) reconcilePool(ctx context.Context, epoch int, pool pools.Pool) (EpochReconciliation, error) {
+	hashes, err := w.blockfrost.GetBlockDistributionByPool(ctx, epoch, pool.ID)
+	if err != nil {
+		return EpochReconciliation{}, fmt.Errorf("reconcileEpoch: failed to get the blocks of pool %s in epoch %d: %w", pool.ID, epoch, err)
+	}
+
+	// A pool degraded during the whole epoch has no schedule, all its blocks are unrecorded
+	schedule, err := w.slotLeaderService.GetSlotLeaders(ctx, pool.ID, epoch)
+	scheduleFound := !errors.Is(err, slotleader.ErrScheduleNotFound)
+	if err != nil && scheduleFound {
+		return EpochReconciliation{}, fmt.Errorf("reconcileEpoch: failed to get the schedule of pool %s in epoch %d: %w", pool.ID, epoch, err)
+	}
+
+	records, err := w.outcomes.ListByEpochAndPool(ctx, epoch, pool.ID)
+	if err != nil {
+		return EpochReconciliation{}, fmt.Errorf("reconcileEpoch: %w", err)
+	}
+
+	reconciliation := EpochReconciliation{
+		Epoch:             epoch,
+		PoolID:            pool.ID,
+		ScheduledSlots:    len(schedule.Slots),
+		OnChainBlocks:     len(hashes),
+		UnscheduledBlocks: []string{},
+		UnrecordedBlocks:  []string{},
+		UnrecordedSlots:   []int{},
+	}
+
+	recordedSlots := make(map[int]struct{}, len(records))
+	validatedHashes := make(map[string]struct{}, len(records))
+	for _, record := range records {
+		recordedSlots[record.Slot] = struct{}{}
+		if record.Outcome == BlockOutcomeValidated {
+			validatedHashes[record.BlockHash] = struct{}{}
+			reconciliation.ValidatedBlocks++
+		}
+	}
+
+	scheduledSlots := make(map[int]struct{}, len(schedule.Slots))
+	for _, slot := range schedule.Slots {
+		scheduledSlots[slot] = struct{}{}
+		if _, ok := recordedSlots[slot]; !ok {
+			reconciliation.UnrecordedSlots = append(reconciliation.UnrecordedSlots, slot)
+		}
+	}
+
+	// A block outside of the schedule is a bug of the schedule, a block in a leader
+	// slot that is not recorded as validated is a gap of the block watcher.
+	for _, hash := range hashes {
+		if _, ok := validatedHashes[hash]; ok {
+			continue
+		}
+		if !scheduleFound {
+			reconciliation.UnrecordedBlocks = append(reconciliation.UnrecordedBlocks, hash)
+			continue
+		}
+
+		block, err := w.blockfrost.GetBlockByHash(ctx, hash)
+		if err != nil {
+			return EpochReconciliation{}, fmt.Errorf("reconcileEpoch: failed to get the block %s of pool %s: %w", hash, pool.ID, err)
+		}
+		if _, ok := scheduledSlots[block.Slot]; ok {
+			reconciliation.UnrecordedBlocks = append(reconciliation.UnrecordedBlocks, hash)
+		} else {
+			reconciliation.UnscheduledBlocks = append(reconciliation.UnscheduledBlocks, hash)
+		}
+	}
+	return reconciliation, nil
+}
+
+// reportReconciliation logs the reconciliation of a pool and exposes it as metrics.
+func (w *BlockWatcher) reportReconciliation(ctx context.Context, pool pools.Pool, reconciliation EpochReconciliation) {
+	labels := []string{pool.Name, pool.ID, pool.Instance, strconv.Itoa(reconciliation.Epoch)}
+	w.metrics.ReconciliationUnscheduledBlocks.WithLabelValues(labels...).Set(float64(len(reconciliation.UnscheduledBlocks)))
+	w.metrics.ReconciliationUnrecordedBlocks.WithLabelValues(labels...).Set(float64(len(reconciliation.UnrecordedBlocks)))
+	w.metrics.ReconciliationUnrecordedSlots.WithLabelValues(labels...).Set(float64(len(reconciliation.UnrecordedSlots)))
+	w.metrics.ReconciliationBlockMismatch.WithLabelValues(labels...).Set(float64(reconciliation.BlockMismatch()))
+
+	attrs := []any{
+		slog.String("pool_id", pool.ID),
+		slog.Int("epoch", reconciliation.Epoch),
+		slog.Int("scheduled_slots", reconciliation.ScheduledSlots),
+		slog.Int("on_chain_blocks", reconciliation.OnChainBlocks),
+		slog.Int("validated_blocks", reconciliation.ValidatedBlocks),
+	}
+	if reconciliation.Consistent() {
+		w.logger.InfoContext(ctx,
+			fmt.Sprintf("✅ epoch %d reconciled for pool %s", reconciliation.Epoch, pool.Name),
+			attrs...,
+		)
+		return
+	}
+
+	unrecordedSlots := make([]string, 0, len(reconciliation.UnrecordedSlots))
+	for _, slot := range reconciliation.UnrecordedSlots {
+		unrecordedSlots = append(unrecordedSlots, strconv.Itoa(slot))
+	}
+	attrs = append(attrs,
+		slog.Int("block_mismatch", reconciliation.BlockMismatch()),
+		slog.String("unscheduled_blocks", strings.Join(reconciliation.UnscheduledBlocks, ",")),
+		slog.String("unrecorded_blocks", strings.Join(reconciliation.UnrecordedBlocks, ",")),
+		slog.String("unrecorded_slots", strings.Join(unrecordedSlots, ",")),
+	)
+	w.logger.WarnContext(ctx,
+		fmt.Sprintf("🚨 epoch %d of pool %s does not match the chain", reconciliation.Epoch, pool.Name),
+		attrs...,
+	)
+}
+
+// saveReconciliation persists the reconciliation of a pool.
+func (w *BlockWatcher) saveReconciliation(ctx context.Context, reconciliation EpochReconciliation) error {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	unscheduledBlocks, err := json.Marshal(reconciliation.UnscheduledBlocks)
+	if err != nil {
+		return fmt.Errorf("failed to encode the unscheduled blocks of pool %s: %w", reconciliation.PoolID, err)
+	}
+	unrecordedBlocks, err := json.Marshal(reconciliation.UnrecordedBlocks)
+	if err != nil {
+		return fmt.Errorf("failed to encode the unrecorded blocks of pool %s: %w", reconciliation.PoolID, err)
+	}
+	unrecordedSlots, err := json.Marshal(reconciliation.UnrecordedSlots)
+	if err != nil {
+		return fmt.Errorf("failed to encode the unrecorded slots of pool %s: %w", reconciliation.PoolID, err)
+	}
+
+	query := "INSERT OR REPLACE INTO epoch_reconciliations (epoch, pool_id, scheduled_slots, on_chain_blocks, validated_blocks, unscheduled_blocks, unrecorded_blocks, unrecorded_slots, reconciled_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err = w.db.ExecContext(cctx, query,
+		reconciliation.Epoch,
+		reconciliation.PoolID,
+		reconciliation.ScheduledSlots,
+		reconciliation.OnChainBlocks,
+		reconciliation.ValidatedBlocks,
+		string(unscheduledBlocks),
+		string(unrecordedBlocks),
+		string(unrecordedSlots),
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute SQL query while saving the reconciliation of pool %s in epoch %d: %w", reconciliation.PoolID, reconciliation.Epoch, err)
+	}
+	return nil
+}
@@ -0,0 +1,401 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/blockfrost/blockfrost-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/kilnfi/cardano-validator-watcher/internal/slotleader"
+)
+
+const (
+	listOutcomesByEpochAndPoolQuery = "SELECT epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at FROM block_outcomes WHERE epoch = ? AND pool_id = ? ORDER BY slot"
+	listPendingReconciliationsQuery = "SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id"
+	saveReconciliationQuery         = "INSERT OR REPLACE INTO epoch_reconciliations (epoch, pool_id, scheduled_slots, on_chain_blocks, validated_blocks, unscheduled_blocks, unrecorded_blocks, unrecorded_slots, reconciled_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+)
+
+var outcomeColumns = []string{"epoch", "pool_id", "slot", "outcome", "block_hash", "block_height", "slot_leader", "recorded_at"}
+
+func TestBlockWatcher_ReconcileEpoch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_EpochMatchesTheChain", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+		pool := setupPool()
+		epoch := 100
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_reconciliation_block_mismatch difference between the blocks forged on-chain and the validated blocks recorded for the closed epoch
+# TYPE cardano_validator_watcher_reconciliation_block_mismatch gauge
+cardano_validator_watcher_reconciliation_block_mismatch{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 0
+# HELP cardano_validator_watcher_reconciliation_unrecorded_blocks number of blocks forged on-chain by the pool in leader slots of the closed epoch that were not recorded as validated
+# TYPE cardano_validator_watcher_reconciliation_unrecorded_blocks gauge
+cardano_validator_watcher_reconciliation_unrecorded_blocks{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 0
+# HELP cardano_validator_watcher_reconciliation_unrecorded_slots number of leader slots of the closed epoch without a recorded outcome
+# TYPE cardano_validator_watcher_reconciliation_unrecorded_slots gauge
+cardano_validator_watcher_reconciliation_unrecorded_slots{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 0
+# HELP cardano_validator_watcher_reconciliation_unscheduled_blocks number of blocks forged on-chain by the pool in the closed epoch in slots missing from its schedule
+# TYPE cardano_validator_watcher_reconciliation_unscheduled_blocks gauge
+cardano_validator_watcher_reconciliation_unscheduled_blocks{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 0
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_reconciliation_block_mismatch",
+			"cardano_validator_watcher_reconciliation_unrecorded_blocks",
+			"cardano_validator_watcher_reconciliation_unrecorded_slots",
+			"cardano_validator_watcher_reconciliation_unscheduled_blocks",
+		}
+
+		clients.bf.EXPECT().
+			GetBlockDistributionByPool(mock.Anything, epoch, pool[0].ID).
+			Return([]string{"hash-1000"}, nil)
+		clients.sl.EXPECT().
+			GetSlotLeaders(mock.Anything, pool[0].ID, epoch).
+			Return(slotleader.Schedule{PoolID: pool[0].ID, Epoch: epoch, Slots: []int{1000, 2000}}, nil)
+		mockDBClient.mock.
+			ExpectQuery(listOutcomesByEpochAndPoolQuery).
+			WithArgs(epoch, pool[0].ID).
+			WillReturnRows(
+				sqlmock.NewRows(outcomeColumns).
+					AddRow(epoch, pool[0].ID, 1000, BlockOutcomeValidated, "hash-1000", 10, pool[0].ID, time.Now()).
+					AddRow(epoch, pool[0].ID, 2000, BlockOutcomeMissed, "", 0, "", time.Now()),
+			)
+		mockDBClient.mock.
+			ExpectExec(saveReconciliationQuery).
+			WithArgs(epoch, pool[0].ID, 2, 1, 1, "[]", "[]", "[]", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), pool, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		err := watcher.reconcileEpoch(context.Background(), epoch)
+		require.NoError(t, err)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("GoodPath_DiscrepanciesAreFlagged", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+		pool := setupPool()
+		epoch := 100
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_reconciliation_block_mismatch difference between the blocks forged on-chain and the validated blocks recorded for the closed epoch
+# TYPE cardano_validator_watcher_reconciliation_block_mismatch gauge
+cardano_validator_watcher_reconciliation_block_mismatch{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 2
+# HELP cardano_validator_watcher_reconciliation_unrecorded_blocks number of blocks forged on-chain by the pool in leader slots of the closed epoch that were not recorded as validated
+# TYPE cardano_validator_watcher_reconciliation_unrecorded_blocks gauge
+cardano_validator_watcher_reconciliation_unrecorded_blocks{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1
+# HELP cardano_validator_watcher_reconciliation_unrecorded_slots number of leader slots of the closed epoch without a recorded outcome
+# TYPE cardano_validator_watcher_reconciliation_unrecorded_slots gauge
+cardano_validator_watcher_reconciliation_unrecorded_slots{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1
+# HELP cardano_validator_watcher_reconciliation_unscheduled_blocks number of blocks forged on-chain by the pool in the closed epoch in slots missing from its schedule
+# TYPE cardano_validator_watcher_reconciliation_unscheduled_blocks gauge
+cardano_validator_watcher_reconciliation_unscheduled_blocks{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_reconciliation_block_mismatch",
+			"cardano_validator_watcher_reconciliation_unrecorded_blocks",
+			"cardano_validator_watcher_reconciliation_unrecorded_slots",
+			"cardano_validator_watcher_reconciliation_unscheduled_blocks",
+		}
+
+		// the block of slot 1500 is not in the schedule and the slot 2000 was never processed
+		clients.bf.EXPECT().
+			GetBlockDistributionByPool(mock.Anything, epoch, pool[0].ID).
+			Return([]string{"hash-1000", "hash-1500", "hash-2000"}, nil)
+		clients.bf.EXPECT().
+			GetBlockByHash(mock.Anything, "hash-1500").
+			Return(blockfrost.Block{Hash: "hash-1500", Slot: 1500, SlotLeader: pool[0].ID}, nil)
+		clients.bf.EXPECT().
+			GetBlockByHash(mock.Anything, "hash-2000").
+			Return(blockfrost.Block{Hash: "hash-2000", Slot: 2000, SlotLeader: pool[0].ID}, nil)
+		clients.sl.EXPECT().
+			GetSlotLeaders(mock.Anything, pool[0].ID, epoch).
+			Return(slotleader.Schedule{PoolID: pool[0].ID, Epoch: epoch, Slots: []int{1000, 2000}}, nil)
+		mockDBClient.mock.
+			ExpectQuery(listOutcomesByEpochAndPoolQuery).
+			WithArgs(epoch, pool[0].ID).
+			WillReturnRows(
+				sqlmock.NewRows(outcomeColumns).
+					AddRow(epoch, pool[0].ID, 1000, BlockOutcomeValidated, "hash-1000", 10, pool[0].ID, time.Now()),
+			)
+		mockDBClient.mock.
+			ExpectExec(saveReconciliationQuery).
+			WithArgs(epoch, pool[0].ID, 2, 3, 1, `["hash-1500"]`, `["hash-2000"]`, "[2000]", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), pool, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		err := watcher.reconcileEpoch(context.Background(), epoch)
+		require.NoError(t, err)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("GoodPath_PoolWithoutScheduleIsReconciled", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+		pool := setupPool()
+		epoch := 100
+
+		clients.bf.EXPECT().
+			GetBlockDistributionByPool(mock.Anything, epoch, pool[0].ID).
+			Return([]string{"hash-1000"}, nil)
+		clients.sl.EXPECT().
+			GetSlotLeaders(mock.Anything, pool[0].ID, epoch).
+			Return(slotleader.Schedule{}, slotleader.ErrScheduleNotFound)
+		mockDBClient.mock.
+			ExpectQuery(listOutcomesByEpochAndPoolQuery).
+			WithArgs(epoch, pool[0].ID).
+			WillReturnRows(sqlmock.NewRows(outcomeColumns))
+		mockDBClient.mock.
+			ExpectExec(saveReconciliationQuery).
+			WithArgs(epoch, pool[0].ID, 0, 1, 0, "[]", `["hash-1000"]`, "[]", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), pool, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		err := watcher.reconcileEpoch(context.Background(), epoch)
+		require.NoError(t, err)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+	})
+
+	t.Run("SadPath_FailingPoolDoesNotBlockOtherPools", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+		pool := append(setupPool(), pools.Pool{ID: "pool-1", Instance: "pool-1", Key: "key", Name: "pool-1"})
+		epoch := 100
+
+		clients.bf.EXPECT().
+			GetBlockDistributionByPool(mock.Anything, epoch, pool[0].ID).
+			Return(nil, errors.New("blockfrost is unavailable"))
+		clients.bf.EXPECT().
+			GetBlockDistributionByPool(mock.Anything, epoch, pool[1].ID).
+			Return([]string{}, nil)
+		clients.sl.EXPECT().
+			GetSlotLeaders(mock.Anything, pool[1].ID, epoch).
+			Return(slotleader.Schedule{PoolID: pool[1].ID, Epoch: epoch}, nil)
+		mockDBClient.mock.
+			ExpectQuery(listOutcomesByEpochAndPoolQuery).
+			WithArgs(epoch, pool[1].ID).
+			WillReturnRows(sqlmock.NewRows(outcomeColumns))
+		mockDBClient.mock.
+			ExpectExec(saveReconciliationQuery).
+			WithArgs(epoch, pool[1].ID, 0, 0, 0, "[]", "[]", "[]", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), pool, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		err := watcher.reconcileEpoch(context.Background(), epoch)
+		require.ErrorContains(t, err, "blockfrost is unavailable")
+		require.Equal(t, []pendingReconciliation{{epoch: epoch, pool: pool[0]}}, watcher.pendingReconciliations)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+	})
+
+	t.Run("SadPath_UnableToFetchUnknownBlockKeepsPoolPending", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+		pool := setupPool()
+		epoch := 100
+
+		clients.bf.EXPECT().
+			GetBlockDistributionByPool(mock.Anything, epoch, pool[0].ID).
+			Return([]string{"hash-1000"}, nil)
+		clients.sl.EXPECT().
+			GetSlotLeaders(mock.Anything, pool[0].ID, epoch).
+			Return(slotleader.Schedule{PoolID: pool[0].ID, Epoch: epoch, Slots: []int{1000}}, nil)
+		mockDBClient.mock.
+			ExpectQuery(listOutcomesByEpochAndPoolQuery).
+			WithArgs(epoch, pool[0].ID).
+			WillReturnRows(sqlmock.NewRows(outcomeColumns))
+		clients.bf.EXPECT().
+			GetBlockByHash(mock.Anything, "hash-1000").
+			Return(blockfrost.Block{}, errors.New("blockfrost is unavailable"))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), pool, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		err := watcher.reconcileEpoch(context.Background(), epoch)
+		require.ErrorContains(t, err, "failed to get the block hash-1000 of pool pool-0")
+		require.Equal(t, []pendingReconciliation{{epoch: epoch, pool: pool[0]}}, watcher.pendingReconciliations)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+	})
+
+	t.Run("SadPath_UnsavedReconciliationIsNotReported", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+		pool := setupPool()
+		epoch := 100
+
+		clients.bf.EXPECT().
+			GetBlockDistributionByPool(mock.Anything, epoch, pool[0].ID).
+			Return([]string{"hash-1000"}, nil)
+		clients.sl.EXPECT().
+			GetSlotLeaders(mock.Anything, pool[0].ID, epoch).
+			Return(slotleader.Schedule{}, slotleader.ErrScheduleNotFound)
+		mockDBClient.mock.
+			ExpectQuery(listOutcomesByEpochAndPoolQuery).
+			WithArgs(epoch, pool[0].ID).
+			WillReturnRows(sqlmock.NewRows(outcomeColumns))
+		mockDBClient.mock.
+			ExpectExec(saveReconciliationQuery).
+			WithArgs(epoch, pool[0].ID, 0, 1, 0, "[]", `["hash-1000"]`, "[]", AnyTime{}).
+			WillReturnError(errors.New("database is locked"))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), pool, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		err := watcher.reconcileEpoch(context.Background(), epoch)
+		require.ErrorContains(t, err, "database is locked")
+		require.Equal(t, []pendingReconciliation{{epoch: epoch, pool: pool[0]}}, watcher.pendingReconciliations)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+
+		// the discrepancy is reported once the reconciliation is saved by a retry
+		require.Equal(t, 0, testutil.CollectAndCount(registry.metrics.ReconciliationUnrecordedBlocks))
+	})
+}
+
+func TestBlockWatcher_RetryReconciliations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_PendingReconciliationsAreRetried", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+		pool := append(setupPool(), pools.Pool{ID: "pool-1", Instance: "pool-1", Key: "key", Name: "pool-1"})
+		epoch := 100
+
+		// pool-0 is reconciled at the retry, pool-1 fails again and stays pending
+		clients.bf.EXPECT().
+			GetBlockDistributionByPool(mock.Anything, epoch, pool[0].ID).
+			Return([]string{}, nil)
+		clients.sl.EXPECT().
+			GetSlotLeaders(mock.Anything, pool[0].ID, epoch).
+			Return(slotleader.Schedule{PoolID: pool[0].ID, Epoch: epoch}, nil)
+		mockDBClient.mock.
+			ExpectQuery(listOutcomesByEpochAndPoolQuery).
+			WithArgs(epoch, pool[0].ID).
+			WillReturnRows(sqlmock.NewRows(outcomeColumns))
+		mockDBClient.mock.
+			ExpectExec(saveReconciliationQuery).
+			WithArgs(epoch, pool[0].ID, 0, 0, 0, "[]", "[]", "[]", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		clients.bf.EXPECT().
+			GetBlockDistributionByPool(mock.Anything, epoch-1, pool[1].ID).
+			Return(nil, errors.New("blockfrost is unavailable"))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), pool, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		watcher.pendingReconciliations = []pendingReconciliation{{epoch: epoch, pool: pool[0]}, {epoch: epoch - 1, pool: pool[1]}}
+
+		err := watcher.retryReconciliations(context.Background())
+		require.ErrorContains(t, err, "blockfrost is unavailable")
+		require.Equal(t, []pendingReconciliation{{epoch: epoch - 1, pool: pool[1]}}, watcher.pendingReconciliations)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+	})
+}
+
+func TestBlockWatcher_LoadPendingReconciliations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_ClosedEpochsWithoutReconciliationArePending", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+		pool := append(setupPool(), pools.Pool{ID: "pool-1", Instance: "pool-1", Key: "key", Name: "pool-1"})
+		epoch := 100
+
+		// pool-2 is no longer monitored
+		mockDBClient.mock.
+			ExpectQuery(listPendingReconciliationsQuery).
+			WithArgs(epoch, epoch).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "pool_id"}).
+					AddRow(98, pool[1].ID).
+					AddRow(99, pool[0].ID).
+					AddRow(99, "pool-2"),
+			)
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), pool, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		watcher.state.Epoch = epoch
+
+		err := watcher.loadPendingReconciliations(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []pendingReconciliation{{epoch: 98, pool: pool[1]}, {epoch: 99, pool: pool[0]}}, watcher.pendingReconciliations)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+	})
+
+	t.Run("SadPath_UnableToListPendingReconciliations", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+		pool := setupPool()
+		epoch := 100
+
+		mockDBClient.mock.
+			ExpectQuery(listPendingReconciliationsQuery).
+			WithArgs(epoch, epoch).
+			WillReturnError(errors.New("database is locked"))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), pool, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		watcher.state.Epoch = epoch
+
+		err := watcher.loadPendingReconciliations(context.Background())
+		require.ErrorContains(t, err, "database is locked")
+		require.Empty(t, watcher.pendingReconciliations)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+	})
+}
+
+func TestBlockWatcher_QueueReconciliation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_OnlyClosedEpochsAreQueuedOnce", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+		pool := setupPool()
+		epoch := 100
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), pool, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		watcher.state.Epoch = epoch
+
+		watcher.queueReconciliation(epoch-1, pool[0])
+		watcher.queueReconciliation(epoch-1, pool[0])
+		watcher.queueReconciliation(epoch, pool[0])
+		require.Equal(t, []pendingReconciliation{{epoch: epoch - 1, pool: pool[0]}}, watcher.pendingReconciliations)
+	})
+}
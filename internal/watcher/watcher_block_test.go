@@ -11,7 +11,9 @@ import (
 	"github.com/blockfrost/blockfrost-go"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 
+	bf "github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
 	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/kilnfi/cardano-validator-watcher/internal/slotleader"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -71,6 +73,19 @@ func TestBlockWatcher_Start(t *testing.T) {
 			WithArgs(currentEpoch, 1, AnyTime{}).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(currentEpoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
 		clients.sl.EXPECT().
 			IsSlotsEmpty(mock.Anything, pool[0].ID, currentEpoch).
 			Return(false, nil)
@@ -84,6 +99,7 @@ func TestBlockWatcher_Start(t *testing.T) {
 			clients.cardano,
 			clients.bf,
 			clients.sl,
+			setupTimeline(t),
 			pool,
 			registry.metrics,
 			mockDBClient.db,
@@ -95,6 +111,97 @@ func TestBlockWatcher_Start(t *testing.T) {
 		require.Equal(t, watcher.state.Epoch, currentEpoch)
 	})
 
+	t.Run("GoodPath_RestoreBlockMetricsFromRecordedOutcomes", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_missed_blocks_total number of missed blocks in the current epoch
+# TYPE cardano_validator_watcher_missed_blocks_total counter
+cardano_validator_watcher_missed_blocks_total{epoch="100",pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1
+# HELP cardano_validator_watcher_orphaned_blocks_total number of orphaned blocks in the current epoch
+# TYPE cardano_validator_watcher_orphaned_blocks_total counter
+cardano_validator_watcher_orphaned_blocks_total{epoch="100",pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 2
+# HELP cardano_validator_watcher_validated_blocks_total number of validated blocks in the current epoch
+# TYPE cardano_validator_watcher_validated_blocks_total counter
+cardano_validator_watcher_validated_blocks_total{epoch="100",pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 12
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_missed_blocks_total",
+			"cardano_validator_watcher_orphaned_blocks_total",
+			"cardano_validator_watcher_validated_blocks_total",
+		}
+
+		epoch := 100
+		initialSlot := 99
+
+		ctx := setupContextWithTimeout(t, time.Second*5)
+
+		// Setup Mocks for Dependencies
+		clients.bf.EXPECT().
+			GetLatestEpoch(mock.Anything).
+			Return(
+				blockfrost.Epoch{Epoch: epoch},
+				nil,
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, slot, last_update FROM block_watcher_state LIMIT 1").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "slot", "last_update"}).
+					AddRow(epoch, initialSlot, time.Now()),
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"pool_id", "outcome", "count"}).
+					AddRow(pool[0].ID, BlockOutcomeMissed, 1).
+					AddRow(pool[0].ID, BlockOutcomeOrphaned, 2).
+					AddRow(pool[0].ID, BlockOutcomeValidated, 12).
+					AddRow("unknown-pool", BlockOutcomeValidated, 3),
+			)
+
+		clients.sl.EXPECT().
+			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
+			Return(false, nil)
+
+		options := BlockWatcherOptions{
+			RefreshInterval: time.Minute * 1,
+		}
+		healthStore := NewHealthStore()
+		healthStore.SetHealth(false)
+		watcher := NewBlockWatcher(
+			clients.cardano,
+			clients.bf,
+			clients.sl,
+			setupTimeline(t),
+			pool,
+			registry.metrics,
+			mockDBClient.db,
+			healthStore,
+			options,
+		)
+		err := watcher.Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.GatherAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
 	t.Run("GoodPath_PoolWithoutLeader", func(t *testing.T) {
 		t.Parallel()
 
@@ -124,6 +231,19 @@ func TestBlockWatcher_Start(t *testing.T) {
 					AddRow(epoch, initialSlot, time.Now()),
 			)
 
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
 		clients.sl.EXPECT().
 			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
 			Return(false, nil)
@@ -142,23 +262,105 @@ func TestBlockWatcher_Start(t *testing.T) {
 				nil,
 			)
 
-		// handle currentSlot - 1
+		// the leader slots are loaded once for the whole range of slots
 		clients.sl.EXPECT().
-			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
-			Return(false, nil)
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(5000))
 
-		clients.sl.EXPECT().
-			IsSlotLeader(mock.Anything, pool[0].ID, currentSlot-1, epoch).
-			Return(false, nil)
+		// save state
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_watcher_state (id, epoch, slot, last_update) VALUES (1, ?, ?, ?)").
+			WithArgs(epoch, currentSlot, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		options := BlockWatcherOptions{
+			RefreshInterval: time.Minute * 1,
+		}
+		healthStore := NewHealthStore()
+		healthStore.SetHealth(true)
+		watcher := NewBlockWatcher(
+			clients.cardano,
+			clients.bf,
+			clients.sl,
+			setupTimeline(t),
+			pool,
+			registry.metrics,
+			mockDBClient.db,
+			healthStore,
+			options,
+		)
+		err := watcher.Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, watcher.state.Slot, currentSlot)
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("GoodPath_SchedulePrivacyHidesNextSlotLeader", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		epoch := 100
+		initialSlot := 99
+		currentSlot := 101
+		currentHeight := 101
+
+		ctx := setupContextWithTimeout(t, time.Second*10)
+
+		// Setup Mocks for Dependencies
+		clients.bf.EXPECT().
+			GetLatestEpoch(mock.Anything).
+			Return(
+				blockfrost.Epoch{Epoch: epoch},
+				nil,
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, slot, last_update FROM block_watcher_state LIMIT 1").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "slot", "last_update"}).
+					AddRow(epoch, initialSlot, time.Now()),
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
 
-		// handle currentSlot
 		clients.sl.EXPECT().
 			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
 			Return(false, nil)
 
+		clients.bf.EXPECT().
+			GetLatestBlock(mock.Anything).
+			Return(
+				blockfrost.Block{
+					Height: currentHeight,
+					Slot:   currentSlot,
+					Hash:   "hash",
+					Epoch:  epoch,
+				},
+				nil,
+			)
+
+		// the remaining leader slots and the slots of the processed range are
+		// both loaded from the schedule, the next slot leader is never fetched
 		clients.sl.EXPECT().
-			IsSlotLeader(mock.Anything, pool[0].ID, currentSlot, epoch).
-			Return(false, nil)
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(5000, 6000))
 
 		// save state
 		mockDBClient.mock.
@@ -166,8 +368,19 @@ func TestBlockWatcher_Start(t *testing.T) {
 			WithArgs(epoch, currentSlot, AnyTime{}).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
+		registry.metricsUnderTest = append(registry.metricsUnderTest,
+			"cardano_validator_watcher_next_slot_leader",
+			"cardano_validator_watcher_remaining_leader_slots",
+		)
+		registry.metricsExpectedOutput = `
+			# HELP cardano_validator_watcher_remaining_leader_slots number of leader slots remaining in the current epoch for each monitored pool
+			# TYPE cardano_validator_watcher_remaining_leader_slots gauge
+			cardano_validator_watcher_remaining_leader_slots{epoch="100",pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 2
+		`
+
 		options := BlockWatcherOptions{
 			RefreshInterval: time.Minute * 1,
+			SchedulePrivacy: true,
 		}
 		healthStore := NewHealthStore()
 		healthStore.SetHealth(true)
@@ -175,6 +388,7 @@ func TestBlockWatcher_Start(t *testing.T) {
 			clients.cardano,
 			clients.bf,
 			clients.sl,
+			setupTimeline(t),
 			pool,
 			registry.metrics,
 			mockDBClient.db,
@@ -228,6 +442,19 @@ cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0",
 					AddRow(epoch, initialSlot, time.Now()),
 			)
 
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
 		clients.sl.EXPECT().
 			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
 			Return(false, nil)
@@ -244,24 +471,10 @@ cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0",
 				}, nil,
 			)
 
-		// handle currentSlot - 1
-		clients.sl.EXPECT().
-			IsSlotsEmpty(
-				mock.Anything,
-				pool[0].ID,
-				epoch,
-			).
-			Return(false, nil)
-
+		// the leader slots are loaded once for the whole range of slots
 		clients.sl.EXPECT().
-			IsSlotLeader(
-				mock.Anything,
-				pool[0].ID,
-				currentSlot-1,
-				epoch,
-			).
-			Return(true, nil)
-
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(currentSlot-1, 5000))
 		clients.bf.EXPECT().
 			GetBlockBySlot(
 				mock.Anything,
@@ -275,22 +488,10 @@ cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0",
 				nil,
 			)
 
-		// handle currentSlot
-		clients.sl.EXPECT().
-			IsSlotsEmpty(
-				mock.Anything,
-				pool[0].ID,
-				epoch,
-			).Return(false, nil)
-
-		clients.sl.EXPECT().
-			IsSlotLeader(
-				mock.Anything,
-				pool[0].ID,
-				currentSlot,
-				epoch,
-			).
-			Return(false, nil)
+		mockDBClient.mock.
+			ExpectExec("INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pool[0].ID, currentSlot-1, BlockOutcomeValidated, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		mockDBClient.mock.
 			ExpectExec("INSERT OR REPLACE INTO block_watcher_state (id, epoch, slot, last_update) VALUES (1, ?, ?, ?)").
@@ -306,6 +507,7 @@ cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0",
 			clients.cardano,
 			clients.bf,
 			clients.sl,
+			setupTimeline(t),
 			pool,
 			registry.metrics,
 			mockDBClient.db,
@@ -360,6 +562,19 @@ cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0",
 					AddRow(epoch, initialSlot, time.Now()),
 			)
 
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
 		clients.sl.EXPECT().
 			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
 			Return(false, nil)
@@ -378,44 +593,21 @@ cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0",
 
 		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, currentHeight, epoch).Return(5000, nil)
 
-		// handle currentSlot - 1
-		clients.sl.EXPECT().
-			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
-			Return(false, nil)
-
+		// the leader slots are loaded once for the whole range of slots
 		clients.sl.EXPECT().
-			IsSlotLeader(
-				mock.Anything,
-				pool[0].ID,
-				currentSlot-1,
-				epoch,
-			).
-			Return(true, nil)
-
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(currentSlot-1, 5000))
 		clients.bf.EXPECT().
 			GetBlockBySlot(
 				mock.Anything,
 				currentSlot-1,
 			).
-			Return(blockfrost.Block{}, errors.New("Not Found"))
-
-		// handle currentSlot
-		clients.sl.EXPECT().
-			IsSlotsEmpty(
-				mock.Anything,
-				pool[0].ID,
-				epoch,
-			).
-			Return(false, nil)
+			Return(blockfrost.Block{}, bf.ErrNotFound)
 
-		clients.sl.EXPECT().
-			IsSlotLeader(
-				mock.Anything,
-				pool[0].ID,
-				currentSlot,
-				epoch,
-			).
-			Return(false, nil)
+		mockDBClient.mock.
+			ExpectExec("INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pool[0].ID, currentSlot-1, BlockOutcomeMissed, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		// save state
 		mockDBClient.mock.
@@ -432,6 +624,7 @@ cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0",
 			clients.cardano,
 			clients.bf,
 			clients.sl,
+			setupTimeline(t),
 			pool,
 			registry.metrics,
 			mockDBClient.db,
@@ -485,6 +678,19 @@ cardano_validator_watcher_orphaned_blocks_total{epoch="100", pool_id="pool-0", p
 					AddRow(epoch, initialSlot, time.Now()),
 			)
 
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
 		clients.sl.EXPECT().
 			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
 			Return(false, nil)
@@ -503,24 +709,10 @@ cardano_validator_watcher_orphaned_blocks_total{epoch="100", pool_id="pool-0", p
 				nil,
 			)
 
-		// handle currentSlot - 1
-		clients.sl.EXPECT().
-			IsSlotsEmpty(
-				mock.Anything,
-				pool[0].ID,
-				epoch,
-			).
-			Return(false, nil)
-
+		// the leader slots are loaded once for the whole range of slots
 		clients.sl.EXPECT().
-			IsSlotLeader(
-				mock.Anything,
-				pool[0].ID,
-				currentSlot-1,
-				epoch,
-			).
-			Return(true, nil)
-
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(currentSlot-1, 5000))
 		clients.bf.EXPECT().
 			GetBlockBySlot(
 				mock.Anything,
@@ -528,23 +720,10 @@ cardano_validator_watcher_orphaned_blocks_total{epoch="100", pool_id="pool-0", p
 			).
 			Return(blockfrost.Block{SlotLeader: "bad-pool"}, nil)
 
-		// handle currentSlot
-		clients.sl.EXPECT().
-			IsSlotsEmpty(
-				mock.Anything,
-				pool[0].ID,
-				epoch,
-			).
-			Return(false, nil)
-
-		clients.sl.EXPECT().
-			IsSlotLeader(
-				mock.Anything,
-				pool[0].ID,
-				currentSlot,
-				epoch,
-			).
-			Return(false, nil)
+		mockDBClient.mock.
+			ExpectExec("INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pool[0].ID, currentSlot-1, BlockOutcomeOrphaned, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		// save state
 		mockDBClient.mock.
@@ -561,6 +740,7 @@ cardano_validator_watcher_orphaned_blocks_total{epoch="100", pool_id="pool-0", p
 			clients.cardano,
 			clients.bf,
 			clients.sl,
+			setupTimeline(t),
 			pool,
 			registry.metrics,
 			mockDBClient.db,
@@ -621,9 +801,22 @@ cardano_validator_watcher_consecutive_missed_blocks{epoch="100", pool_id="pool-0
 					AddRow(epoch, initialSlot, time.Now()),
 			)
 
-		clients.sl.EXPECT().
-			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
-			Return(false, nil)
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
+		clients.sl.EXPECT().
+			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
+			Return(false, nil)
 
 		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, currentHeight, epoch).Return(5000, nil)
 
@@ -639,31 +832,27 @@ cardano_validator_watcher_consecutive_missed_blocks{epoch="100", pool_id="pool-0
 				nil,
 			)
 
-		// handle currentSlot - 1
+		// the leader slots are loaded once for the whole range of slots
 		clients.sl.EXPECT().
-			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
-			Return(false, nil)
-
-		clients.sl.EXPECT().
-			IsSlotLeader(mock.Anything, pool[0].ID, currentSlot-1, epoch).
-			Return(true, nil)
-
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(currentSlot-1, currentSlot, 5000))
 		clients.bf.EXPECT().
 			GetBlockBySlot(mock.Anything, currentSlot-1).
-			Return(blockfrost.Block{}, errors.New("Not Found"))
-
-		// handle currentSlot
-		clients.sl.EXPECT().
-			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
-			Return(false, nil)
+			Return(blockfrost.Block{}, bf.ErrNotFound)
 
-		clients.sl.EXPECT().
-			IsSlotLeader(mock.Anything, pool[0].ID, currentSlot, epoch).
-			Return(true, nil)
+		mockDBClient.mock.
+			ExpectExec("INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pool[0].ID, currentSlot-1, BlockOutcomeMissed, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		clients.bf.EXPECT().
 			GetBlockBySlot(mock.Anything, currentSlot).
-			Return(blockfrost.Block{}, errors.New("Not Found"))
+			Return(blockfrost.Block{}, bf.ErrNotFound)
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pool[0].ID, currentSlot, BlockOutcomeMissed, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		// save state
 		mockDBClient.mock.
@@ -680,6 +869,7 @@ cardano_validator_watcher_consecutive_missed_blocks{epoch="100", pool_id="pool-0
 			clients.cardano,
 			clients.bf,
 			clients.sl,
+			setupTimeline(t),
 			pool,
 			registry.metrics,
 			mockDBClient.db,
@@ -737,6 +927,19 @@ cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0",
 					AddRow(epoch, initialSlot, time.Now()),
 			)
 
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
 		clients.sl.EXPECT().
 			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
 			Return(false, nil)
@@ -755,27 +958,18 @@ cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0",
 				nil,
 			)
 
-		// handle currentSlot +1 with a missed block
-		clients.sl.EXPECT().
-			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
-			Return(false, nil)
-
+		// the leader slots are loaded once for the whole range of slots
 		clients.sl.EXPECT().
-			IsSlotLeader(mock.Anything, pool[0].ID, currentSlot-1, epoch).
-			Return(true, nil)
-
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(currentSlot-1, currentSlot, 5000))
 		clients.bf.EXPECT().
 			GetBlockBySlot(mock.Anything, currentSlot-1).
-			Return(blockfrost.Block{}, errors.New("Not Found"))
-
-		// handle currentSlot with a valid block
-		clients.sl.EXPECT().
-			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
-			Return(false, nil)
+			Return(blockfrost.Block{}, bf.ErrNotFound)
 
-		clients.sl.EXPECT().
-			IsSlotLeader(mock.Anything, pool[0].ID, currentSlot, epoch).
-			Return(true, nil)
+		mockDBClient.mock.
+			ExpectExec("INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pool[0].ID, currentSlot-1, BlockOutcomeMissed, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		clients.bf.EXPECT().
 			GetBlockBySlot(
@@ -789,6 +983,11 @@ cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0",
 				}, nil,
 			)
 
+		mockDBClient.mock.
+			ExpectExec("INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pool[0].ID, currentSlot, BlockOutcomeValidated, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
 		// save state
 		mockDBClient.mock.
 			ExpectExec("INSERT OR REPLACE INTO block_watcher_state (id, epoch, slot, last_update) VALUES (1, ?, ?, ?)").
@@ -804,6 +1003,7 @@ cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0",
 			clients.cardano,
 			clients.bf,
 			clients.sl,
+			setupTimeline(t),
 			pool,
 			registry.metrics,
 			mockDBClient.db,
@@ -843,12 +1043,12 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 			"cardano_validator_watcher_validated_blocks_total",
 		}
 
+		// epoch 100 ends at slot 43631999 with 432000 slots per epoch
 		epoch := 100
-		initialSlot := 99
-		currentSlot := 100
-		currentHeight := 100
+		initialSlot := 43631997
+		lastSlotInEpoch := 43631999
 		nextEpoch := 101
-		nextEpochSlot := 102
+		nextEpochSlot := 43632001
 		nextEpochHeight := 102
 
 		ctx := setupContextWithTimeout(t, time.Second*30)
@@ -868,11 +1068,24 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 					AddRow(epoch, initialSlot, time.Now()),
 			)
 
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
 		clients.sl.EXPECT().
 			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
 			Return(false, nil)
 
-		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, nextEpochHeight, epoch).Return(5000, nil)
+		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, nextEpochSlot, epoch).Return(5000, nil)
 
 		clients.bf.EXPECT().
 			GetLatestBlock(mock.Anything).
@@ -886,58 +1099,15 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 				nil,
 			)
 
-		// retrieve the latest block from the previous epoch and calculate the last slot of the previous epoch
-		clients.bf.EXPECT().
-			GetLastBlockFromPreviousEpoch(mock.Anything, epoch).Return(
-			blockfrost.Block{
-				Height:    currentHeight,
-				Slot:      currentSlot,
-				Epoch:     epoch,
-				EpochSlot: 431999,
-			},
-			nil,
-		)
-
-		// handle currentSlot
-		clients.sl.EXPECT().
-			IsSlotsEmpty(
-				mock.Anything,
-				pool[0].ID,
-				epoch,
-			).
-			Return(false, nil)
-
-		clients.sl.EXPECT().
-			IsSlotLeader(
-				mock.Anything,
-				pool[0].ID,
-				currentSlot,
-				epoch,
-			).
-			Return(false, nil)
-
-		// handle currentSlot+1 which is the last slot for the epoch
-		clients.sl.EXPECT().
-			IsSlotsEmpty(
-				mock.Anything,
-				pool[0].ID,
-				epoch,
-			).
-			Return(false, nil)
-
+		// the leader slots are loaded once for the whole range of slots
 		clients.sl.EXPECT().
-			IsSlotLeader(
-				mock.Anything,
-				pool[0].ID,
-				currentSlot+1,
-				epoch,
-			).
-			Return(false, nil)
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(5000))
 
 		// Save state before transitioning to the next epoch
 		mockDBClient.mock.
 			ExpectExec("INSERT OR REPLACE INTO block_watcher_state (id, epoch, slot, last_update) VALUES (1, ?, ?, ?)").
-			WithArgs(epoch, currentSlot+1, AnyTime{}).
+			WithArgs(epoch, lastSlotInEpoch, AnyTime{}).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		// handle next epoch
@@ -952,12 +1122,32 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 			blockfrost.Epoch{Epoch: nextEpoch},
 		).Return(nil)
 
+		clients.sl.EXPECT().
+			IsSlotsEmpty(mock.Anything, pool[0].ID, nextEpoch).
+			Return(false, nil)
+
 		mockDBClient.mock.
 			ExpectExec("INSERT OR REPLACE INTO block_watcher_state (id, epoch, slot, last_update) VALUES (1, ?, ?, ?)").
-			WithArgs(nextEpoch, currentSlot+1, AnyTime{}).
+			WithArgs(nextEpoch, lastSlotInEpoch, AnyTime{}).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, nextEpochHeight, nextEpoch).Return(5000, nil)
+		// reconcile the closed epoch
+		clients.bf.EXPECT().
+			GetBlockDistributionByPool(mock.Anything, epoch, pool[0].ID).
+			Return([]string{}, nil)
+		clients.sl.EXPECT().
+			GetSlotLeaders(mock.Anything, pool[0].ID, epoch).
+			Return(slotleader.Schedule{PoolID: pool[0].ID, Epoch: epoch}, nil)
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at FROM block_outcomes WHERE epoch = ? AND pool_id = ? ORDER BY slot").
+			WithArgs(epoch, pool[0].ID).
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id", "slot", "outcome", "block_hash", "block_height", "slot_leader", "recorded_at"}))
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO epoch_reconciliations (epoch, pool_id, scheduled_slots, on_chain_blocks, validated_blocks, unscheduled_blocks, unrecorded_blocks, unrecorded_slots, reconciled_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pool[0].ID, 0, 0, 0, "[]", "[]", "[]", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, nextEpochSlot, nextEpoch).Return(5000, nil)
 
 		clients.bf.EXPECT().GetLatestBlock(mock.Anything).
 			Return(
@@ -971,23 +1161,10 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 				nil,
 			)
 
-		// handle nextEpochSlot
-		clients.sl.EXPECT().
-			IsSlotsEmpty(
-				mock.Anything,
-				pool[0].ID,
-				nextEpoch,
-			).
-			Return(false, nil)
-
+		// the leader slots are loaded once for the whole range of slots
 		clients.sl.EXPECT().
-			IsSlotLeader(
-				mock.Anything,
-				pool[0].ID,
-				nextEpochSlot,
-				nextEpoch,
-			).
-			Return(false, nil)
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, nextEpoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(5000))
 
 		// save state with the new epoch
 		mockDBClient.mock.
@@ -1004,6 +1181,7 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 			clients.cardano,
 			clients.bf,
 			clients.sl,
+			setupTimeline(t),
 			pool,
 			registry.metrics,
 			mockDBClient.db,
@@ -1047,6 +1225,19 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 					AddRow(epoch, initialSlot, time.Now()),
 			)
 
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
 		clients.sl.EXPECT().
 			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
 			Return(true, nil)
@@ -1059,6 +1250,7 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 			clients.cardano,
 			clients.bf,
 			clients.sl,
+			setupTimeline(t),
 			pool,
 			registry.metrics,
 			mockDBClient.db,
@@ -1102,9 +1294,18 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 					AddRow(epoch, initialSlot, time.Now()),
 			)
 
-		clients.sl.EXPECT().
-			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
-			Return(true, nil).Times(1)
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
 
 		clients.bf.EXPECT().
 			GetLatestBlock(mock.Anything).
@@ -1118,19 +1319,10 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 				nil,
 			)
 
-		// handle currentSlot - 1
-		clients.sl.EXPECT().IsSlotsEmpty(
-			mock.Anything,
-			pool[0].ID,
-			epoch,
-		).Return(true, nil)
-
-		// handle currentSlot
-		clients.sl.EXPECT().IsSlotsEmpty(
-			mock.Anything,
-			pool[0].ID,
-			epoch,
-		).Return(true, nil)
+		// the leader slots are loaded once for the whole range of slots
+		clients.sl.EXPECT().
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange())
 
 		// save state
 		mockDBClient.mock.
@@ -1147,6 +1339,7 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 			clients.cardano,
 			clients.bf,
 			clients.sl,
+			setupTimeline(t),
 			pool,
 			registry.metrics,
 			mockDBClient.db,
@@ -1158,7 +1351,7 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 		require.Equal(t, watcher.state.Slot, currentSlot)
 	})
 
-	t.Run("SadPath_BlockFrostIsNotReachable", func(t *testing.T) {
+	t.Run("SadPath_UnableToLoadScheduleDoesNotAdvanceState", func(t *testing.T) {
 		t.Parallel()
 
 		clients := setupClients(t)
@@ -1167,8 +1360,10 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 
 		epoch := 100
 		initialSlot := 99
+		currentSlot := 101
+		currentHeight := 101
 
-		ctx := setupContextWithTimeout(t, time.Second*10)
+		ctx := setupContextWithTimeout(t, time.Second*5)
 
 		// Setup Mocks for Dependencies
 		clients.bf.EXPECT().
@@ -1185,19 +1380,57 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 					AddRow(epoch, initialSlot, time.Now()),
 			)
 
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
 		clients.sl.EXPECT().
 			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
 			Return(false, nil)
 
+		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, currentHeight, epoch).Return(5000, nil)
+
+		clients.bf.EXPECT().
+			GetLatestBlock(mock.Anything).
+			Return(
+				blockfrost.Block{
+					Height: currentHeight,
+					Slot:   currentSlot,
+					Hash:   "hash",
+					Epoch:  epoch,
+				},
+				nil,
+			)
+
+		clients.sl.EXPECT().
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			Return(nil, errors.New("database is locked"))
+
+		// the state is saved on the slot preceding the range
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_watcher_state (id, epoch, slot, last_update) VALUES (1, ?, ?, ?)").
+			WithArgs(epoch, initialSlot, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
 		options := BlockWatcherOptions{
 			RefreshInterval: time.Minute * 1,
 		}
 		healthStore := NewHealthStore()
-		healthStore.SetHealth(false)
+		healthStore.SetHealth(true)
 		watcher := NewBlockWatcher(
 			clients.cardano,
 			clients.bf,
 			clients.sl,
+			setupTimeline(t),
 			pool,
 			registry.metrics,
 			mockDBClient.db,
@@ -1206,6 +1439,987 @@ cardano_validator_watcher_validated_blocks_total{epoch="101",pool_id="pool-0",po
 		)
 		err := watcher.Start(ctx)
 		require.ErrorIs(t, err, context.Canceled)
-		require.Equal(t, watcher.state.Slot, initialSlot)
+		require.Equal(t, initialSlot, watcher.state.Slot)
+	})
+
+	t.Run("GoodPath_DegradedPoolDoesNotBlockOtherPools", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_validated_blocks_total number of validated blocks in the current epoch
+# TYPE cardano_validator_watcher_validated_blocks_total counter
+cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 0
+cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-1", pool_instance="pool-1", pool_name="pool-1"} 1
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_validated_blocks_total",
+		}
+
+		// pool-0 failed to refresh its schedule, pool-1 is healthy
+		pools := append(setupPool(), pools.Pool{ID: "pool-1", Instance: "pool-1", Key: "key", Name: "pool-1"})
+
+		epoch := 100
+		initialSlot := 99
+		currentSlot := 101
+		currentHeight := 101
+
+		ctx := setupContextWithTimeout(t, time.Second*10)
+
+		// Setup Mocks for Dependencies
+		clients.bf.EXPECT().
+			GetLatestEpoch(mock.Anything).
+			Return(
+				blockfrost.Epoch{Epoch: epoch},
+				nil,
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, slot, last_update FROM block_watcher_state LIMIT 1").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "slot", "last_update"}).
+					AddRow(epoch, initialSlot, time.Now()),
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
+		clients.sl.EXPECT().
+			IsSlotsEmpty(mock.Anything, pools[0].ID, epoch).
+			Return(false, slotleader.ErrScheduleNotFound)
+		clients.sl.EXPECT().
+			IsSlotsEmpty(mock.Anything, pools[1].ID, epoch).
+			Return(false, nil)
+
+		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pools[0].ID, currentHeight, epoch).Return(0, slotleader.ErrScheduleNotFound)
+		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pools[1].ID, currentHeight, epoch).Return(5000, nil)
+
+		clients.bf.EXPECT().GetLatestBlock(mock.Anything).
+			Return(
+				blockfrost.Block{
+					Height: currentHeight,
+					Slot:   currentSlot,
+					Hash:   "hash",
+					Epoch:  epoch,
+				}, nil,
+			)
+
+		clients.sl.EXPECT().
+			GetLeaderSlotsInRange(mock.Anything, pools[0].ID, epoch, mock.Anything, mock.Anything).
+			Return(nil, slotleader.ErrScheduleNotFound)
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_watcher_backlog (pool_id, epoch, first_slot) VALUES (?, ?, ?)").
+			WithArgs(pools[0].ID, epoch, initialSlot+1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		clients.sl.EXPECT().
+			GetLeaderSlotsInRange(mock.Anything, pools[1].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(currentSlot-1, 5000))
+		clients.bf.EXPECT().
+			GetBlockBySlot(
+				mock.Anything,
+				currentSlot-1,
+			).
+			Return(
+				blockfrost.Block{
+					SlotLeader: pools[1].ID,
+					Epoch:      epoch,
+				},
+				nil,
+			)
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pools[1].ID, currentSlot-1, BlockOutcomeValidated, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_watcher_state (id, epoch, slot, last_update) VALUES (1, ?, ?, ?)").
+			WithArgs(epoch, currentSlot, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		options := BlockWatcherOptions{
+			RefreshInterval: time.Minute * 1,
+		}
+		healthStore := NewHealthStore()
+		healthStore.SetHealth(true)
+		watcher := NewBlockWatcher(
+			clients.cardano,
+			clients.bf,
+			clients.sl,
+			setupTimeline(t),
+			pools,
+			registry.metrics,
+			mockDBClient.db,
+			healthStore,
+			options,
+		)
+
+		err := watcher.Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, currentSlot, watcher.state.Slot)
+		require.Equal(t, BlockBacklog{PoolID: pools[0].ID, Epoch: epoch, FirstSlot: initialSlot + 1}, watcher.poolBacklogs[pools[0].ID])
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("SadPath_UpstreamErrorIsNotCountedAsMissedBlock", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		epoch := 100
+		initialSlot := 99
+		currentSlot := 101
+		currentHeight := 101
+
+		ctx := setupContextWithTimeout(t, time.Second*5)
+
+		// Setup Mocks for Dependencies
+		clients.bf.EXPECT().
+			GetLatestEpoch(mock.Anything).
+			Return(
+				blockfrost.Epoch{Epoch: epoch},
+				nil,
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, slot, last_update FROM block_watcher_state LIMIT 1").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "slot", "last_update"}).
+					AddRow(epoch, initialSlot, time.Now()),
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
+		clients.sl.EXPECT().
+			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
+			Return(false, nil)
+
+		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, currentHeight, epoch).Return(5000, nil)
+
+		clients.bf.EXPECT().
+			GetLatestBlock(mock.Anything).
+			Return(
+				blockfrost.Block{
+					Height: currentHeight,
+					Slot:   currentSlot,
+					Hash:   "hash",
+					Epoch:  epoch,
+				},
+				nil,
+			)
+
+		clients.sl.EXPECT().
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(currentSlot))
+
+		// a failing provider must not be mistaken for a missed block
+		clients.bf.EXPECT().
+			GetBlockBySlot(mock.Anything, currentSlot).
+			Return(blockfrost.Block{}, bf.ErrUpstreamUnavailable)
+
+		// the state is saved on the slot preceding the leader slot so it is retried
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_watcher_state (id, epoch, slot, last_update) VALUES (1, ?, ?, ?)").
+			WithArgs(epoch, currentSlot-1, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		options := BlockWatcherOptions{
+			RefreshInterval: time.Minute * 1,
+		}
+		healthStore := NewHealthStore()
+		healthStore.SetHealth(true)
+		watcher := NewBlockWatcher(
+			clients.cardano,
+			clients.bf,
+			clients.sl,
+			setupTimeline(t),
+			pool,
+			registry.metrics,
+			mockDBClient.db,
+			healthStore,
+			options,
+		)
+		err := watcher.Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, currentSlot-1, watcher.state.Slot)
+	})
+
+	t.Run("GoodPath_ConfirmationDepthInBlocksDelaysFinalization", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		epoch := 100
+		initialSlot := 99
+		currentSlot := 110
+		currentHeight := 110
+		confirmedSlot := 105
+
+		ctx := setupContextWithTimeout(t, time.Second*10)
+
+		// Setup Mocks for Dependencies
+		clients.bf.EXPECT().
+			GetLatestEpoch(mock.Anything).
+			Return(
+				blockfrost.Epoch{Epoch: epoch},
+				nil,
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, slot, last_update FROM block_watcher_state LIMIT 1").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "slot", "last_update"}).
+					AddRow(epoch, initialSlot, time.Now()),
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
+		clients.sl.EXPECT().
+			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
+			Return(false, nil)
+
+		clients.bf.EXPECT().
+			GetLatestBlock(mock.Anything).
+			Return(
+				blockfrost.Block{
+					Height: currentHeight,
+					Slot:   currentSlot,
+					Hash:   "hash",
+					Epoch:  epoch,
+				},
+				nil,
+			)
+
+		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, currentSlot, epoch).Return(5000, nil)
+
+		// the block 3 blocks behind the tip is the last confirmed one
+		clients.bf.EXPECT().
+			GetBlockByHeight(mock.Anything, currentHeight-3).
+			Return(blockfrost.Block{Height: currentHeight - 3, Slot: confirmedSlot, Epoch: epoch}, nil)
+
+		// the leader slot is after the confirmed slot so it must not be finalized yet
+		clients.sl.EXPECT().
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(currentSlot-2, 5000))
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_watcher_state (id, epoch, slot, last_update) VALUES (1, ?, ?, ?)").
+			WithArgs(epoch, confirmedSlot, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		options := BlockWatcherOptions{
+			RefreshInterval:   time.Minute * 1,
+			ConfirmationDepth: 3,
+			ConfirmationUnit:  ConfirmationUnitBlocks,
+		}
+		healthStore := NewHealthStore()
+		healthStore.SetHealth(true)
+		watcher := NewBlockWatcher(
+			clients.cardano,
+			clients.bf,
+			clients.sl,
+			setupTimeline(t),
+			pool,
+			registry.metrics,
+			mockDBClient.db,
+			healthStore,
+			options,
+		)
+
+		err := watcher.Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, confirmedSlot, watcher.state.Slot)
+		clients.bf.AssertNotCalled(t, "GetBlockBySlot", mock.Anything, currentSlot-2)
+	})
+
+	t.Run("GoodPath_ConfirmationDepthInSlots", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_validated_blocks_total number of validated blocks in the current epoch
+# TYPE cardano_validator_watcher_validated_blocks_total counter
+cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_validated_blocks_total",
+		}
+
+		epoch := 100
+		initialSlot := 99
+		currentSlot := 110
+		currentHeight := 110
+		confirmedSlot := currentSlot - 5
+
+		ctx := setupContextWithTimeout(t, time.Second*10)
+
+		// Setup Mocks for Dependencies
+		clients.bf.EXPECT().
+			GetLatestEpoch(mock.Anything).
+			Return(
+				blockfrost.Epoch{Epoch: epoch},
+				nil,
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, slot, last_update FROM block_watcher_state LIMIT 1").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "slot", "last_update"}).
+					AddRow(epoch, initialSlot, time.Now()),
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
+		clients.sl.EXPECT().
+			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
+			Return(false, nil)
+
+		clients.bf.EXPECT().
+			GetLatestBlock(mock.Anything).
+			Return(
+				blockfrost.Block{
+					Height: currentHeight,
+					Slot:   currentSlot,
+					Hash:   "hash",
+					Epoch:  epoch,
+				},
+				nil,
+			)
+
+		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, currentSlot, epoch).Return(5000, nil)
+
+		// only the first leader slot is deep enough to be finalized
+		clients.sl.EXPECT().
+			GetLeaderSlotsInRange(mock.Anything, pool[0].ID, epoch, mock.Anything, mock.Anything).
+			RunAndReturn(leaderSlotsInRange(confirmedSlot-1, currentSlot-1, 5000))
+		clients.bf.EXPECT().
+			GetBlockBySlot(mock.Anything, confirmedSlot-1).
+			Return(blockfrost.Block{SlotLeader: pool[0].ID, Epoch: epoch}, nil)
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pool[0].ID, confirmedSlot-1, BlockOutcomeValidated, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_watcher_state (id, epoch, slot, last_update) VALUES (1, ?, ?, ?)").
+			WithArgs(epoch, confirmedSlot, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		options := BlockWatcherOptions{
+			RefreshInterval:   time.Minute * 1,
+			ConfirmationDepth: 5,
+			ConfirmationUnit:  ConfirmationUnitSlots,
+		}
+		healthStore := NewHealthStore()
+		healthStore.SetHealth(true)
+		watcher := NewBlockWatcher(
+			clients.cardano,
+			clients.bf,
+			clients.sl,
+			setupTimeline(t),
+			pool,
+			registry.metrics,
+			mockDBClient.db,
+			healthStore,
+			options,
+		)
+
+		err := watcher.Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, confirmedSlot, watcher.state.Slot)
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("GoodPath_RolledBackBlockIsReclassifiedAsOrphaned", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_orphaned_blocks_total number of orphaned blocks in the current epoch
+# TYPE cardano_validator_watcher_orphaned_blocks_total counter
+cardano_validator_watcher_orphaned_blocks_total{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1
+# HELP cardano_validator_watcher_validated_blocks_total number of validated blocks in the current epoch
+# TYPE cardano_validator_watcher_validated_blocks_total counter
+cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 0
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_orphaned_blocks_total",
+			"cardano_validator_watcher_validated_blocks_total",
+		}
+
+		epoch := 100
+		currentSlot := 110
+		currentHeight := 110
+		rolledBackSlot := 95
+
+		ctx := setupContextWithTimeout(t, time.Second*10)
+
+		// Setup Mocks for Dependencies
+		clients.bf.EXPECT().
+			GetLatestEpoch(mock.Anything).
+			Return(
+				blockfrost.Epoch{Epoch: epoch},
+				nil,
+			)
+
+		// all the slots up to the tip have already been processed
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, slot, last_update FROM block_watcher_state LIMIT 1").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "slot", "last_update"}).
+					AddRow(epoch, currentSlot, time.Now()),
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"pool_id", "outcome", "count"}).
+					AddRow(pool[0].ID, BlockOutcomeValidated, 1),
+			)
+
+		clients.sl.EXPECT().
+			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
+			Return(false, nil)
+
+		clients.bf.EXPECT().
+			GetLatestBlock(mock.Anything).
+			Return(
+				blockfrost.Block{
+					Height: currentHeight,
+					Slot:   currentSlot,
+					Hash:   "hash",
+					Epoch:  epoch,
+				},
+				nil,
+			)
+
+		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, currentSlot, epoch).Return(5000, nil)
+
+		// the validated block recorded in the re-check window is no longer on-chain
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at FROM block_outcomes WHERE outcome = ? AND slot >= ? ORDER BY slot").
+			WithArgs(BlockOutcomeValidated, currentSlot-100).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "pool_id", "slot", "outcome", "block_hash", "block_height", "slot_leader", "recorded_at"}).
+					AddRow(epoch, pool[0].ID, rolledBackSlot, BlockOutcomeValidated, "rolled-back-hash", 95, pool[0].ID, time.Now()),
+			)
+		clients.bf.EXPECT().
+			GetBlockBySlot(mock.Anything, rolledBackSlot).
+			Return(blockfrost.Block{}, bf.ErrNotFound)
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(epoch, pool[0].ID, rolledBackSlot, BlockOutcomeOrphaned, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		// the counters are rebuilt from the recorded outcomes
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"pool_id", "outcome", "count"}).
+					AddRow(pool[0].ID, BlockOutcomeOrphaned, 1),
+			)
+
+		options := BlockWatcherOptions{
+			RefreshInterval: time.Minute * 1,
+			RecheckWindow:   100,
+		}
+		healthStore := NewHealthStore()
+		healthStore.SetHealth(true)
+		watcher := NewBlockWatcher(
+			clients.cardano,
+			clients.bf,
+			clients.sl,
+			setupTimeline(t),
+			pool,
+			registry.metrics,
+			mockDBClient.db,
+			healthStore,
+			options,
+		)
+
+		err := watcher.Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("GoodPath_RolledBackBlockOfClosedEpochIsReconciledAgain", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_reconciliation_block_mismatch difference between the blocks forged on-chain and the validated blocks recorded for the closed epoch
+# TYPE cardano_validator_watcher_reconciliation_block_mismatch gauge
+cardano_validator_watcher_reconciliation_block_mismatch{epoch="99", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 0
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_reconciliation_block_mismatch",
+		}
+
+		epoch := 100
+		currentSlot := 110
+		currentHeight := 110
+		closedEpoch := 99
+		rolledBackSlot := 95
+
+		ctx := setupContextWithTimeout(t, time.Second*10)
+
+		// Setup Mocks for Dependencies
+		clients.bf.EXPECT().
+			GetLatestEpoch(mock.Anything).
+			Return(
+				blockfrost.Epoch{Epoch: epoch},
+				nil,
+			)
+
+		// all the slots up to the tip have already been processed
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, slot, last_update FROM block_watcher_state LIMIT 1").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "slot", "last_update"}).
+					AddRow(epoch, currentSlot, time.Now()),
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"pool_id", "outcome", "count"}).
+					AddRow(pool[0].ID, BlockOutcomeValidated, 1),
+			)
+
+		clients.sl.EXPECT().
+			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
+			Return(false, nil)
+
+		clients.bf.EXPECT().
+			GetLatestBlock(mock.Anything).
+			Return(
+				blockfrost.Block{
+					Height: currentHeight,
+					Slot:   currentSlot,
+					Hash:   "hash",
+					Epoch:  epoch,
+				},
+				nil,
+			)
+
+		clients.sl.EXPECT().GetNextSlotLeader(mock.Anything, pool[0].ID, currentSlot, epoch).Return(5000, nil)
+
+		// the validated block of the closed epoch recorded in the re-check window is no longer on-chain
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at FROM block_outcomes WHERE outcome = ? AND slot >= ? ORDER BY slot").
+			WithArgs(BlockOutcomeValidated, currentSlot-100).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "pool_id", "slot", "outcome", "block_hash", "block_height", "slot_leader", "recorded_at"}).
+					AddRow(closedEpoch, pool[0].ID, rolledBackSlot, BlockOutcomeValidated, "rolled-back-hash", 95, pool[0].ID, time.Now()),
+			)
+		clients.bf.EXPECT().
+			GetBlockBySlot(mock.Anything, rolledBackSlot).
+			Return(blockfrost.Block{}, bf.ErrNotFound)
+
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(closedEpoch, pool[0].ID, rolledBackSlot, BlockOutcomeOrphaned, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
+		// the closed epoch is reconciled again with the reclassified block
+		clients.bf.EXPECT().
+			GetBlockDistributionByPool(mock.Anything, closedEpoch, pool[0].ID).
+			Return([]string{}, nil)
+		clients.sl.EXPECT().
+			GetSlotLeaders(mock.Anything, pool[0].ID, closedEpoch).
+			Return(slotleader.Schedule{PoolID: pool[0].ID, Epoch: closedEpoch, Slots: []int{rolledBackSlot}}, nil)
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at FROM block_outcomes WHERE epoch = ? AND pool_id = ? ORDER BY slot").
+			WithArgs(closedEpoch, pool[0].ID).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "pool_id", "slot", "outcome", "block_hash", "block_height", "slot_leader", "recorded_at"}).
+					AddRow(closedEpoch, pool[0].ID, rolledBackSlot, BlockOutcomeOrphaned, "", 0, "", time.Now()),
+			)
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO epoch_reconciliations (epoch, pool_id, scheduled_slots, on_chain_blocks, validated_blocks, unscheduled_blocks, unrecorded_blocks, unrecorded_slots, reconciled_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)").
+			WithArgs(closedEpoch, pool[0].ID, 1, 0, 0, "[]", "[]", "[]", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		options := BlockWatcherOptions{
+			RefreshInterval: time.Minute * 1,
+			RecheckWindow:   100,
+		}
+		healthStore := NewHealthStore()
+		healthStore.SetHealth(true)
+		watcher := NewBlockWatcher(
+			clients.cardano,
+			clients.bf,
+			clients.sl,
+			setupTimeline(t),
+			pool,
+			registry.metrics,
+			mockDBClient.db,
+			healthStore,
+			options,
+		)
+
+		err := watcher.Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+		require.Empty(t, watcher.pendingReconciliations)
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("SadPath_BlockFrostIsNotReachable", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		epoch := 100
+		initialSlot := 99
+
+		ctx := setupContextWithTimeout(t, time.Second*10)
+
+		// Setup Mocks for Dependencies
+		clients.bf.EXPECT().
+			GetLatestEpoch(mock.Anything).
+			Return(
+				blockfrost.Epoch{Epoch: epoch},
+				nil,
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, slot, last_update FROM block_watcher_state LIMIT 1").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"epoch", "slot", "last_update"}).
+					AddRow(epoch, initialSlot, time.Now()),
+			)
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, epoch, first_slot FROM block_watcher_backlog").
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "epoch", "first_slot"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT epoch, pool_id FROM slots WHERE epoch < ? UNION SELECT epoch, pool_id FROM block_outcomes WHERE epoch < ? EXCEPT SELECT epoch, pool_id FROM epoch_reconciliations ORDER BY epoch, pool_id").
+			WillReturnRows(sqlmock.NewRows([]string{"epoch", "pool_id"}))
+
+		mockDBClient.mock.
+			ExpectQuery("SELECT pool_id, outcome, COUNT(*) AS count FROM block_outcomes WHERE epoch = ? GROUP BY pool_id, outcome").
+			WithArgs(epoch).
+			WillReturnRows(sqlmock.NewRows([]string{"pool_id", "outcome", "count"}))
+
+		clients.sl.EXPECT().
+			IsSlotsEmpty(mock.Anything, pool[0].ID, epoch).
+			Return(false, nil)
+
+		options := BlockWatcherOptions{
+			RefreshInterval: time.Minute * 1,
+		}
+		healthStore := NewHealthStore()
+		healthStore.SetHealth(false)
+		watcher := NewBlockWatcher(
+			clients.cardano,
+			clients.bf,
+			clients.sl,
+			setupTimeline(t),
+			pool,
+			registry.metrics,
+			mockDBClient.db,
+			healthStore,
+			options,
+		)
+		err := watcher.Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, watcher.state.Slot, initialSlot)
+	})
+}
+
+func TestBlockWatcher_ProcessSlots(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_RetriedSlotIsNotCountedTwice", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_orphaned_blocks_total number of orphaned blocks in the current epoch
+# TYPE cardano_validator_watcher_orphaned_blocks_total counter
+cardano_validator_watcher_orphaned_blocks_total{epoch="100", pool_id="pool-1", pool_instance="pool-1", pool_name="pool-1"} 1
+# HELP cardano_validator_watcher_validated_blocks_total number of validated blocks in the current epoch
+# TYPE cardano_validator_watcher_validated_blocks_total counter
+cardano_validator_watcher_validated_blocks_total{epoch="100", pool_id="pool-0", pool_instance="pool-0", pool_name="pool-0"} 1
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_orphaned_blocks_total",
+			"cardano_validator_watcher_validated_blocks_total",
+		}
+
+		// both monitored pools lead the same slot
+		monitored := pools.Pools{
+			{ID: "pool-0", Instance: "pool-0", Key: "key", Name: "pool-0"},
+			{ID: "pool-1", Instance: "pool-1", Key: "key", Name: "pool-1"},
+		}
+		epoch := 100
+		slot := 1000
+		createOutcomeQuery := "INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+
+		for _, pool := range monitored {
+			clients.sl.EXPECT().
+				GetLeaderSlotsInRange(mock.Anything, pool.ID, epoch, slot, slot).
+				Return([]int{slot}, nil)
+		}
+		clients.bf.EXPECT().
+			GetBlockBySlot(mock.Anything, slot).
+			Return(blockfrost.Block{Hash: "hash", SlotLeader: monitored[0].ID, Epoch: epoch}, nil)
+
+		// the outcome of the second pool fails to be saved during the first pass
+		mockDBClient.mock.
+			ExpectExec(createOutcomeQuery).
+			WithArgs(epoch, monitored[0].ID, slot, BlockOutcomeValidated, "hash", 0, monitored[0].ID, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mockDBClient.mock.
+			ExpectExec(createOutcomeQuery).
+			WithArgs(epoch, monitored[1].ID, slot, BlockOutcomeOrphaned, "hash", 0, monitored[0].ID, AnyTime{}).
+			WillReturnError(errors.New("database is locked"))
+		mockDBClient.mock.
+			ExpectExec(createOutcomeQuery).
+			WithArgs(epoch, monitored[0].ID, slot, BlockOutcomeValidated, "hash", 0, monitored[0].ID, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mockDBClient.mock.
+			ExpectExec(createOutcomeQuery).
+			WithArgs(epoch, monitored[1].ID, slot, BlockOutcomeOrphaned, "hash", 0, monitored[0].ID, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), monitored, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+
+		processed, err := watcher.processSlots(context.Background(), epoch, slot, slot)
+		require.ErrorContains(t, err, "database is locked")
+		require.Equal(t, slot-1, processed)
+
+		processed, err = watcher.processSlots(context.Background(), epoch, slot, slot)
+		require.NoError(t, err)
+		require.Equal(t, slot, processed)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+	t.Run("GoodPath_SkippedSlotsProcessedOnceScheduleIsKnown", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		monitored := setupPool()
+		epoch := 100
+		createOutcomeQuery := "INSERT OR IGNORE INTO block_outcomes (epoch, pool_id, slot, outcome, block_hash, block_height, slot_leader, recorded_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+
+		// the schedule of the pool is unknown during the first two passes
+		clients.sl.EXPECT().
+			GetLeaderSlotsInRange(mock.Anything, monitored[0].ID, epoch, 1000, 1010).
+			Return(nil, slotleader.ErrScheduleNotFound).
+			Once()
+		mockDBClient.mock.
+			ExpectExec("INSERT OR REPLACE INTO block_watcher_backlog (pool_id, epoch, first_slot) VALUES (?, ?, ?)").
+			WithArgs(monitored[0].ID, epoch, 1000).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		clients.sl.EXPECT().
+			GetLeaderSlotsInRange(mock.Anything, monitored[0].ID, epoch, 1000, 1020).
+			Return(nil, slotleader.ErrScheduleNotFound).
+			Once()
+
+		// its refresh succeeded, the skipped slots are processed with the new ones
+		clients.sl.EXPECT().
+			GetLeaderSlotsInRange(mock.Anything, monitored[0].ID, epoch, 1000, 1030).
+			Return([]int{1005, 1025}, nil).
+			Once()
+		clients.bf.EXPECT().
+			GetBlockBySlot(mock.Anything, 1005).
+			Return(blockfrost.Block{Hash: "hash-1005", SlotLeader: monitored[0].ID, Epoch: epoch}, nil)
+		clients.bf.EXPECT().
+			GetBlockBySlot(mock.Anything, 1025).
+			Return(blockfrost.Block{}, bf.ErrNotFound)
+		mockDBClient.mock.
+			ExpectExec(createOutcomeQuery).
+			WithArgs(epoch, monitored[0].ID, 1005, BlockOutcomeValidated, "hash-1005", 0, monitored[0].ID, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mockDBClient.mock.
+			ExpectExec(createOutcomeQuery).
+			WithArgs(epoch, monitored[0].ID, 1025, BlockOutcomeMissed, "", 0, "", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mockDBClient.mock.
+			ExpectExec("DELETE FROM block_watcher_backlog WHERE pool_id = ?").
+			WithArgs(monitored[0].ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), monitored, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+
+		for _, passSlots := range [][2]int{{1000, 1010}, {1011, 1020}, {1021, 1030}} {
+			processed, err := watcher.processSlots(context.Background(), epoch, passSlots[0], passSlots[1])
+			require.NoError(t, err)
+			require.Equal(t, passSlots[1], processed)
+		}
+		require.Empty(t, watcher.poolBacklogs)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+		require.Equal(t, 1.0, testutil.ToFloat64(registry.metrics.ValidatedBlocks.WithLabelValues("pool-0", "pool-0", "pool-0", "100")))
+		require.Equal(t, 1.0, testutil.ToFloat64(registry.metrics.MissedBlocks.WithLabelValues("pool-0", "pool-0", "pool-0", "100")))
+	})
+
+	t.Run("SadPath_FailedSkippedSlotDoesNotMoveStateBack", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		monitored := setupPool()
+		epoch := 100
+
+		clients.sl.EXPECT().
+			GetLeaderSlotsInRange(mock.Anything, monitored[0].ID, epoch, 900, 1010).
+			Return([]int{950}, nil)
+		clients.bf.EXPECT().
+			GetBlockBySlot(mock.Anything, 950).
+			Return(blockfrost.Block{}, errors.New("connection reset"))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), monitored, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		watcher.poolBacklogs[monitored[0].ID] = BlockBacklog{PoolID: monitored[0].ID, Epoch: epoch, FirstSlot: 900}
+
+		processed, err := watcher.processSlots(context.Background(), epoch, 1001, 1010)
+		require.ErrorContains(t, err, "connection reset")
+		require.Equal(t, 1000, processed)
+		require.Contains(t, watcher.poolBacklogs, monitored[0].ID)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+	})
+}
+
+func TestBlockWatcher_DropBacklogs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_BacklogsOfClosedEpochAreDropped", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		monitored := pools.Pools{
+			{ID: "pool-0", Instance: "pool-0", Key: "key", Name: "pool-0"},
+			{ID: "pool-1", Instance: "pool-1", Key: "key", Name: "pool-1"},
+		}
+		mockDBClient.mock.
+			ExpectExec("DELETE FROM block_watcher_backlog WHERE pool_id = ?").
+			WithArgs(monitored[0].ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		watcher := NewBlockWatcher(clients.cardano, clients.bf, clients.sl, setupTimeline(t), monitored, registry.metrics, mockDBClient.db, NewHealthStore(), BlockWatcherOptions{})
+		watcher.poolBacklogs[monitored[0].ID] = BlockBacklog{PoolID: monitored[0].ID, Epoch: 100, FirstSlot: 900}
+		watcher.poolBacklogs[monitored[1].ID] = BlockBacklog{PoolID: monitored[1].ID, Epoch: 101, FirstSlot: 1100}
+
+		watcher.dropBacklogs(context.Background(), 100)
+		require.Equal(t, map[string]BlockBacklog{
+			monitored[1].ID: {PoolID: monitored[1].ID, Epoch: 101, FirstSlot: 1100},
+		}, watcher.poolBacklogs)
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
 	})
 }
@@ -0,0 +1,260 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
+	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+)
+
+// kesWatcherLatestBlocks is the number of latest blocks of a pool inspected at each refresh.
+const kesWatcherLatestBlocks = 5
+
+// KESWatcherOptions represents the KES watcher options
+type KESWatcherOptions struct {
+	RefreshInterval time.Duration
+}
+
+// opCertState tracks the operational certificate counters seen on-chain for a pool.
+type opCertState struct {
+	// lastBlock is the hash of the latest block of the pool already inspected
+	lastBlock string
+	// counter is the highest operational certificate counter seen on-chain
+	counter int
+	seen    bool
+}
+
+// KESWatcher monitors the KES keys and the operational certificates of the pools
+type KESWatcher struct {
+	logger      *slog.Logger
+	blockfrost  blockfrost.Client
+	cardano     cardano.CardanoClient
+	timeline    *cardanotime.Timeline
+	metrics     *metrics.Collection
+	pools       pools.Pools
+	healthStore *HealthStore
+	opts        KESWatcherOptions
+
+	opCerts map[string]*opCertState
+}
+
+var _ Watcher = (*KESWatcher)(nil)
+
+// NewKESWatcher creates a new KES watcher
+func NewKESWatcher(
+	blockfrost blockfrost.Client,
+	cardano cardano.CardanoClient,
+	timeline *cardanotime.Timeline,
+	metrics *metrics.Collection,
+	pools pools.Pools,
+	healthStore *HealthStore,
+	opts KESWatcherOptions,
+) *KESWatcher {
+	logger := slog.With(
+		slog.String("component", "kes-watcher"),
+	)
+	return &KESWatcher{
+		logger:      logger,
+		blockfrost:  blockfrost,
+		cardano:     cardano,
+		timeline:    timeline,
+		metrics:     metrics,
+		pools:       pools,
+		healthStore: healthStore,
+		opts:        opts,
+		opCerts:     make(map[string]*opCertState),
+	}
+}
+
+// Start starts the KES watcher.
+// It returns an error if the watcher fails to start or if an error is encountered during the process.
+func (w *KESWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.opts.RefreshInterval)
+	defer ticker.Stop()
+
+	var previousHealthStatus bool
+	for {
+		currentHealthStatus := w.healthStore.GetHealth()
+		w.handleHealthTransition(ctx, previousHealthStatus, currentHealthStatus)
+		previousHealthStatus = currentHealthStatus
+
+		if currentHealthStatus {
+			if err := w.start(ctx); err != nil {
+				w.logger.ErrorContext(ctx, "watcher started but failed with the following error", slog.String("error", err.Error()))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			w.logger.InfoContext(ctx, "stopping watcher")
+			return fmt.Errorf("context done in watcher: %w", ctx.Err())
+		case <-ticker.C:
+			continue
+		}
+	}
+}
+
+// start checks the operational certificate and the KES key of each active pool
+func (w *KESWatcher) start(ctx context.Context) error {
+	var errs []error
+	for _, pool := range w.pools.GetActivePools() {
+		if err := w.checkOnChainOpCert(ctx, pool); err != nil {
+			errs = append(errs, fmt.Errorf("unable to check the on-chain operational certificate of pool %s: %w", pool.Name, err))
+			continue
+		}
+
+		if pool.OpCert == "" {
+			continue
+		}
+		if err := w.checkKESPeriod(ctx, pool); err != nil {
+			errs = append(errs, fmt.Errorf("unable to check the KES period of pool %s: %w", pool.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// handleHealthTransition handles the transition of the KES watcher's health status.
+// It compares the previous and current health states, and logs a warning if the KES watcher
+// is not ready, or an info message if it is ready.
+func (w *KESWatcher) handleHealthTransition(ctx context.Context, previous bool, current bool) {
+	if previous != current {
+		if !w.healthStore.GetHealth() {
+			w.logger.WarnContext(ctx,
+				"💔 kes watcher is not ready.",
+			)
+		} else {
+			w.logger.InfoContext(ctx, "💚 kes watcher is ready")
+		}
+	}
+}
+
+// checkOnChainOpCert inspects the operational certificate counter of the latest blocks
+// forged by the pool. A counter lower than a previous block means that a block was
+// forged with an older operational certificate, e.g. by a stale producer.
+func (w *KESWatcher) checkOnChainOpCert(ctx context.Context, pool pools.Pool) error {
+	hashes, err := w.blockfrost.GetPoolLatestBlocks(ctx, pool.ID, kesWatcherLatestBlocks)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the latest blocks: %w", err)
+	}
+
+	state, ok := w.opCerts[pool.ID]
+	if !ok {
+		state = &opCertState{}
+		w.opCerts[pool.ID] = state
+	}
+
+	// the blocks are returned from the most recent, only the new ones are inspected from the oldest
+	newBlocks := hashes
+	if idx := slices.Index(hashes, state.lastBlock); idx >= 0 {
+		newBlocks = hashes[:idx]
+	}
+
+	for _, hash := range slices.Backward(newBlocks) {
+		block, err := w.blockfrost.GetBlockByHash(ctx, hash)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve block %s: %w", hash, err)
+		}
+		state.lastBlock = hash
+		if block.OPCertCounter == nil {
+			continue
+		}
+
+		counter, err := strconv.Atoi(*block.OPCertCounter)
+		if err != nil {
+			return fmt.Errorf("unable to parse the operational certificate counter of block %s: %w", hash, err)
+		}
+		w.metrics.OpCertCounter.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(float64(counter))
+
+		if state.seen && counter < state.counter {
+			w.metrics.OpCertCounterRegressions.WithLabelValues(pool.Name, pool.ID, pool.Instance).Inc()
+			w.logger.ErrorContext(ctx,
+				fmt.Sprintf("🚨 pool %s forged a block with an operational certificate counter lower than a previous block", pool.Name),
+				slog.String("pool_id", pool.ID),
+				slog.String("block", hash),
+				slog.Int("op_cert_counter", counter),
+				slog.Int("highest_op_cert_counter", state.counter),
+			)
+			continue
+		}
+		state.counter = counter
+		state.seen = true
+	}
+
+	return nil
+}
+
+// checkKESPeriod collects the remaining KES periods of the operational certificate of
+// the pool and checks that its counter can be used to forge blocks.
+func (w *KESWatcher) checkKESPeriod(ctx context.Context, pool pools.Pool) error {
+	info, err := w.cardano.KESPeriodInfo(ctx, pool.OpCert)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve kes period info: %w", err)
+	}
+
+	remaining := info.RemainingKESPeriods()
+	w.metrics.KESRemainingPeriods.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(float64(remaining))
+
+	expiry := w.kesExpiry(info)
+	w.metrics.KESExpiryTimestamp.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(float64(expiry.Unix()))
+	if remaining == 0 {
+		w.logger.ErrorContext(ctx,
+			fmt.Sprintf("🚨 the KES key of pool %s has expired", pool.Name),
+			slog.String("pool_id", pool.ID),
+			slog.String("op_cert", pool.OpCert),
+		)
+	}
+
+	// The counter of the certificate on disk must be the one seen on-chain, or the next one
+	// once the operational certificate is rotated.
+	state := w.opCerts[pool.ID]
+	stale := state != nil && state.seen &&
+		(info.OnDiskOpCertCounter < state.counter || info.OnDiskOpCertCounter > state.counter+1)
+	if stale {
+		w.metrics.OpCertStale.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(1)
+		w.logger.ErrorContext(ctx,
+			fmt.Sprintf("🚨 the operational certificate of pool %s is rejected by the counter seen on-chain", pool.Name),
+			slog.String("pool_id", pool.ID),
+			slog.String("op_cert", pool.OpCert),
+			slog.Int("on_disk_op_cert_counter", info.OnDiskOpCertCounter),
+			slog.Int("on_chain_op_cert_counter", state.counter),
+		)
+	} else {
+		w.metrics.OpCertStale.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(0)
+	}
+
+	if info.NodeStateOpCertCounter != nil && *info.NodeStateOpCertCounter != info.OnDiskOpCertCounter {
+		w.logger.WarnContext(ctx,
+			fmt.Sprintf("the node of pool %s does not use the operational certificate on disk, a restart may be pending", pool.Name),
+			slog.String("pool_id", pool.ID),
+			slog.Int("on_disk_op_cert_counter", info.OnDiskOpCertCounter),
+			slog.Int("node_state_op_cert_counter", *info.NodeStateOpCertCounter),
+		)
+	}
+
+	return nil
+}
+
+// kesExpiry returns the expiry of the KES key. It is computed from the remaining slots
+// when cardano-cli is not able to convert the expiry slot to a time.
+func (w *KESWatcher) kesExpiry(info cardano.ClientKESPeriodInfoResponse) time.Time {
+	if info.KESKeyExpiry != nil {
+		return *info.KESKeyExpiry
+	}
+
+	remaining := info.RemainingKESPeriods()
+	if remaining == 0 {
+		return time.Now()
+	}
+	slots := (remaining-1)*info.SlotsPerKESPeriod + info.RemainingSlotsInKESPeriod
+	return time.Now().Add(time.Duration(slots) * w.timeline.SlotLength())
+}
@@ -0,0 +1,200 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	bfAPI "github.com/blockfrost/blockfrost-go"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func opCertBlock(counter string) bfAPI.Block {
+	return bfAPI.Block{OPCertCounter: &counter}
+}
+
+func TestKESWatcher_Start(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GoodPath_AllMetricsCollected", func(t *testing.T) {
+		t.Parallel()
+
+		pools := setupPools(t)
+		pools[0].OpCert = "config/pool-0.node.cert"
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_kes_expiry_timestamp_seconds Unix timestamp at which the KES key of the operational certificate expires
+# TYPE cardano_validator_watcher_kes_expiry_timestamp_seconds gauge
+cardano_validator_watcher_kes_expiry_timestamp_seconds{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1704067200
+# HELP cardano_validator_watcher_kes_remaining_periods Number of KES periods left before the KES key of the operational certificate expires
+# TYPE cardano_validator_watcher_kes_remaining_periods gauge
+cardano_validator_watcher_kes_remaining_periods{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 42
+# HELP cardano_validator_watcher_op_cert_counter Operational certificate counter of the latest block forged by the pool
+# TYPE cardano_validator_watcher_op_cert_counter gauge
+cardano_validator_watcher_op_cert_counter{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 5
+# HELP cardano_validator_watcher_op_cert_stale Whether the operational certificate on disk is rejected by the counter seen on-chain: 1 = stale, 0 = valid
+# TYPE cardano_validator_watcher_op_cert_stale gauge
+cardano_validator_watcher_op_cert_stale{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 0
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_kes_expiry_timestamp_seconds",
+			"cardano_validator_watcher_kes_remaining_periods",
+			"cardano_validator_watcher_op_cert_counter",
+			"cardano_validator_watcher_op_cert_counter_regressions_total",
+			"cardano_validator_watcher_op_cert_stale",
+		}
+
+		ctx := setupContextWithTimeout(t, time.Second*10)
+
+		// Setup Mocks for Dependencies
+		clients.bf.EXPECT().
+			GetPoolLatestBlocks(mock.Anything, pools[0].ID, kesWatcherLatestBlocks).
+			Return([]string{"block-2", "block-1"}, nil)
+		clients.bf.EXPECT().GetBlockByHash(mock.Anything, "block-1").Return(opCertBlock("4"), nil)
+		clients.bf.EXPECT().GetBlockByHash(mock.Anything, "block-2").Return(opCertBlock("5"), nil)
+
+		expiry := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		clients.cardano.EXPECT().
+			KESPeriodInfo(mock.Anything, pools[0].OpCert).
+			Return(cardano.ClientKESPeriodInfoResponse{
+				CurrentKESPeriod:    420,
+				EndKESInterval:      462,
+				OnDiskOpCertCounter: 5,
+				KESKeyExpiry:        &expiry,
+			}, nil)
+
+		healthStore := NewHealthStore()
+		healthStore.SetHealth(true)
+		watcher := NewKESWatcher(
+			clients.bf,
+			clients.cardano,
+			setupTimeline(t),
+			registry.metrics,
+			pools,
+			healthStore,
+			KESWatcherOptions{RefreshInterval: time.Minute * 1},
+		)
+
+		err := watcher.Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("GoodPath_DetectOpCertCounterRegression", func(t *testing.T) {
+		t.Parallel()
+
+		pools := setupPools(t)
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_op_cert_counter Operational certificate counter of the latest block forged by the pool
+# TYPE cardano_validator_watcher_op_cert_counter gauge
+cardano_validator_watcher_op_cert_counter{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 4
+# HELP cardano_validator_watcher_op_cert_counter_regressions_total Number of blocks forged by the pool with an operational certificate counter lower than a previous block
+# TYPE cardano_validator_watcher_op_cert_counter_regressions_total counter
+cardano_validator_watcher_op_cert_counter_regressions_total{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_op_cert_counter",
+			"cardano_validator_watcher_op_cert_counter_regressions_total",
+		}
+
+		// the second refresh only fetches the new block, forged with an older certificate
+		clients.bf.EXPECT().
+			GetPoolLatestBlocks(mock.Anything, pools[0].ID, kesWatcherLatestBlocks).
+			Return([]string{"block-1"}, nil).Once()
+		clients.bf.EXPECT().
+			GetPoolLatestBlocks(mock.Anything, pools[0].ID, kesWatcherLatestBlocks).
+			Return([]string{"block-2", "block-1"}, nil).Once()
+		clients.bf.EXPECT().GetBlockByHash(mock.Anything, "block-1").Return(opCertBlock("5"), nil).Once()
+		clients.bf.EXPECT().GetBlockByHash(mock.Anything, "block-2").Return(opCertBlock("4"), nil).Once()
+
+		watcher := NewKESWatcher(clients.bf, clients.cardano, setupTimeline(t), registry.metrics, pools, NewHealthStore(), KESWatcherOptions{})
+		require.NoError(t, watcher.start(context.Background()))
+		require.NoError(t, watcher.start(context.Background()))
+		require.Equal(t, 5, watcher.opCerts[pools[0].ID].counter)
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err := testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("SadPath_StaleOpCertOnDisk", func(t *testing.T) {
+		t.Parallel()
+
+		pools := setupPools(t)
+		pools[0].OpCert = "config/pool-0.node.cert"
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+		timeline := setupTimeline(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_kes_remaining_periods Number of KES periods left before the KES key of the operational certificate expires
+# TYPE cardano_validator_watcher_kes_remaining_periods gauge
+cardano_validator_watcher_kes_remaining_periods{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1
+# HELP cardano_validator_watcher_op_cert_stale Whether the operational certificate on disk is rejected by the counter seen on-chain: 1 = stale, 0 = valid
+# TYPE cardano_validator_watcher_op_cert_stale gauge
+cardano_validator_watcher_op_cert_stale{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_kes_remaining_periods",
+			"cardano_validator_watcher_op_cert_stale",
+		}
+
+		clients.bf.EXPECT().
+			GetPoolLatestBlocks(mock.Anything, pools[0].ID, kesWatcherLatestBlocks).
+			Return([]string{"block-1"}, nil)
+		clients.bf.EXPECT().GetBlockByHash(mock.Anything, "block-1").Return(opCertBlock("6"), nil)
+
+		// without expiry from cardano-cli, it is computed from the remaining slots
+		clients.cardano.EXPECT().
+			KESPeriodInfo(mock.Anything, pools[0].OpCert).
+			Return(cardano.ClientKESPeriodInfoResponse{
+				CurrentKESPeriod:          461,
+				EndKESInterval:            462,
+				RemainingSlotsInKESPeriod: 3600,
+				OnDiskOpCertCounter:       5,
+				SlotsPerKESPeriod:         129600,
+			}, nil)
+
+		watcher := NewKESWatcher(clients.bf, clients.cardano, timeline, registry.metrics, pools, NewHealthStore(), KESWatcherOptions{})
+		before := time.Now()
+		require.NoError(t, watcher.start(context.Background()))
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err := testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+
+		expiry := testutil.ToFloat64(registry.metrics.KESExpiryTimestamp.WithLabelValues(pools[0].Name, pools[0].ID, pools[0].Instance))
+		require.InDelta(t, float64(before.Add(time.Hour).Unix()), expiry, 5)
+	})
+
+	t.Run("SadPath_UnableToRetrieveBlock", func(t *testing.T) {
+		t.Parallel()
+
+		pools := setupPools(t)
+		pools[0].OpCert = "config/pool-0.node.cert"
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+
+		clients.bf.EXPECT().
+			GetPoolLatestBlocks(mock.Anything, pools[0].ID, kesWatcherLatestBlocks).
+			Return([]string{"block-1"}, nil)
+		clients.bf.EXPECT().GetBlockByHash(mock.Anything, "block-1").Return(bfAPI.Block{}, errors.New("service unavailable"))
+
+		watcher := NewKESWatcher(clients.bf, clients.cardano, setupTimeline(t), registry.metrics, pools, NewHealthStore(), KESWatcherOptions{})
+		err := watcher.start(context.Background())
+		require.ErrorContains(t, err, "unable to retrieve block block-1")
+	})
+}
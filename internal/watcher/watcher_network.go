@@ -8,13 +8,10 @@ import (
 	"time"
 
 	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
+	"github.com/kilnfi/cardano-validator-watcher/internal/cardano/cardanotime"
 	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
 )
 
-const (
-	EpochDuration = 5
-)
-
 // NetworkWatcherOptions represents the network watcher options
 type NetworkWatcherOptions struct {
 	Network         string
@@ -25,6 +22,7 @@ type NetworkWatcherOptions struct {
 type NetworkWatcher struct {
 	logger      *slog.Logger
 	blockfrost  blockfrost.Client
+	timeline    *cardanotime.Timeline
 	metrics     *metrics.Collection
 	healthStore *HealthStore
 	opts        NetworkWatcherOptions
@@ -35,6 +33,7 @@ var _ Watcher = (*NetworkWatcher)(nil)
 // NewNetworkWatcher creates a new network watcher
 func NewNetworkWatcher(
 	blockfrost blockfrost.Client,
+	timeline *cardanotime.Timeline,
 	metrics *metrics.Collection,
 	healthStore *HealthStore,
 	opts NetworkWatcherOptions,
@@ -45,6 +44,7 @@ func NewNetworkWatcher(
 	return &NetworkWatcher{
 		logger:      logger,
 		blockfrost:  blockfrost,
+		timeline:    timeline,
 		metrics:     metrics,
 		healthStore: healthStore,
 		opts:        opts,
@@ -113,7 +113,7 @@ func (w *NetworkWatcher) handleHealthTransition(ctx context.Context, previous bo
 
 // collectChainInfo collects information about the chain
 func (w *NetworkWatcher) collectChainInfo(ctx context.Context) error {
-	w.metrics.EpochDuration.Set(float64(EpochDuration))
+	w.metrics.EpochDuration.Set(w.timeline.EpochDuration().Hours() / 24)
 
 	chainID, err := w.blockfrost.GetGenesisInfo(ctx)
 	if err != nil {
@@ -150,7 +150,7 @@ func TestNetworkWatcher_Start(t *testing.T) {
 		}
 		healthStore := NewHealthStore()
 		healthStore.SetHealth(true)
-		watcher := NewNetworkWatcher(clients.bf, registry.metrics, healthStore, options)
+		watcher := NewNetworkWatcher(clients.bf, setupTimeline(t), registry.metrics, healthStore, options)
 		err := watcher.Start(ctx)
 		require.ErrorIs(t, err, context.Canceled)
 		b := bytes.NewBufferString(registry.metricsExpectedOutput)
@@ -171,7 +171,7 @@ func TestNetworkWatcher_Start(t *testing.T) {
 		}
 		healthStore := NewHealthStore()
 		healthStore.SetHealth(false)
-		watcher := NewNetworkWatcher(clients.bf, registry.metrics, healthStore, options)
+		watcher := NewNetworkWatcher(clients.bf, setupTimeline(t), registry.metrics, healthStore, options)
 		err := watcher.Start(ctx)
 		require.ErrorIs(t, err, context.Canceled)
 	})
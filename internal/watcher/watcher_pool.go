@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"strconv"
+	"net"
+	"net/http"
 	"time"
 
 	bfAPI "github.com/blockfrost/blockfrost-go"
 	"github.com/dgraph-io/ristretto/v2"
+	"github.com/jmoiron/sqlx"
 	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
 	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
 	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
@@ -18,6 +20,10 @@ import (
 type PoolWatcherOptions struct {
 	Network         string
 	RefreshInterval time.Duration
+	// RelayProbeInterval is the interval at which the relays of the pools are dialed, 0 disables the probe.
+	RelayProbeInterval time.Duration
+	// PledgeWarningThreshold is the margin over the declared pledge, in percent, under which the live pledge is reported.
+	PledgeWarningThreshold float64
 }
 
 // PoolWatcher represents a watcher for a set of Cardano pools.
@@ -27,9 +33,13 @@ type PoolWatcher struct {
 	metrics     *metrics.Collection
 	pools       pools.Pools
 	poolstats   pools.PoolStats
+	db          *sqlx.DB
 	healthStore *HealthStore
 	cache       *ristretto.Cache[string, interface{}]
 	cacheTTL    time.Duration
+	relayProber *relayProber
+	httpClient  *http.Client
+	owners      map[string][]string
 	opts        PoolWatcherOptions
 }
 
@@ -42,6 +52,7 @@ func NewPoolWatcher(
 	blockfrost blockfrost.Client,
 	metrics *metrics.Collection,
 	pools pools.Pools,
+	db *sqlx.DB,
 	healthStore *HealthStore,
 	opts PoolWatcherOptions,
 ) (*PoolWatcher, error) {
@@ -64,9 +75,13 @@ func NewPoolWatcher(
 		metrics:     metrics,
 		pools:       pools,
 		poolstats:   pools.GetPoolStats(),
+		db:          db,
 		healthStore: healthStore,
 		cache:       cache,
 		cacheTTL:    2 * opts.RefreshInterval,
+		relayProber: newRelayProber(net.DefaultResolver, (&net.Dialer{}).DialContext),
+		httpClient:  &http.Client{Timeout: metadataFetchTimeout},
+		owners:      make(map[string][]string),
 		opts:        opts,
 	}, nil
 }
@@ -78,6 +93,10 @@ func (w *PoolWatcher) Start(ctx context.Context) error {
 	ticker := time.NewTicker(w.opts.RefreshInterval)
 	defer ticker.Stop()
 
+	if w.opts.RelayProbeInterval > 0 {
+		go w.runRelayProbe(ctx)
+	}
+
 	var previousHealthStatus bool
 	for {
 		currentHealthStatus := w.healthStore.GetHealth()
@@ -131,6 +150,9 @@ func (w *PoolWatcher) fetch(ctx context.Context) error {
 			return fmt.Errorf("unable to retrieve metadata for pool '%s': %w", pool.ID, err)
 		}
 
+		// Check the metadata served by the pool against its on-chain registration
+		w.checkPoolMetadata(ctx, pool, poolMetadata)
+
 		// Get pool details
 		poolInfo, err := w.getPoolInfo(ctx, pool.ID)
 		if err != nil {
@@ -140,20 +162,9 @@ func (w *PoolWatcher) fetch(ctx context.Context) error {
 		// Set pool saturation level
 		w.metrics.PoolsSaturationLevel.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(poolInfo.LiveSaturation)
 
-		// check if the pool has met its pledge requirements and set the metric accordingly
-		livePledge, err := strconv.Atoi(poolInfo.LivePledge)
-		if err != nil {
-			return fmt.Errorf("unable to convert live pledge to integer: %w", err)
-		}
-
-		declaredPledge, err := strconv.Atoi(poolInfo.DeclaredPledge)
-		if err != nil {
-			return fmt.Errorf("unable to convert declared pledge to integer: %w", err)
-		}
-		if livePledge >= declaredPledge {
-			w.metrics.PoolsPledgeMet.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(1)
-		} else {
-			w.metrics.PoolsPledgeMet.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(0)
+		// check if the pool has met its pledge requirements and set the metrics accordingly
+		if err := w.collectPledge(ctx, pool, poolInfo); err != nil {
+			return fmt.Errorf("unable to collect pledge for pool '%s': %w", pool.ID, err)
 		}
 
 		// Get number of relay servers associated with the pool
@@ -173,6 +184,11 @@ func (w *PoolWatcher) fetch(ctx context.Context) error {
 		} else {
 			w.metrics.PoolsDRepRegistered.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(0)
 		}
+
+		// detect the changes of the parameters registered by the pool
+		if err := w.checkPoolParams(ctx, pool, poolInfo, poolRelays); err != nil {
+			return fmt.Errorf("unable to check parameters for pool '%s': %w", pool.ID, err)
+		}
 	}
 
 	return nil
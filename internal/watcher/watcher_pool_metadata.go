@@ -0,0 +1,175 @@
+package watcher
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	bfAPI "github.com/blockfrost/blockfrost-go"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// metadataFetchTimeout bounds the download of the metadata of a pool.
+	metadataFetchTimeout = 10 * time.Second
+	// metadataCheckInterval is the interval at which the metadata of a pool is downloaded again.
+	metadataCheckInterval = 15 * time.Minute
+
+	// The limits of the pool metadata defined by CIP-6 and the ledger.
+	metadataMaxSize           = 512
+	metadataMaxNameLength     = 50
+	metadataMinTickerLength   = 3
+	metadataMaxTickerLength   = 5
+	metadataMaxHomepageLength = 64
+	metadataMaxDescLength     = 255
+)
+
+// MetadataStatus is the outcome of the integrity check of the metadata of a pool.
+type MetadataStatus string
+
+const (
+	MetadataStatusValid              MetadataStatus = "valid"
+	MetadataStatusNotRegistered      MetadataStatus = "not_registered"
+	MetadataStatusUnreachable        MetadataStatus = "unreachable"
+	MetadataStatusTooLarge           MetadataStatus = "too_large"
+	MetadataStatusHashMismatch       MetadataStatus = "hash_mismatch"
+	MetadataStatusInvalidJSON        MetadataStatus = "invalid_json"
+	MetadataStatusInvalidName        MetadataStatus = "invalid_name"
+	MetadataStatusInvalidTicker      MetadataStatus = "invalid_ticker"
+	MetadataStatusInvalidHomepage    MetadataStatus = "invalid_homepage"
+	MetadataStatusInvalidDescription MetadataStatus = "invalid_description"
+)
+
+// poolMetadataFile is the metadata file of a pool as defined by CIP-6.
+// The fields are pointers to tell a missing or null key from an empty string,
+// a key with a value that is not a string fails the decoding.
+type poolMetadataFile struct {
+	Name        *string `json:"name"`
+	Ticker      *string `json:"ticker"`
+	Description *string `json:"description"`
+	Homepage    *string `json:"homepage"`
+}
+
+// checkPoolMetadata downloads the metadata of the pool from the URL registered
+// on-chain, and checks it against the registered hash and the CIP-6 schema.
+// The outcome is cached for metadataCheckInterval.
+func (w *PoolWatcher) checkPoolMetadata(ctx context.Context, pool pools.Pool, metadata bfAPI.PoolMetadata) {
+	if _, ok := w.cache.Get(pool.ID + "_metadata_check"); ok {
+		return
+	}
+
+	status, detail := w.verifyPoolMetadata(ctx, metadata)
+	w.cache.SetWithTTL(pool.ID+"_metadata_check", status, 1, metadataCheckInterval)
+	w.cache.Wait()
+
+	// the previous status of the pool is dropped since the status is a label
+	w.metrics.PoolMetadataValid.DeletePartialMatch(prometheus.Labels{"pool_id": pool.ID})
+	if status == MetadataStatusValid {
+		w.metrics.PoolMetadataValid.WithLabelValues(pool.Name, pool.ID, pool.Instance, string(status)).Set(1)
+		return
+	}
+	w.metrics.PoolMetadataValid.WithLabelValues(pool.Name, pool.ID, pool.Instance, string(status)).Set(0)
+
+	url := ""
+	if metadata.URL != nil {
+		url = *metadata.URL
+	}
+	w.logger.WarnContext(ctx,
+		fmt.Sprintf("the metadata of pool %s is invalid", pool.Name),
+		slog.String("pool_id", pool.ID),
+		slog.String("url", url),
+		slog.String("reason", string(status)),
+		slog.String("detail", detail),
+	)
+}
+
+// verifyPoolMetadata returns the status of the metadata of a pool and a detail
+// explaining an invalid status.
+func (w *PoolWatcher) verifyPoolMetadata(ctx context.Context, metadata bfAPI.PoolMetadata) (MetadataStatus, string) {
+	if metadata.URL == nil || metadata.Hash == nil {
+		return MetadataStatusNotRegistered, "no metadata registered on-chain"
+	}
+
+	content, err := w.downloadPoolMetadata(ctx, *metadata.URL)
+	if err != nil {
+		return MetadataStatusUnreachable, err.Error()
+	}
+	if len(content) > metadataMaxSize {
+		return MetadataStatusTooLarge, fmt.Sprintf("metadata larger than %d bytes", metadataMaxSize)
+	}
+
+	hash := blake2b.Sum256(content)
+	if !strings.EqualFold(hex.EncodeToString(hash[:]), *metadata.Hash) {
+		return MetadataStatusHashMismatch, fmt.Sprintf("hash %x does not match the registered hash %s", hash, *metadata.Hash)
+	}
+
+	file := poolMetadataFile{}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return MetadataStatusInvalidJSON, err.Error()
+	}
+	return validatePoolMetadataFile(file)
+}
+
+// downloadPoolMetadata returns the content of the metadata file, read up to
+// one byte over the maximum size to tell a file that is too large.
+func (w *PoolWatcher) downloadPoolMetadata(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	resp, err := w.httpClient.Do(req) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("unable to download metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to download metadata: unexpected status code %d", resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, metadataMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read metadata: %w", err)
+	}
+	return content, nil
+}
+
+// validatePoolMetadataFile checks the fields of the metadata against the limits of CIP-6.
+// All the fields are required.
+func validatePoolMetadataFile(file poolMetadataFile) (MetadataStatus, string) {
+	if file.Name == nil {
+		return MetadataStatusInvalidName, "name is required"
+	}
+	if length := utf8.RuneCountInString(*file.Name); length == 0 || length > metadataMaxNameLength {
+		return MetadataStatusInvalidName, fmt.Sprintf("name must have between 1 and %d characters", metadataMaxNameLength)
+	}
+	if file.Ticker == nil {
+		return MetadataStatusInvalidTicker, "ticker is required"
+	}
+	if length := utf8.RuneCountInString(*file.Ticker); length < metadataMinTickerLength || length > metadataMaxTickerLength {
+		return MetadataStatusInvalidTicker, fmt.Sprintf("ticker must have between %d and %d characters", metadataMinTickerLength, metadataMaxTickerLength)
+	}
+	if file.Homepage == nil {
+		return MetadataStatusInvalidHomepage, "homepage is required"
+	}
+	if length := utf8.RuneCountInString(*file.Homepage); length == 0 || length > metadataMaxHomepageLength {
+		return MetadataStatusInvalidHomepage, fmt.Sprintf("homepage must have between 1 and %d characters", metadataMaxHomepageLength)
+	}
+	if file.Description == nil {
+		return MetadataStatusInvalidDescription, "description is required"
+	}
+	if utf8.RuneCountInString(*file.Description) > metadataMaxDescLength {
+		return MetadataStatusInvalidDescription, fmt.Sprintf("description must have at most %d characters", metadataMaxDescLength)
+	}
+	return MetadataStatusValid, ""
+}
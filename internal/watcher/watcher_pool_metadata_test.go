@@ -0,0 +1,178 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	bfAPI "github.com/blockfrost/blockfrost-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+const poolMetadataJSON = `{"name":"Pool 0","ticker":"POOL0","description":"The pool 0","homepage":"https://pool-0.example.com"}`
+
+func metadataHash(content string) string {
+	hash := blake2b.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+// setupMetadataServer serves the given metadata and returns its URL.
+func setupMetadataServer(t *testing.T, status int, content string) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, _ *http.Request) {
+		res.WriteHeader(status)
+		_, _ = res.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/pool-0.json"
+}
+
+func TestPoolWatcher_CheckPoolMetadata(t *testing.T) {
+	t.Parallel()
+	pools := setupPools(t)
+
+	tests := []struct {
+		name    string
+		status  int
+		content string
+		hash    string
+		reason  MetadataStatus
+	}{
+		{
+			name:    "GoodPath_ValidMetadata",
+			status:  http.StatusOK,
+			content: poolMetadataJSON,
+			hash:    strings.ToUpper(metadataHash(poolMetadataJSON)),
+			reason:  MetadataStatusValid,
+		},
+		{
+			name:    "SadPath_HashMismatch",
+			status:  http.StatusOK,
+			content: poolMetadataJSON,
+			hash:    metadataHash(`{"name":"Pool 0","ticker":"POOL0"}`),
+			reason:  MetadataStatusHashMismatch,
+		},
+		{
+			name:    "SadPath_Unreachable",
+			status:  http.StatusNotFound,
+			content: "not found",
+			hash:    metadataHash(poolMetadataJSON),
+			reason:  MetadataStatusUnreachable,
+		},
+		{
+			name:    "SadPath_TooLarge",
+			status:  http.StatusOK,
+			content: strings.Repeat(" ", metadataMaxSize) + poolMetadataJSON,
+			hash:    metadataHash(strings.Repeat(" ", metadataMaxSize) + poolMetadataJSON),
+			reason:  MetadataStatusTooLarge,
+		},
+		{
+			name:    "SadPath_InvalidTicker",
+			status:  http.StatusOK,
+			content: `{"name":"Pool 0","ticker":"POOL00","description":"The pool 0","homepage":"https://pool-0.example.com"}`,
+			hash:    metadataHash(`{"name":"Pool 0","ticker":"POOL00","description":"The pool 0","homepage":"https://pool-0.example.com"}`),
+			reason:  MetadataStatusInvalidTicker,
+		},
+		{
+			name:    "SadPath_InvalidJSON",
+			status:  http.StatusOK,
+			content: `{"name":`,
+			hash:    metadataHash(`{"name":`),
+			reason:  MetadataStatusInvalidJSON,
+		},
+		{
+			name:    "SadPath_MissingDescription",
+			status:  http.StatusOK,
+			content: `{"name":"Pool 0","ticker":"POOL0","homepage":"https://pool-0.example.com"}`,
+			hash:    metadataHash(`{"name":"Pool 0","ticker":"POOL0","homepage":"https://pool-0.example.com"}`),
+			reason:  MetadataStatusInvalidDescription,
+		},
+		{
+			name:    "SadPath_NullDescription",
+			status:  http.StatusOK,
+			content: `{"name":"Pool 0","ticker":"POOL0","description":null,"homepage":"https://pool-0.example.com"}`,
+			hash:    metadataHash(`{"name":"Pool 0","ticker":"POOL0","description":null,"homepage":"https://pool-0.example.com"}`),
+			reason:  MetadataStatusInvalidDescription,
+		},
+		{
+			name:    "SadPath_WrongTypedDescription",
+			status:  http.StatusOK,
+			content: `{"name":"Pool 0","ticker":"POOL0","description":42,"homepage":"https://pool-0.example.com"}`,
+			hash:    metadataHash(`{"name":"Pool 0","ticker":"POOL0","description":42,"homepage":"https://pool-0.example.com"}`),
+			reason:  MetadataStatusInvalidJSON,
+		},
+		{
+			name:    "SadPath_MissingHomepage",
+			status:  http.StatusOK,
+			content: `{"name":"Pool 0","ticker":"POOL0","description":"The pool 0"}`,
+			hash:    metadataHash(`{"name":"Pool 0","ticker":"POOL0","description":"The pool 0"}`),
+			reason:  MetadataStatusInvalidHomepage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clients := setupClients(t)
+			registry := setupRegistry(t)
+
+			value := "0"
+			if tt.reason == MetadataStatusValid {
+				value = "1"
+			}
+			registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_pool_metadata_valid Whether the metadata served by the pool matches its on-chain hash and CIP-6, with the reason of the last check (0 or 1)
+# TYPE cardano_validator_watcher_pool_metadata_valid gauge
+cardano_validator_watcher_pool_metadata_valid{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0",reason="` + string(tt.reason) + `"} ` + value + `
+`
+			registry.metricsUnderTest = []string{"cardano_validator_watcher_pool_metadata_valid"}
+
+			url := setupMetadataServer(t, tt.status, tt.content)
+			watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, nil, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+			require.NoError(t, err)
+
+			watcher.checkPoolMetadata(context.Background(), pools[0], bfAPI.PoolMetadata{URL: &url, Hash: &tt.hash})
+
+			b := bytes.NewBufferString(registry.metricsExpectedOutput)
+			err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("GoodPath_ReasonReplacedOnNextCheck", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_pool_metadata_valid Whether the metadata served by the pool matches its on-chain hash and CIP-6, with the reason of the last check (0 or 1)
+# TYPE cardano_validator_watcher_pool_metadata_valid gauge
+cardano_validator_watcher_pool_metadata_valid{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0",reason="valid"} 1
+`
+		registry.metricsUnderTest = []string{"cardano_validator_watcher_pool_metadata_valid"}
+
+		url := setupMetadataServer(t, http.StatusOK, poolMetadataJSON)
+		hash := metadataHash(poolMetadataJSON)
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, nil, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+		require.NoError(t, err)
+
+		// the pool registers its metadata, the check is cached until then
+		watcher.checkPoolMetadata(context.Background(), pools[0], bfAPI.PoolMetadata{})
+		watcher.cache.Del(pools[0].ID + "_metadata_check")
+		watcher.checkPoolMetadata(context.Background(), pools[0], bfAPI.PoolMetadata{URL: &url, Hash: &hash})
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,220 @@
+package watcher
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	bfAPI "github.com/blockfrost/blockfrost-go"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+)
+
+// PoolParams is the snapshot of the parameters registered on-chain by a pool.
+type PoolParams struct {
+	Margin         float64  `json:"margin"`
+	FixedCost      string   `json:"fixed_cost"`
+	DeclaredPledge string   `json:"declared_pledge"`
+	RewardAccount  string   `json:"reward_account"`
+	Owners         []string `json:"owners"`
+	Relays         []string `json:"relays"`
+	// RetiringEpoch is the epoch at which the retirement of the pool is scheduled, 0 when none is.
+	RetiringEpoch int `json:"retiring_epoch"`
+}
+
+// PoolParamChange is a change of a registered parameter between two snapshots of a pool.
+type PoolParamChange struct {
+	Param  string
+	Before string
+	After  string
+}
+
+// NewPoolParams builds the snapshot of the registered parameters of a pool.
+// The owners and the relays are sorted so that a reordering is not reported as a change.
+func NewPoolParams(poolInfo bfAPI.Pool, relays []bfAPI.PoolRelay, retiringEpoch int) PoolParams {
+	owners := slices.Clone(poolInfo.Owners)
+	slices.Sort(owners)
+
+	relayNames := make([]string, 0, len(relays))
+	for _, relay := range relays {
+		relayNames = append(relayNames, relayName(relay))
+	}
+	slices.Sort(relayNames)
+
+	return PoolParams{
+		Margin:         poolInfo.MarginCost,
+		FixedCost:      poolInfo.FixedCost,
+		DeclaredPledge: poolInfo.DeclaredPledge,
+		RewardAccount:  poolInfo.RewardAccount,
+		Owners:         owners,
+		Relays:         relayNames,
+		RetiringEpoch:  retiringEpoch,
+	}
+}
+
+// fields returns the parameters of the snapshot formatted as they are recorded in the change events.
+func (p PoolParams) fields() [][2]string {
+	return [][2]string{
+		{"margin", strconv.FormatFloat(p.Margin, 'f', -1, 64)},
+		{"fixed_cost", p.FixedCost},
+		{"declared_pledge", p.DeclaredPledge},
+		{"reward_account", p.RewardAccount},
+		{"owners", strings.Join(p.Owners, ",")},
+		{"relays", strings.Join(p.Relays, ",")},
+		{"retiring_epoch", strconv.Itoa(p.RetiringEpoch)},
+	}
+}
+
+// Diff returns the parameters that differ between the snapshot and the next one.
+func (p PoolParams) Diff(next PoolParams) []PoolParamChange {
+	changes := []PoolParamChange{}
+	after := next.fields()
+	for i, before := range p.fields() {
+		if before[1] != after[i][1] {
+			changes = append(changes, PoolParamChange{Param: before[0], Before: before[1], After: after[i][1]})
+		}
+	}
+	return changes
+}
+
+// relayName formats a registered relay, e.g. relay.example.com:3001
+func relayName(relay bfAPI.PoolRelay) string {
+	port := strconv.Itoa(relay.Port)
+
+	switch {
+	case relay.DNSSrv != nil:
+		return *relay.DNSSrv
+	case relay.DNS != nil:
+		return net.JoinHostPort(*relay.DNS, port)
+	}
+
+	addrs := []string{}
+	if relay.Ipv4 != nil {
+		addrs = append(addrs, net.JoinHostPort(*relay.Ipv4, port))
+	}
+	if relay.Ipv6 != nil {
+		addrs = append(addrs, net.JoinHostPort(*relay.Ipv6, port))
+	}
+	return strings.Join(addrs, "/")
+}
+
+// checkPoolParams compares the registered parameters of a pool with the snapshot
+// persisted at the previous refresh. Each change is recorded with its before and
+// after values, an unexpected re-registration of a pool is a security incident.
+func (w *PoolWatcher) checkPoolParams(ctx context.Context, pool pools.Pool, poolInfo bfAPI.Pool, relays []bfAPI.PoolRelay) error {
+	retiringPools, err := w.getRetiringPools(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := NewPoolParams(poolInfo, relays, retiringPools[pool.ID])
+	w.metrics.PoolRetiringEpoch.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(float64(params.RetiringEpoch))
+
+	previous, err := w.loadPoolParams(ctx, pool.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return w.savePoolParams(ctx, pool.ID, params, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	changes := previous.Diff(params)
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := w.savePoolParams(ctx, pool.ID, params, changes); err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		w.metrics.PoolParamChanges.WithLabelValues(pool.Name, pool.ID, pool.Instance, change.Param).Inc()
+		w.logger.ErrorContext(ctx,
+			fmt.Sprintf("🚨 parameter %s of pool %s changed", change.Param, pool.Name),
+			slog.String("pool_id", pool.ID),
+			slog.String("before", change.Before),
+			slog.String("after", change.After),
+		)
+	}
+	return nil
+}
+
+// getRetiringPools returns the epoch at which each retiring pool retires, keyed by pool ID.
+func (w *PoolWatcher) getRetiringPools(ctx context.Context) (map[string]int, error) {
+	retiringPools, ok := w.cache.Get("retiring_pools")
+	if !ok {
+		retiring, err := w.blockfrost.GetRetiringPools(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve retiring pools: %w", err)
+		}
+
+		epochs := make(map[string]int, len(retiring))
+		for _, pool := range retiring {
+			epochs[pool.PoolID] = pool.Epoch
+		}
+		retiringPools = epochs
+		w.cache.SetWithTTL("retiring_pools", retiringPools, 1, w.cacheTTL)
+		w.cache.Wait()
+	}
+
+	return retiringPools.(map[string]int), nil
+}
+
+// loadPoolParams returns the snapshot of a pool persisted at the previous refresh.
+func (w *PoolWatcher) loadPoolParams(ctx context.Context, poolID string) (PoolParams, error) {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var encoded string
+	query := "SELECT params FROM pool_params WHERE pool_id = ?"
+	if err := w.db.GetContext(cctx, &encoded, query, poolID); err != nil {
+		return PoolParams{}, fmt.Errorf("failed to execute SQL query while loading the parameters of pool %s: %w", poolID, err)
+	}
+
+	params := PoolParams{}
+	if err := json.Unmarshal([]byte(encoded), &params); err != nil {
+		return PoolParams{}, fmt.Errorf("failed to decode the parameters of pool %s: %w", poolID, err)
+	}
+	return params, nil
+}
+
+// savePoolParams records the changes and replaces the snapshot of a pool in a single transaction.
+func (w *PoolWatcher) savePoolParams(ctx context.Context, poolID string, params PoolParams, changes []PoolParamChange) error {
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode the parameters of pool %s: %w", poolID, err)
+	}
+
+	tx, err := w.db.BeginTxx(cctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction while saving the parameters of pool %s: %w", poolID, err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := time.Now()
+	for _, change := range changes {
+		query := "INSERT INTO pool_param_changes (pool_id, param, before, after, detected_at) VALUES (?, ?, ?, ?, ?)"
+		if _, err := tx.ExecContext(cctx, query, poolID, change.Param, change.Before, change.After, now); err != nil {
+			return fmt.Errorf("failed to execute SQL query while recording the change of %s for pool %s: %w", change.Param, poolID, err)
+		}
+	}
+
+	query := "INSERT OR REPLACE INTO pool_params (pool_id, params, updated_at) VALUES (?, ?, ?)"
+	if _, err := tx.ExecContext(cctx, query, poolID, string(encoded), now); err != nil {
+		return fmt.Errorf("failed to execute SQL query while saving the parameters of pool %s: %w", poolID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction while saving the parameters of pool %s: %w", poolID, err)
+	}
+	return nil
+}
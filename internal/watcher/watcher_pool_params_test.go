@@ -0,0 +1,221 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	bfAPI "github.com/blockfrost/blockfrost-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	loadPoolParamsQuery    = "SELECT params FROM pool_params WHERE pool_id = ?"
+	savePoolParamsQuery    = "INSERT OR REPLACE INTO pool_params (pool_id, params, updated_at) VALUES (?, ?, ?)"
+	recordParamChangeQuery = "INSERT INTO pool_param_changes (pool_id, param, before, after, detected_at) VALUES (?, ?, ?, ?, ?)"
+)
+
+// expectFirstPoolParamsSnapshot expects the snapshot of a pool without a previous one to be saved.
+func expectFirstPoolParamsSnapshot(client *dbMockClient, poolID string) {
+	client.mock.
+		ExpectQuery(loadPoolParamsQuery).
+		WithArgs(poolID).
+		WillReturnRows(sqlmock.NewRows([]string{"params"}))
+	client.mock.ExpectBegin()
+	client.mock.
+		ExpectExec(savePoolParamsQuery).
+		WithArgs(poolID, sqlmock.AnyArg(), AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	client.mock.ExpectCommit()
+}
+
+func encodePoolParams(t *testing.T, params PoolParams) string {
+	t.Helper()
+
+	encoded, err := json.Marshal(params)
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+func TestPoolWatcher_CheckPoolParams(t *testing.T) {
+	t.Parallel()
+	pools := setupPools(t)
+
+	relay := "relay.example.com"
+	poolInfo := bfAPI.Pool{
+		MarginCost:     0.01,
+		FixedCost:      "340000000",
+		DeclaredPledge: "1000000",
+		RewardAccount:  "stake1reward",
+		Owners:         []string{"stake-owner-1", "stake-owner-0"},
+	}
+	relays := []bfAPI.PoolRelay{{DNS: &relay, Port: 3001}}
+
+	t.Run("GoodPath_FirstSnapshotRecordsNoChange", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_pool_retiring_epoch Epoch at which the retirement of the pool is scheduled, 0 when no retirement is scheduled
+# TYPE cardano_validator_watcher_pool_retiring_epoch gauge
+cardano_validator_watcher_pool_retiring_epoch{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 0
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_pool_param_changes_total",
+			"cardano_validator_watcher_pool_retiring_epoch",
+		}
+
+		clients.bf.EXPECT().
+			GetRetiringPools(mock.Anything).
+			Return([]bfAPI.PoolRetiring{{PoolID: "pool-other", Epoch: 520}}, nil)
+		expectFirstPoolParamsSnapshot(mockDBClient, pools[0].ID)
+
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, mockDBClient.db, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+		require.NoError(t, err)
+		require.NoError(t, watcher.checkPoolParams(context.Background(), pools[0], poolInfo, relays))
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("GoodPath_UnchangedParamsNotSaved", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		clients.bf.EXPECT().
+			GetRetiringPools(mock.Anything).
+			Return([]bfAPI.PoolRetiring{}, nil)
+
+		// the owners are reordered by the new registration
+		previous := NewPoolParams(bfAPI.Pool{
+			MarginCost:     0.01,
+			FixedCost:      "340000000",
+			DeclaredPledge: "1000000",
+			RewardAccount:  "stake1reward",
+			Owners:         []string{"stake-owner-0", "stake-owner-1"},
+		}, relays, 0)
+		mockDBClient.mock.
+			ExpectQuery(loadPoolParamsQuery).
+			WithArgs(pools[0].ID).
+			WillReturnRows(sqlmock.NewRows([]string{"params"}).AddRow(encodePoolParams(t, previous)))
+
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, mockDBClient.db, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+		require.NoError(t, err)
+		require.NoError(t, watcher.checkPoolParams(context.Background(), pools[0], poolInfo, relays))
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+		require.Equal(t, 0, testutil.CollectAndCount(registry.metrics.PoolParamChanges))
+	})
+
+	t.Run("GoodPath_ChangesRecorded", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_pool_param_changes_total Number of changes detected in the registered parameters of the pool
+# TYPE cardano_validator_watcher_pool_param_changes_total counter
+cardano_validator_watcher_pool_param_changes_total{param="margin",pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1
+cardano_validator_watcher_pool_param_changes_total{param="reward_account",pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1
+cardano_validator_watcher_pool_param_changes_total{param="retiring_epoch",pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1
+# HELP cardano_validator_watcher_pool_retiring_epoch Epoch at which the retirement of the pool is scheduled, 0 when no retirement is scheduled
+# TYPE cardano_validator_watcher_pool_retiring_epoch gauge
+cardano_validator_watcher_pool_retiring_epoch{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 520
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_pool_param_changes_total",
+			"cardano_validator_watcher_pool_retiring_epoch",
+		}
+
+		clients.bf.EXPECT().
+			GetRetiringPools(mock.Anything).
+			Return([]bfAPI.PoolRetiring{{PoolID: pools[0].ID, Epoch: 520}}, nil)
+
+		previous := NewPoolParams(bfAPI.Pool{
+			MarginCost:     0.005,
+			FixedCost:      "340000000",
+			DeclaredPledge: "1000000",
+			RewardAccount:  "stake1previous",
+			Owners:         []string{"stake-owner-0", "stake-owner-1"},
+		}, relays, 0)
+		mockDBClient.mock.
+			ExpectQuery(loadPoolParamsQuery).
+			WithArgs(pools[0].ID).
+			WillReturnRows(sqlmock.NewRows([]string{"params"}).AddRow(encodePoolParams(t, previous)))
+		mockDBClient.mock.ExpectBegin()
+		mockDBClient.mock.
+			ExpectExec(recordParamChangeQuery).
+			WithArgs(pools[0].ID, "margin", "0.005", "0.01", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mockDBClient.mock.
+			ExpectExec(recordParamChangeQuery).
+			WithArgs(pools[0].ID, "reward_account", "stake1previous", "stake1reward", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mockDBClient.mock.
+			ExpectExec(recordParamChangeQuery).
+			WithArgs(pools[0].ID, "retiring_epoch", "0", "520", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mockDBClient.mock.
+			ExpectExec(savePoolParamsQuery).
+			WithArgs(pools[0].ID, encodePoolParams(t, NewPoolParams(poolInfo, relays, 520)), AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mockDBClient.mock.ExpectCommit()
+
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, mockDBClient.db, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+		require.NoError(t, err)
+		require.NoError(t, watcher.checkPoolParams(context.Background(), pools[0], poolInfo, relays))
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("SadPath_UnableToRecordChange", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		clients.bf.EXPECT().
+			GetRetiringPools(mock.Anything).
+			Return([]bfAPI.PoolRetiring{}, nil)
+
+		previous := NewPoolParams(poolInfo, nil, 0)
+		mockDBClient.mock.
+			ExpectQuery(loadPoolParamsQuery).
+			WithArgs(pools[0].ID).
+			WillReturnRows(sqlmock.NewRows([]string{"params"}).AddRow(encodePoolParams(t, previous)))
+		mockDBClient.mock.ExpectBegin()
+		mockDBClient.mock.
+			ExpectExec(recordParamChangeQuery).
+			WithArgs(pools[0].ID, "relays", "", "relay.example.com:3001", AnyTime{}).
+			WillReturnError(errors.New("database is locked"))
+		mockDBClient.mock.ExpectRollback()
+
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, mockDBClient.db, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+		require.NoError(t, err)
+		err = watcher.checkPoolParams(context.Background(), pools[0], poolInfo, relays)
+		require.ErrorContains(t, err, "failed to execute SQL query while recording the change of relays for pool pool-0")
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+
+		// the change is reported again at the next refresh since the snapshot was not replaced
+		require.Equal(t, 0, testutil.CollectAndCount(registry.metrics.PoolParamChanges))
+	})
+}
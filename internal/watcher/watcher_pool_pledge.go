@@ -0,0 +1,80 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	bfAPI "github.com/blockfrost/blockfrost-go"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+)
+
+// collectPledge exposes the live and declared pledge of a pool, the margin between
+// them and the balance of each owner. A margin under the warning threshold is
+// reported before the pledge is actually missed and the rewards of the epoch lost.
+func (w *PoolWatcher) collectPledge(ctx context.Context, pool pools.Pool, poolInfo bfAPI.Pool) error {
+	livePledge, err := strconv.Atoi(poolInfo.LivePledge)
+	if err != nil {
+		return fmt.Errorf("unable to convert live pledge to integer: %w", err)
+	}
+
+	declaredPledge, err := strconv.Atoi(poolInfo.DeclaredPledge)
+	if err != nil {
+		return fmt.Errorf("unable to convert declared pledge to integer: %w", err)
+	}
+	if livePledge >= declaredPledge {
+		w.metrics.PoolsPledgeMet.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(1)
+	} else {
+		w.metrics.PoolsPledgeMet.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(0)
+	}
+
+	margin := livePledge - declaredPledge
+	w.metrics.PoolLivePledge.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(float64(livePledge))
+	w.metrics.PoolDeclaredPledge.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(float64(declaredPledge))
+	w.metrics.PoolPledgeMargin.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(float64(margin))
+
+	threshold := float64(declaredPledge) * w.opts.PledgeWarningThreshold / 100
+	if float64(margin) < threshold {
+		w.metrics.PoolPledgeMarginWarning.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(1)
+		w.logger.WarnContext(ctx,
+			fmt.Sprintf("the live pledge of pool %s is close to its declared pledge", pool.Name),
+			slog.String("pool_id", pool.ID),
+			slog.Int("live_pledge", livePledge),
+			slog.Int("declared_pledge", declaredPledge),
+			slog.Int("margin", margin),
+			slog.Float64("threshold_percent", w.opts.PledgeWarningThreshold),
+		)
+	} else {
+		w.metrics.PoolPledgeMarginWarning.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(0)
+	}
+
+	return w.collectOwnerBalances(ctx, pool, poolInfo.Owners)
+}
+
+// collectOwnerBalances exposes the balance of each owner of the pool, the owners
+// no longer registered by the pool are removed from the metrics.
+func (w *PoolWatcher) collectOwnerBalances(ctx context.Context, pool pools.Pool, owners []string) error {
+	for _, owner := range owners {
+		account, err := w.getAccountInfo(ctx, owner)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve account info for owner '%s': %w", owner, err)
+		}
+
+		balance, err := strconv.Atoi(account.ControlledAmount)
+		if err != nil {
+			return fmt.Errorf("unable to convert balance of owner '%s' to integer: %w", owner, err)
+		}
+		w.metrics.PoolOwnerBalance.WithLabelValues(pool.Name, pool.ID, pool.Instance, owner).Set(float64(balance))
+	}
+
+	for _, owner := range w.owners[pool.ID] {
+		if !slices.Contains(owners, owner) {
+			w.metrics.PoolOwnerBalance.DeleteLabelValues(pool.Name, pool.ID, pool.Instance, owner)
+		}
+	}
+	w.owners[pool.ID] = owners
+
+	return nil
+}
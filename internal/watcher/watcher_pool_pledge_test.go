@@ -0,0 +1,173 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	bfAPI "github.com/blockfrost/blockfrost-go"
+	"github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolWatcher_CollectPledge(t *testing.T) {
+	t.Parallel()
+	pools := setupPools(t)
+
+	t.Run("GoodPath_PledgeMarginUnderThreshold", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		mockDBClient := setupDB(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_pool_declared_pledge Pledge declared in the registration of the pool in lovelace
+# TYPE cardano_validator_watcher_pool_declared_pledge gauge
+cardano_validator_watcher_pool_declared_pledge{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1e+06
+# HELP cardano_validator_watcher_pool_live_pledge Live pledge of the pool in lovelace
+# TYPE cardano_validator_watcher_pool_live_pledge gauge
+cardano_validator_watcher_pool_live_pledge{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1.02e+06
+# HELP cardano_validator_watcher_pool_owner_balance Balance controlled by each owner stake address of the pool in lovelace
+# TYPE cardano_validator_watcher_pool_owner_balance gauge
+cardano_validator_watcher_pool_owner_balance{owner="stake-owner-0",pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 700000
+cardano_validator_watcher_pool_owner_balance{owner="stake-owner-1",pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 320000
+# HELP cardano_validator_watcher_pool_pledge_margin Live pledge minus declared pledge of the pool in lovelace
+# TYPE cardano_validator_watcher_pool_pledge_margin gauge
+cardano_validator_watcher_pool_pledge_margin{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 20000
+# HELP cardano_validator_watcher_pool_pledge_margin_warning Whether the pledge margin of the pool is under the warning threshold (0 or 1)
+# TYPE cardano_validator_watcher_pool_pledge_margin_warning gauge
+cardano_validator_watcher_pool_pledge_margin_warning{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1
+# HELP cardano_validator_watcher_pool_pledge_met Whether the pool has met its pledge requirements or not (0 or 1)
+# TYPE cardano_validator_watcher_pool_pledge_met gauge
+cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_pool_declared_pledge",
+			"cardano_validator_watcher_pool_live_pledge",
+			"cardano_validator_watcher_pool_owner_balance",
+			"cardano_validator_watcher_pool_pledge_margin",
+			"cardano_validator_watcher_pool_pledge_margin_warning",
+			"cardano_validator_watcher_pool_pledge_met",
+		}
+
+		ticker := testTicker
+		clients.bf.EXPECT().
+			GetPoolMetadata(mock.Anything, pools[0].ID).
+			Return(bfAPI.PoolMetadata{Ticker: &ticker}, nil)
+		clients.bf.EXPECT().
+			GetPoolInfo(mock.Anything, pools[0].ID).
+			Return(bfAPI.Pool{
+				LivePledge:     "1020000",
+				DeclaredPledge: "1000000",
+				RewardAccount:  "stake1test",
+				Owners:         []string{"stake-owner-0", "stake-owner-1"},
+			}, nil)
+		clients.bf.EXPECT().
+			GetPoolRelays(mock.Anything, pools[0].ID).
+			Return([]bfAPI.PoolRelay{}, nil)
+		clients.bf.EXPECT().
+			GetAccountInfo(mock.Anything, "stake-owner-0").
+			Return(blockfrost.Account{ControlledAmount: "700000"}, nil)
+		clients.bf.EXPECT().
+			GetAccountInfo(mock.Anything, "stake-owner-1").
+			Return(blockfrost.Account{ControlledAmount: "320000"}, nil)
+		clients.bf.EXPECT().
+			GetAccountInfo(mock.Anything, "stake1test").
+			Return(blockfrost.Account{}, nil)
+		clients.bf.EXPECT().
+			GetRetiringPools(mock.Anything).
+			Return([]bfAPI.PoolRetiring{}, nil)
+		expectFirstPoolParamsSnapshot(mockDBClient, pools[0].ID)
+
+		// the margin of 2% is under the threshold of 5%
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, mockDBClient.db, NewHealthStore(), PoolWatcherOptions{
+			RefreshInterval:        time.Minute,
+			PledgeWarningThreshold: 5,
+		})
+		require.NoError(t, err)
+		require.NoError(t, watcher.fetch(context.Background()))
+		require.NoError(t, mockDBClient.mock.ExpectationsWereMet())
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("GoodPath_PledgeMarginOverThreshold", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_pool_pledge_margin_warning Whether the pledge margin of the pool is under the warning threshold (0 or 1)
+# TYPE cardano_validator_watcher_pool_pledge_margin_warning gauge
+cardano_validator_watcher_pool_pledge_margin_warning{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 0
+`
+		registry.metricsUnderTest = []string{"cardano_validator_watcher_pool_pledge_margin_warning"}
+
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, nil, NewHealthStore(), PoolWatcherOptions{
+			RefreshInterval:        time.Minute,
+			PledgeWarningThreshold: 5,
+		})
+		require.NoError(t, err)
+		require.NoError(t, watcher.collectPledge(context.Background(), pools[0], bfAPI.Pool{
+			LivePledge:     "1100000",
+			DeclaredPledge: "1000000",
+		}))
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("GoodPath_RemovedOwnerDeleted", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_pool_owner_balance Balance controlled by each owner stake address of the pool in lovelace
+# TYPE cardano_validator_watcher_pool_owner_balance gauge
+cardano_validator_watcher_pool_owner_balance{owner="stake-owner-0",pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 700000
+`
+		registry.metricsUnderTest = []string{"cardano_validator_watcher_pool_owner_balance"}
+
+		clients.bf.EXPECT().
+			GetAccountInfo(mock.Anything, "stake-owner-0").
+			Return(blockfrost.Account{ControlledAmount: "700000"}, nil)
+		clients.bf.EXPECT().
+			GetAccountInfo(mock.Anything, "stake-owner-1").
+			Return(blockfrost.Account{ControlledAmount: "320000"}, nil)
+
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, nil, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+		require.NoError(t, err)
+		require.NoError(t, watcher.collectOwnerBalances(context.Background(), pools[0], []string{"stake-owner-0", "stake-owner-1"}))
+		require.NoError(t, watcher.collectOwnerBalances(context.Background(), pools[0], []string{"stake-owner-0"}))
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("SadPath_InvalidOwnerBalance", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+
+		clients.bf.EXPECT().
+			GetAccountInfo(mock.Anything, "stake-owner-0").
+			Return(blockfrost.Account{ControlledAmount: "not a number"}, nil)
+
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, nil, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+		require.NoError(t, err)
+		err = watcher.collectOwnerBalances(context.Background(), pools[0], []string{"stake-owner-0"})
+		require.ErrorContains(t, err, "unable to convert balance of owner 'stake-owner-0' to integer")
+	})
+}
@@ -0,0 +1,274 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	bfAPI "github.com/blockfrost/blockfrost-go"
+	"github.com/kilnfi/cardano-validator-watcher/internal/pools"
+)
+
+const (
+	// relayDialTimeout bounds a single dial of a relay address.
+	relayDialTimeout = 5 * time.Second
+	// relayFailureCooldown is how long an address that failed to answer is
+	// reported down without being dialed again, so that probing a dead relay
+	// does not pay the dial timeout at every probe.
+	relayFailureCooldown = 5 * time.Minute
+)
+
+// relayResolver resolves the addresses of the relays, it is implemented by net.Resolver.
+type relayResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// relayDialFunc opens a connection to a relay address.
+type relayDialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// relayStatus is the outcome of the probe of a registered relay.
+type relayStatus struct {
+	// name identifies the relay as registered on-chain, e.g. relay.example.com:3001
+	name string
+	// dns is set when the relay is registered with a DNS name
+	dns      bool
+	resolved bool
+	up       bool
+	latency  time.Duration
+}
+
+// relayProber dials the relays registered by the pools. An address that failed
+// to answer is skipped for a cooldown window, like the nodes of the socket proxy.
+type relayProber struct {
+	resolver relayResolver
+	dial     relayDialFunc
+
+	mu        sync.Mutex
+	downUntil map[string]time.Time
+	// probed holds the relays of each pool exposed in the metrics, keyed by pool ID
+	probed map[string][]string
+}
+
+func newRelayProber(resolver relayResolver, dial relayDialFunc) *relayProber {
+	return &relayProber{
+		resolver:  resolver,
+		dial:      dial,
+		downUntil: make(map[string]time.Time),
+		probed:    make(map[string][]string),
+	}
+}
+
+func (p *relayProber) isDown(addr string, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return now.Before(p.downUntil[addr])
+}
+
+func (p *relayProber) markDown(addr string, until time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downUntil[addr] = until
+}
+
+func (p *relayProber) clearDown(addr string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.downUntil, addr)
+}
+
+// probe resolves the addresses of a relay and dials them until one answers.
+func (p *relayProber) probe(ctx context.Context, relay bfAPI.PoolRelay) relayStatus {
+	status, addrs := p.resolve(ctx, relay)
+
+	for _, addr := range addrs {
+		if p.isDown(addr, time.Now()) {
+			continue
+		}
+
+		dialCtx, cancel := context.WithTimeout(ctx, relayDialTimeout)
+		start := time.Now()
+		conn, err := p.dial(dialCtx, "tcp", addr)
+		cancel()
+		if err != nil {
+			p.markDown(addr, time.Now().Add(relayFailureCooldown))
+			continue
+		}
+		status.latency = time.Since(start)
+		_ = conn.Close()
+		p.clearDown(addr)
+		status.up = true
+		break
+	}
+
+	return status
+}
+
+// resolve returns the addresses to dial for a relay. A relay registered with a DNS
+// name is resolved first so that a name that no longer resolves is told apart
+// from a relay that does not answer.
+func (p *relayProber) resolve(ctx context.Context, relay bfAPI.PoolRelay) (relayStatus, []string) {
+	port := strconv.Itoa(relay.Port)
+
+	switch {
+	case relay.DNSSrv != nil:
+		status := relayStatus{name: *relay.DNSSrv, dns: true}
+		_, records, err := p.resolver.LookupSRV(ctx, "", "", *relay.DNSSrv)
+		if err != nil || len(records) == 0 {
+			return status, nil
+		}
+		status.resolved = true
+
+		addrs := make([]string, 0, len(records))
+		for _, record := range records {
+			target := strings.TrimSuffix(record.Target, ".")
+			addrs = append(addrs, net.JoinHostPort(target, strconv.Itoa(int(record.Port))))
+		}
+		return status, addrs
+
+	case relay.DNS != nil:
+		status := relayStatus{name: net.JoinHostPort(*relay.DNS, port), dns: true}
+		ips, err := p.resolver.LookupIPAddr(ctx, *relay.DNS)
+		if err != nil || len(ips) == 0 {
+			return status, nil
+		}
+		status.resolved = true
+
+		addrs := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			addrs = append(addrs, net.JoinHostPort(ip.String(), port))
+		}
+		return status, addrs
+
+	default:
+		addrs := []string{}
+		if relay.Ipv4 != nil {
+			addrs = append(addrs, net.JoinHostPort(*relay.Ipv4, port))
+		}
+		if relay.Ipv6 != nil {
+			addrs = append(addrs, net.JoinHostPort(*relay.Ipv6, port))
+		}
+		if len(addrs) == 0 {
+			return relayStatus{}, nil
+		}
+		return relayStatus{name: addrs[0], resolved: true}, addrs
+	}
+}
+
+// runRelayProbe periodically probes the relays registered by the monitored pools.
+func (w *PoolWatcher) runRelayProbe(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.RelayProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		if w.healthStore.GetHealth() {
+			if err := w.probeRelays(ctx); err != nil {
+				w.logger.ErrorContext(ctx, "unable to probe pool relays", slog.String("error", err.Error()))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeRelays probes the relays of each active pool and updates the relay metrics.
+// A pool whose relays cannot be retrieved does not prevent the others from being probed.
+func (w *PoolWatcher) probeRelays(ctx context.Context) error {
+	var errs []error
+	for _, pool := range w.pools.GetActivePools() {
+		relays, err := w.getPoolRelays(ctx, pool.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to retrieve relays for pool '%s': %w", pool.ID, err))
+			continue
+		}
+		w.probePoolRelays(ctx, pool, relays)
+	}
+
+	return errors.Join(errs...)
+}
+
+// probePoolRelays probes the relays of a pool concurrently. A pool is flagged when
+// none of its registered relays answers.
+func (w *PoolWatcher) probePoolRelays(ctx context.Context, pool pools.Pool, relays []bfAPI.PoolRelay) {
+	statuses := make([]relayStatus, len(relays))
+	var wg sync.WaitGroup
+	for i, relay := range relays {
+		wg.Go(func() {
+			statuses[i] = w.relayProber.probe(ctx, relay)
+		})
+	}
+	wg.Wait()
+
+	names := []string{}
+	up := 0
+	for _, status := range statuses {
+		if status.name == "" {
+			continue
+		}
+		names = append(names, status.name)
+		labels := []string{pool.Name, pool.ID, pool.Instance, status.name}
+
+		if status.dns {
+			if status.resolved {
+				w.metrics.PoolRelayDNSResolved.WithLabelValues(labels...).Set(1)
+			} else {
+				w.metrics.PoolRelayDNSResolved.WithLabelValues(labels...).Set(0)
+				w.logger.WarnContext(ctx,
+					fmt.Sprintf("relay %s of pool %s does not resolve", status.name, pool.Name),
+					slog.String("pool_id", pool.ID),
+				)
+			}
+		}
+
+		if status.up {
+			up++
+			w.metrics.PoolRelayUp.WithLabelValues(labels...).Set(1)
+			w.metrics.PoolRelayLatency.WithLabelValues(labels...).Set(status.latency.Seconds())
+		} else {
+			w.metrics.PoolRelayUp.WithLabelValues(labels...).Set(0)
+		}
+	}
+	w.deleteUnregisteredRelays(pool, names)
+
+	if len(names) > 0 && up == 0 {
+		w.metrics.PoolRelaysUnreachable.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(1)
+		w.logger.ErrorContext(ctx,
+			fmt.Sprintf("🚨 none of the relays of pool %s is reachable", pool.Name),
+			slog.String("pool_id", pool.ID),
+			slog.Any("relays", names),
+		)
+	} else {
+		w.metrics.PoolRelaysUnreachable.WithLabelValues(pool.Name, pool.ID, pool.Instance).Set(0)
+	}
+}
+
+// deleteUnregisteredRelays removes the metrics of the relays no longer registered by the pool.
+func (w *PoolWatcher) deleteUnregisteredRelays(pool pools.Pool, names []string) {
+	w.relayProber.mu.Lock()
+	defer w.relayProber.mu.Unlock()
+
+	registered := make(map[string]bool, len(names))
+	for _, name := range names {
+		registered[name] = true
+	}
+	for _, name := range w.relayProber.probed[pool.ID] {
+		if registered[name] {
+			continue
+		}
+		labels := []string{pool.Name, pool.ID, pool.Instance, name}
+		w.metrics.PoolRelayUp.DeleteLabelValues(labels...)
+		w.metrics.PoolRelayLatency.DeleteLabelValues(labels...)
+		w.metrics.PoolRelayDNSResolved.DeleteLabelValues(labels...)
+	}
+	w.relayProber.probed[pool.ID] = names
+}
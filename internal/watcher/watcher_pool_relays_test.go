@@ -0,0 +1,266 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	bfAPI "github.com/blockfrost/blockfrost-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResolver resolves the names of its maps, the other names do not resolve.
+type fakeResolver struct {
+	hosts map[string][]net.IPAddr
+	srv   map[string][]*net.SRV
+}
+
+func (r fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r.hosts[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return ips, nil
+}
+
+func (r fakeResolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	records, ok := r.srv[name]
+	if !ok {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return name, records, nil
+}
+
+// setupRelay returns the port of a relay listening on the loopback.
+func setupRelay(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// setupClosedPort returns a port of the loopback on which nothing listens.
+func setupClosedPort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+	return port
+}
+
+func TestPoolWatcher_ProbeRelays(t *testing.T) {
+	t.Parallel()
+	pools := setupPools(t)
+	loopback := []net.IPAddr{{IP: net.IPv4(127, 0, 0, 1)}}
+
+	t.Run("GoodPath_RelaysReachable", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+		port := setupRelay(t)
+		ipv4 := "127.0.0.1"
+		dns := "relay.example.com"
+		srv := "_cardano._tcp.example.com"
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_pool_relay_dns_resolved Whether the DNS name of the relay resolves: 1 = resolved, 0 = unresolved
+# TYPE cardano_validator_watcher_pool_relay_dns_resolved gauge
+cardano_validator_watcher_pool_relay_dns_resolved{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0",relay="_cardano._tcp.example.com"} 1
+cardano_validator_watcher_pool_relay_dns_resolved{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0",relay="relay.example.com:` + strconv.Itoa(port) + `"} 1
+# HELP cardano_validator_watcher_pool_relay_up Reachability of each relay registered by the pool: 1 = reachable, 0 = down
+# TYPE cardano_validator_watcher_pool_relay_up gauge
+cardano_validator_watcher_pool_relay_up{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0",relay="127.0.0.1:` + strconv.Itoa(port) + `"} 1
+cardano_validator_watcher_pool_relay_up{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0",relay="_cardano._tcp.example.com"} 1
+cardano_validator_watcher_pool_relay_up{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0",relay="relay.example.com:` + strconv.Itoa(port) + `"} 1
+# HELP cardano_validator_watcher_pool_relays_unreachable Whether none of the relays registered by the pool is reachable (0 or 1)
+# TYPE cardano_validator_watcher_pool_relays_unreachable gauge
+cardano_validator_watcher_pool_relays_unreachable{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 0
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_pool_relay_dns_resolved",
+			"cardano_validator_watcher_pool_relay_up",
+			"cardano_validator_watcher_pool_relays_unreachable",
+		}
+
+		clients.bf.EXPECT().
+			GetPoolRelays(mock.Anything, pools[0].ID).
+			Return([]bfAPI.PoolRelay{
+				{Ipv4: &ipv4, Port: port},
+				{DNS: &dns, Port: port},
+				{DNSSrv: &srv},
+			}, nil)
+
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, nil, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+		require.NoError(t, err)
+		watcher.relayProber = newRelayProber(fakeResolver{
+			hosts: map[string][]net.IPAddr{dns: loopback},
+			srv:   map[string][]*net.SRV{srv: {{Target: "127.0.0.1.", Port: uint16(port)}}},
+		}, (&net.Dialer{}).DialContext)
+
+		require.NoError(t, watcher.probeRelays(context.Background()))
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+		require.Equal(t, 3, testutil.CollectAndCount(registry.metrics.PoolRelayLatency))
+	})
+
+	t.Run("SadPath_AllRelaysUnreachable", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+		port := setupClosedPort(t)
+		ipv4 := "127.0.0.1"
+		dns := "relay.example.com"
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_pool_relay_dns_resolved Whether the DNS name of the relay resolves: 1 = resolved, 0 = unresolved
+# TYPE cardano_validator_watcher_pool_relay_dns_resolved gauge
+cardano_validator_watcher_pool_relay_dns_resolved{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0",relay="relay.example.com:` + strconv.Itoa(port) + `"} 0
+# HELP cardano_validator_watcher_pool_relay_up Reachability of each relay registered by the pool: 1 = reachable, 0 = down
+# TYPE cardano_validator_watcher_pool_relay_up gauge
+cardano_validator_watcher_pool_relay_up{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0",relay="127.0.0.1:` + strconv.Itoa(port) + `"} 0
+cardano_validator_watcher_pool_relay_up{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0",relay="relay.example.com:` + strconv.Itoa(port) + `"} 0
+# HELP cardano_validator_watcher_pool_relays_unreachable Whether none of the relays registered by the pool is reachable (0 or 1)
+# TYPE cardano_validator_watcher_pool_relays_unreachable gauge
+cardano_validator_watcher_pool_relays_unreachable{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0"} 1
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_pool_relay_dns_resolved",
+			"cardano_validator_watcher_pool_relay_up",
+			"cardano_validator_watcher_pool_relays_unreachable",
+		}
+
+		clients.bf.EXPECT().
+			GetPoolRelays(mock.Anything, pools[0].ID).
+			Return([]bfAPI.PoolRelay{
+				{Ipv4: &ipv4, Port: port},
+				{DNS: &dns, Port: port},
+			}, nil)
+
+		var dials atomic.Int32
+		dialer := &net.Dialer{}
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, nil, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+		require.NoError(t, err)
+		watcher.relayProber = newRelayProber(fakeResolver{}, func(ctx context.Context, network, address string) (net.Conn, error) {
+			dials.Add(1)
+			return dialer.DialContext(ctx, network, address)
+		})
+
+		// the relay that failed is not dialed again during the cooldown
+		require.NoError(t, watcher.probeRelays(context.Background()))
+		require.NoError(t, watcher.probeRelays(context.Background()))
+		require.Equal(t, int32(1), dials.Load())
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+		require.Equal(t, 0, testutil.CollectAndCount(registry.metrics.PoolRelayLatency))
+	})
+
+	t.Run("SadPath_PoolRelaysUnavailable", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+		port := setupRelay(t)
+		ipv4 := "127.0.0.1"
+
+		monitored := setupPools(t)
+		monitored[1].Exclude = false
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_pool_relay_up Reachability of each relay registered by the pool: 1 = reachable, 0 = down
+# TYPE cardano_validator_watcher_pool_relay_up gauge
+cardano_validator_watcher_pool_relay_up{pool_id="pool-1",pool_instance="pool-1",pool_name="pool-1",relay="127.0.0.1:` + strconv.Itoa(port) + `"} 1
+# HELP cardano_validator_watcher_pool_relays_unreachable Whether none of the relays registered by the pool is reachable (0 or 1)
+# TYPE cardano_validator_watcher_pool_relays_unreachable gauge
+cardano_validator_watcher_pool_relays_unreachable{pool_id="pool-1",pool_instance="pool-1",pool_name="pool-1"} 0
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_pool_relay_up",
+			"cardano_validator_watcher_pool_relays_unreachable",
+		}
+
+		clients.bf.EXPECT().
+			GetPoolRelays(mock.Anything, monitored[0].ID).
+			Return(nil, errors.New("upstream unavailable"))
+		clients.bf.EXPECT().
+			GetPoolRelays(mock.Anything, monitored[1].ID).
+			Return([]bfAPI.PoolRelay{{Ipv4: &ipv4, Port: port}}, nil)
+
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, monitored, nil, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+		require.NoError(t, err)
+		watcher.relayProber = newRelayProber(fakeResolver{}, (&net.Dialer{}).DialContext)
+
+		// the relays of pool-1 are probed even though those of pool-0 cannot be retrieved
+		err = watcher.probeRelays(context.Background())
+		require.ErrorContains(t, err, "unable to retrieve relays for pool 'pool-0'")
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+
+	t.Run("GoodPath_DeleteUnregisteredRelays", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+		registry := setupRegistry(t)
+		port := setupRelay(t)
+		ipv4 := "127.0.0.1"
+		dns := "relay.example.com"
+
+		registry.metricsExpectedOutput = `
+# HELP cardano_validator_watcher_pool_relay_up Reachability of each relay registered by the pool: 1 = reachable, 0 = down
+# TYPE cardano_validator_watcher_pool_relay_up gauge
+cardano_validator_watcher_pool_relay_up{pool_id="pool-0",pool_instance="pool-0",pool_name="pool-0",relay="127.0.0.1:` + strconv.Itoa(port) + `"} 1
+`
+		registry.metricsUnderTest = []string{
+			"cardano_validator_watcher_pool_relay_dns_resolved",
+			"cardano_validator_watcher_pool_relay_up",
+		}
+
+		watcher, err := NewPoolWatcher(clients.bf, registry.metrics, pools, nil, NewHealthStore(), PoolWatcherOptions{RefreshInterval: time.Minute})
+		require.NoError(t, err)
+		watcher.relayProber = newRelayProber(fakeResolver{
+			hosts: map[string][]net.IPAddr{dns: loopback},
+		}, (&net.Dialer{}).DialContext)
+
+		// the DNS relay is removed from the registration of the pool
+		watcher.probePoolRelays(context.Background(), pools[0], []bfAPI.PoolRelay{
+			{Ipv4: &ipv4, Port: port},
+			{DNS: &dns, Port: port},
+		})
+		watcher.probePoolRelays(context.Background(), pools[0], []bfAPI.PoolRelay{
+			{Ipv4: &ipv4, Port: port},
+		})
+
+		b := bytes.NewBufferString(registry.metricsExpectedOutput)
+		err = testutil.CollectAndCompare(registry.registry, b, registry.metricsUnderTest...)
+		require.NoError(t, err)
+	})
+}
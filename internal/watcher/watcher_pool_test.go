@@ -24,6 +24,7 @@ func TestPoolWatcher_Start(t *testing.T) {
 		t.Parallel()
 
 		clients := setupClients(t)
+		mockDBClient := setupDB(t)
 		registry := setupRegistry(t)
 
 		registry.metricsExpectedOutput = `
@@ -73,6 +74,11 @@ cardano_validator_watcher_pool_saturation_level{pool_id="pool-0",pool_instance="
 			GetAccountInfo(mock.Anything, "stake1test").
 			Return(blockfrost.Account{DrepID: &drepID}, nil)
 
+		clients.bf.EXPECT().
+			GetRetiringPools(mock.Anything).
+			Return([]bfAPI.PoolRetiring{}, nil)
+		expectFirstPoolParamsSnapshot(mockDBClient, pool[0].ID)
+
 		options := PoolWatcherOptions{
 			RefreshInterval: time.Minute * 1,
 			Network:         "testnet",
@@ -83,6 +89,7 @@ cardano_validator_watcher_pool_saturation_level{pool_id="pool-0",pool_instance="
 			clients.bf,
 			registry.metrics,
 			pool,
+			mockDBClient.db,
 			healthStore,
 			options,
 		)
@@ -100,6 +107,7 @@ cardano_validator_watcher_pool_saturation_level{pool_id="pool-0",pool_instance="
 		t.Parallel()
 
 		clients := setupClients(t)
+		mockDBClient := setupDB(t)
 		registry := setupRegistry(t)
 
 		registry.metricsExpectedOutput = `
@@ -140,6 +148,11 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 			GetAccountInfo(mock.Anything, "stake1test").
 			Return(blockfrost.Account{DrepID: nil}, nil)
 
+		clients.bf.EXPECT().
+			GetRetiringPools(mock.Anything).
+			Return([]bfAPI.PoolRetiring{}, nil)
+		expectFirstPoolParamsSnapshot(mockDBClient, pool[0].ID)
+
 		options := PoolWatcherOptions{
 			RefreshInterval: time.Minute * 1,
 			Network:         "testnet",
@@ -150,6 +163,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 			clients.bf,
 			registry.metrics,
 			pool,
+			mockDBClient.db,
 			healthStore,
 			options,
 		)
@@ -167,6 +181,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 		t.Parallel()
 
 		clients := setupClients(t)
+		mockDBClient := setupDB(t)
 		registry := setupRegistry(t)
 
 		ctx := setupContextWithTimeout(t, time.Second*10)
@@ -186,6 +201,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 			clients.bf,
 			registry.metrics,
 			pool,
+			mockDBClient.db,
 			healthStore,
 			options,
 		)
@@ -199,6 +215,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 		t.Parallel()
 
 		clients := setupClients(t)
+		mockDBClient := setupDB(t)
 		registry := setupRegistry(t)
 
 		ctx := setupContextWithTimeout(t, time.Second*10)
@@ -223,6 +240,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 			clients.bf,
 			registry.metrics,
 			pool,
+			mockDBClient.db,
 			healthStore,
 			options,
 		)
@@ -236,6 +254,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 		t.Parallel()
 
 		clients := setupClients(t)
+		mockDBClient := setupDB(t)
 		registry := setupRegistry(t)
 
 		ctx := setupContextWithTimeout(t, time.Second*10)
@@ -265,6 +284,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 			clients.bf,
 			registry.metrics,
 			pool,
+			mockDBClient.db,
 			healthStore,
 			options,
 		)
@@ -278,6 +298,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 		t.Parallel()
 
 		clients := setupClients(t)
+		mockDBClient := setupDB(t)
 		registry := setupRegistry(t)
 
 		ctx := setupContextWithTimeout(t, time.Second*10)
@@ -307,6 +328,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 			clients.bf,
 			registry.metrics,
 			pool,
+			mockDBClient.db,
 			healthStore,
 			options,
 		)
@@ -320,6 +342,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 		t.Parallel()
 
 		clients := setupClients(t)
+		mockDBClient := setupDB(t)
 		registry := setupRegistry(t)
 
 		ctx := setupContextWithTimeout(t, time.Second*10)
@@ -353,6 +376,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 			clients.bf,
 			registry.metrics,
 			pool,
+			mockDBClient.db,
 			healthStore,
 			options,
 		)
@@ -366,6 +390,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 		t.Parallel()
 
 		clients := setupClients(t)
+		mockDBClient := setupDB(t)
 		registry := setupRegistry(t)
 
 		ctx := setupContextWithTimeout(t, time.Second*10)
@@ -403,6 +428,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 			clients.bf,
 			registry.metrics,
 			pool,
+			mockDBClient.db,
 			healthStore,
 			options,
 		)
@@ -416,6 +442,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 		t.Parallel()
 
 		clients := setupClients(t)
+		mockDBClient := setupDB(t)
 		registry := setupRegistry(t)
 
 		ctx := setupContextWithTimeout(t, time.Second*2)
@@ -432,6 +459,7 @@ cardano_validator_watcher_pool_pledge_met{pool_id="pool-0",pool_instance="pool-0
 			clients.bf,
 			registry.metrics,
 			pool,
+			mockDBClient.db,
 			healthStore,
 			options,
 		)
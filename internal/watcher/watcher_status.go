@@ -2,6 +2,7 @@ package watcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -17,10 +18,24 @@ const (
 	DefaultRefreshInterval = 15 * time.Second
 )
 
+// HealthState describes why the watcher is healthy or not.
+type HealthState string
+
+const (
+	HealthStateHealthy   HealthState = "healthy"
+	HealthStateUnhealthy HealthState = "unhealthy"
+	// HealthStateUnauthorized is set when a chain data provider rejects the
+	// credentials of the watcher, which does not recover without an operator.
+	HealthStateUnauthorized HealthState = "unauthorized"
+)
+
+// HealthStates lists all the health states, e.g. to reset the health state metric.
+var HealthStates = []HealthState{HealthStateHealthy, HealthStateUnhealthy, HealthStateUnauthorized}
+
 type HealthStore struct {
 	mu sync.RWMutex
 
-	health bool
+	state HealthState
 }
 
 func NewHealthStore() *HealthStore {
@@ -28,17 +43,33 @@ func NewHealthStore() *HealthStore {
 }
 
 func (r *HealthStore) SetHealth(health bool) {
+	if health {
+		r.SetState(HealthStateHealthy)
+	} else {
+		r.SetState(HealthStateUnhealthy)
+	}
+}
+
+func (r *HealthStore) GetHealth() bool {
+	return r.GetState() == HealthStateHealthy
+}
+
+func (r *HealthStore) SetState(state HealthState) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.health = health
+	r.state = state
 }
 
-func (r *HealthStore) GetHealth() bool {
+// GetState returns the health state, unhealthy until the first status check.
+func (r *HealthStore) GetState() HealthState {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.health
+	if r.state == "" {
+		return HealthStateUnhealthy
+	}
+	return r.state
 }
 
 type StatusWatcher struct {
@@ -91,21 +122,28 @@ func (w *StatusWatcher) Start(ctx context.Context) error {
 	}
 }
 
-// checkStatus probes Blockfrost and the Cardano node on every call and updates
-// the health store accordingly. It must run unconditionally on each tick: each
-// probe opens a fresh connection (cardano-cli spawns a new process, Blockfrost a
-// new HTTP request), so an unhealthy state recovers as soon as the upstream comes
-// back. Gating this behind a "last refresh was recent" guard risks freezing the
-// health state permanently if the goroutine is ever starved (e.g. GC pause under
-// memory pressure) for longer than the guard window.
+// checkStatus probes the chain data providers and the Cardano node on every
+// call and updates the health store accordingly. It must run unconditionally on
+// each tick: each probe opens a fresh connection (cardano-cli spawns a new
+// process, the providers a new HTTP request), so an unhealthy state recovers as
+// soon as the upstream comes back. Gating this behind a "last refresh was
+// recent" guard risks freezing the health state permanently if the goroutine is
+// ever starved (e.g. GC pause under memory pressure) for longer than the guard
+// window.
+// An authentication failure is reported as a distinct unauthorized state since
+// retrying does not fix it.
 func (w *StatusWatcher) checkStatus(ctx context.Context) {
 	status, err := w.blockfrost.Health(ctx)
-	if err != nil {
+	unauthorized := errors.Is(err, blockfrost.ErrUnauthorized)
+	switch {
+	case unauthorized:
+		w.logger.ErrorContext(ctx, "Chain data provider rejected the credentials", slog.String("error", err.Error()))
+	case err != nil:
 		w.logger.ErrorContext(ctx, "unable to check blockfrost health", slog.String("error", err.Error()))
 	}
 
-	if !status.IsHealthy {
-		w.logger.ErrorContext(ctx, "Blockfrost API is not responding")
+	if !status.IsHealthy && !unauthorized {
+		w.logger.ErrorContext(ctx, "No chain data provider is responding")
 	}
 
 	isConnected, err := w.checkCardanoNodeConnection(ctx)
@@ -113,13 +151,30 @@ func (w *StatusWatcher) checkStatus(ctx context.Context) {
 		w.logger.ErrorContext(ctx, "Cardano node is not responding", slog.String("error", err.Error()))
 	}
 
-	if !status.IsHealthy || !isConnected {
-		w.metrics.HealthStatus.Set(0)
-		w.healthStore.SetHealth(false)
-	} else {
+	state := HealthStateHealthy
+	switch {
+	case !status.IsHealthy && unauthorized:
+		state = HealthStateUnauthorized
+	case !status.IsHealthy || !isConnected:
+		state = HealthStateUnhealthy
+	}
+	w.setState(state)
+}
+
+func (w *StatusWatcher) setState(state HealthState) {
+	if state == HealthStateHealthy {
 		w.metrics.HealthStatus.Set(1)
-		w.healthStore.SetHealth(true)
+	} else {
+		w.metrics.HealthStatus.Set(0)
+	}
+	for _, s := range HealthStates {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		w.metrics.HealthState.WithLabelValues(string(s)).Set(value)
 	}
+	w.healthStore.SetState(state)
 }
 
 // checkCardanoNodeConnection checks the connection to the cardano-node socket.
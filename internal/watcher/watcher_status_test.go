@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/blockfrost/blockfrost-go"
+	bf "github.com/kilnfi/cardano-validator-watcher/internal/blockfrost"
 	"github.com/kilnfi/cardano-validator-watcher/internal/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	promutils "github.com/prometheus/client_golang/prometheus/testutil"
@@ -134,4 +136,47 @@ cardano_validator_watcher_health_status 0
 		err = promutils.CollectAndCompare(registry, b, metricsUnderTest...)
 		require.NoError(t, err)
 	})
+
+	t.Run("SadPath_WatcherIsUnauthorizedWhenCredentialsAreRejected", func(t *testing.T) {
+		t.Parallel()
+
+		clients := setupClients(t)
+
+		metricsExpectedOutput := `
+# HELP cardano_validator_watcher_health_state Health state of the Cardano validator watcher, set to 1 for the current state (healthy, unhealthy or unauthorized)
+# TYPE cardano_validator_watcher_health_state gauge
+cardano_validator_watcher_health_state{state="healthy"} 0
+cardano_validator_watcher_health_state{state="unauthorized"} 1
+cardano_validator_watcher_health_state{state="unhealthy"} 0
+`
+		metricsUnderTest := []string{
+			"cardano_validator_watcher_health_state",
+		}
+
+		registry := prometheus.NewRegistry()
+		metrics := metrics.NewCollection()
+		metrics.MustRegister(registry)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			ticker := time.NewTimer(time.Second * 10)
+			<-ticker.C
+			cancel()
+		}()
+
+		// Mock the calls
+		clients.bf.EXPECT().Health(mock.Anything).Return(blockfrost.Health{IsHealthy: false}, fmt.Errorf("provider blockfrost: %w", bf.ErrUnauthorized))
+		clients.cardano.EXPECT().Ping(ctx).Return(nil)
+
+		healthStore := &HealthStore{}
+		watcher := NewStatusWatcher(clients.bf, clients.cardano, metrics, healthStore, DefaultRefreshInterval)
+		err := watcher.Start(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, HealthStateUnauthorized, healthStore.GetState())
+		require.False(t, healthStore.GetHealth())
+		b := bytes.NewBufferString(metricsExpectedOutput)
+		err = promutils.CollectAndCompare(registry, b, metricsUnderTest...)
+		require.NoError(t, err)
+	})
 }
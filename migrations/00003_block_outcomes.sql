@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "block_outcomes" (
+	id           INTEGER NOT NULL,
+	epoch        INTEGER NOT NULL,
+	pool_id      TEXT NOT NULL,
+	slot         INTEGER NOT NULL,
+	outcome      TEXT NOT NULL,
+	block_hash   TEXT NOT NULL,
+	block_height INTEGER NOT NULL,
+	slot_leader  TEXT NOT NULL,
+	recorded_at  TIMESTAMP NOT NULL,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("pool_id","slot")
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS "idx_block_outcomes_epoch_pool" ON "block_outcomes" ("epoch", "pool_id");
+-- +goose StatementEnd
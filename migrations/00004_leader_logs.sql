@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "leader_logs" (
+	id                 INTEGER NOT NULL,
+	epoch              INTEGER NOT NULL,
+	pool_id            TEXT NOT NULL,
+	epoch_nonce        TEXT NOT NULL,
+	consensus          TEXT NOT NULL,
+	epoch_slots        INTEGER NOT NULL,
+	epoch_slots_ideal  REAL NOT NULL,
+	max_performance    REAL NOT NULL,
+	sigma              REAL NOT NULL,
+	active_stake       INTEGER NOT NULL,
+	total_active_stake INTEGER NOT NULL,
+	assigned_slots     TEXT NOT NULL,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("epoch","pool_id")
+);
+-- +goose StatementEnd
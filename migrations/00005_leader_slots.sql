@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "leader_slots" (
+	epoch         INTEGER NOT NULL,
+	pool_id       TEXT NOT NULL,
+	slot          INTEGER NOT NULL,
+	slot_in_epoch INTEGER NOT NULL DEFAULT 0,
+	at            TIMESTAMP NULL,
+	PRIMARY KEY("pool_id","slot")
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS "idx_leader_slots_epoch_pool_slot" ON "leader_slots" ("epoch", "pool_id", "slot");
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT OR IGNORE INTO leader_slots (epoch, pool_id, slot, slot_in_epoch, at)
+SELECT l.epoch, l.pool_id, json_extract(j.value, '$.slot'), COALESCE(json_extract(j.value, '$.slotInEpoch'), 0), json_extract(j.value, '$.at')
+FROM leader_logs l, json_each(l.assigned_slots) j;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT OR IGNORE INTO leader_slots (epoch, pool_id, slot)
+SELECT s.epoch, s.pool_id, j.value
+FROM slots s, json_each(s.slots) j;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE slots DROP COLUMN slots;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE leader_logs DROP COLUMN assigned_slots;
+-- +goose StatementEnd
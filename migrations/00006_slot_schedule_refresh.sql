@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "slot_schedule_refresh" (
+	pool_id    TEXT NOT NULL,
+	epoch      INTEGER NOT NULL,
+	status     TEXT NOT NULL,
+	attempts   INTEGER NOT NULL,
+	last_error TEXT NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY("pool_id","epoch")
+);
+-- +goose StatementEnd
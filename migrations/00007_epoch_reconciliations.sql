@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "epoch_reconciliations" (
+	epoch            INTEGER NOT NULL,
+	pool_id          TEXT NOT NULL,
+	scheduled_slots  INTEGER NOT NULL,
+	on_chain_blocks  INTEGER NOT NULL,
+	validated_blocks INTEGER NOT NULL,
+	unknown_blocks   TEXT NOT NULL,
+	unrecorded_slots TEXT NOT NULL,
+	reconciled_at    TIMESTAMP NOT NULL,
+	PRIMARY KEY("epoch","pool_id")
+);
+-- +goose StatementEnd
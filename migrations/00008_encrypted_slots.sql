@@ -0,0 +1,4 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "slots" ADD COLUMN encrypted_slots BLOB NULL;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "pool_params" (
+	pool_id    TEXT NOT NULL,
+	params     TEXT NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY("pool_id")
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "pool_param_changes" (
+	id          INTEGER NOT NULL,
+	pool_id     TEXT NOT NULL,
+	param       TEXT NOT NULL,
+	before      TEXT NOT NULL,
+	after       TEXT NOT NULL,
+	detected_at TIMESTAMP NOT NULL,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS "idx_pool_param_changes_pool" ON "pool_param_changes" ("pool_id", "detected_at");
+-- +goose StatementEnd
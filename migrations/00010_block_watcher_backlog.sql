@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "block_watcher_backlog" (
+	pool_id    TEXT NOT NULL,
+	epoch      INTEGER NOT NULL,
+	first_slot INTEGER NOT NULL,
+	PRIMARY KEY("pool_id")
+);
+-- +goose StatementEnd
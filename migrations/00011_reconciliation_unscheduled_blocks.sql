@@ -0,0 +1,7 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "epoch_reconciliations" RENAME COLUMN unknown_blocks TO unrecorded_blocks;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE "epoch_reconciliations" ADD COLUMN unscheduled_blocks TEXT NOT NULL DEFAULT '[]';
+-- +goose StatementEnd